@@ -1,42 +1,88 @@
 package cli
 
 import (
+	"flag"
 	"fmt"
 	yaml "gopkg.in/yaml.v2"
 	"io/ioutil"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/eleztian/blog-generator/config"
 	"github.com/eleztian/blog-generator/datasource"
 	"github.com/eleztian/blog-generator/generator"
 )
 
-// Run runs the application
-func Run() {
+// Run runs the application and returns the configuration of the generated site
+func Run() *generator.SiteConfig {
+	strict := flag.Bool("strict", false, "fail the generation on warnings")
+	includeDrafts := flag.Bool("include-drafts", false, "generate posts with status draft")
+	dev := flag.Bool("dev", false, "link the sources of posts for editing, the site isn't pushed")
+	flag.Parse()
 	cfg, err := readConfig()
 	if err != nil {
 		log.Fatal("There was an error while reading the configuration file: ", err)
 	}
-	ds := datasource.New()
+	if *strict {
+		cfg.Generator.Strict = true
+	}
+	if *includeDrafts {
+		cfg.Generator.IncludeDrafts = true
+	}
+	if *dev {
+		cfg.Generator.Dev = true
+	}
+	// the lock also keeps concurrent runs from fetching and pushing at once
+	unlock, err := generator.LockDestination(cfg.Generator.Dest, time.Duration(cfg.Generator.LockWait)*time.Second)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer unlock()
+	ds := datasource.New(cfg.Generator.Ignore, cfg.Generator.Glob)
 	dirs, err := ds.Fetch(cfg.Generator.Repo, cfg.Generator.Tmp)
 
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	g := generator.New(&generator.SiteConfig{
+	site := &generator.SiteConfig{
 		Sources:     dirs,
+		SourceRoot:  cfg.Generator.Tmp,
 		Destination: cfg.Generator.Dest,
 		Config:      cfg,
-	})
+		LockHeld:    true,
+	}
+	g := generator.New(site)
 
 	err = g.Generate()
 	if err != nil {
 		log.Fatal(err)
 	}
+	// dev builds link to local files and must not be published
+	if cfg.Generator.Dev {
+		return site
+	}
 	if err = datasource.Push(cfg.Generator.Dest, cfg.Generator.SiteRepo); err != nil {
 		log.Fatal(err)
 	}
+	return site
+}
+
+// New creates the scaffold of a new post with the title given in args
+func New(args []string) {
+	fs := flag.NewFlagSet("new", flag.ExitOnError)
+	dir := fs.String("dir", ".", "folder of the blog repository the post is created in")
+	fs.Parse(args)
+	cfg, err := readConfig()
+	if err != nil {
+		log.Fatal("There was an error while reading the configuration file: ", err)
+	}
+	path, err := generator.CreatePost(*dir, strings.Join(fs.Args(), " "), cfg, time.Now())
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Created post", path)
 }
 
 func readConfig() (*config.Config, error) {
@@ -51,35 +97,20 @@ func readConfig() (*config.Config, error) {
 	if cfg.Generator.Repo == "" {
 		return nil, fmt.Errorf("Please provide a repository URL, e.g.: https://github.com/zupzup/blog")
 	}
-	if cfg.Generator.Tmp == "" {
-		cfg.Generator.Tmp = "tmp"
-	}
-	if cfg.Generator.Dest == "" {
-		cfg.Generator.Dest = "www"
-	}
-	if cfg.Generator.NPG == 0 {
-		cfg.Generator.NPG = 10
-	}
 	if cfg.Blog.URL == "" {
 		return nil, fmt.Errorf("Please provide a Blog URL, e.g.: https://www.zupzup.org")
 	}
-	if cfg.Blog.Language == "" {
-		cfg.Blog.Language = "en-us"
-	}
 	if cfg.Blog.Description == "" {
 		return nil, fmt.Errorf("Please provide a Blog Description, e.g.: A blog about Go, JavaScript, Open Source and Programming in General")
 	}
-	if cfg.Blog.Dateformat == "" {
-		cfg.Blog.Dateformat = "02.01.2006"
-	}
 	if cfg.Blog.Title == "" {
 		return nil, fmt.Errorf("Please provide a Blog Title, e.g.: zupzup")
 	}
 	if cfg.Blog.Author == "" {
 		return nil, fmt.Errorf("Please provide a Blog author, e.g.: Mario Zupan")
 	}
-	if cfg.Blog.Frontpageposts == 0 {
-		cfg.Blog.Frontpageposts = 10
+	if err := config.Defaults(&cfg); err != nil {
+		return nil, err
 	}
 	return &cfg, nil
 }
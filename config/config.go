@@ -1,27 +1,215 @@
 package config
 
+import "os"
+
 // Config is the configuration of the blog-generator
 type Config struct {
 	Generator struct {
-		Repo     string
-		SiteRepo string
-		Tmp      string
-		Dest     string
-		NPG      int
+		Repo             string
+		SiteRepo         string
+		Tmp              string
+		Dest             string
+		NPG              int
+		Ignore           []string
+		Glob             string
+		DirMode          os.FileMode
+		FileMode         os.FileMode
+		ETags            bool
+		Lint             bool
+		Strict           bool
+		Validate         bool
+		ValidateFeeds    bool
+		DuplicateContent bool
+		BuildInfo        bool
+		SlugHistory      bool
+		IncludeDrafts    bool
+		Dev              bool
+		Editor           string
+		Preview          string
+		SlugHistoryFile  string
+		LockWait         int
+		Files            string
+		Includes         struct {
+			Dir   string
+			Depth int
+		}
+		Images struct {
+			Dir            string
+			Workers        int
+			OpenFiles      int
+			MaxWidth       int
+			MaxPixels      int
+			JPEGQuality    int
+			PNGCompression string
+			Keep           []string
+		}
+		Hooks struct {
+			Commands []string
+			Fatal    bool
+		}
+		Export struct {
+			Enabled  bool
+			WarnSize int64
+		}
 	}
 	Blog struct {
 		URL            string
 		Language       string
 		Description    string
 		Dateformat     string
+		Timezone       string
 		Title          string
 		Author         string
 		Github         string
 		Twitter        string
 		GooglePluse    string
 		Frontpageposts int
-		Statics        struct {
-			Files []struct {
+		Recentposts    int
+		Related        struct {
+			Posts    int
+			Strategy string
+		}
+		Sprite   string
+		AMP      bool
+		Gallery  bool
+		Markdown map[string]bool
+		OGImage  struct {
+			Enabled    bool
+			Font       string
+			Background string
+			Color      string
+		}
+		Anchors struct {
+			Symbol   string
+			Position string
+			Hover    bool
+		}
+		TOC struct {
+			Enabled  bool
+			MinLevel int
+			MaxLevel int
+		}
+		JSON struct {
+			Enabled bool
+			Content string
+		}
+		Trailer      string
+		DefaultImage string
+		Webmention   struct {
+			Endpoint string
+			Pingback string
+			Data     string
+		}
+		Comments struct {
+			Default string
+			Disqus  struct {
+				Shortname string
+			}
+			Utterances struct {
+				Repo      string
+				IssueTerm string
+				Theme     string
+			}
+		}
+		Autolink struct {
+			URLs     bool
+			Mentions string
+		}
+		Tables struct {
+			Responsive bool
+			Class      string
+		}
+		ExternalLinks struct {
+			NewTab    bool
+			Indicator string
+		}
+		License     string
+		Maintenance struct {
+			Enabled  bool
+			Title    string
+			Template string
+		}
+		CleanURLs   bool
+		CriticalCSS struct {
+			Enabled bool
+			File    string
+		}
+		RedirectStubs bool
+		CSP           struct {
+			Policy  map[string]string
+			Headers bool
+		}
+		HostRedirect struct {
+			Format string
+			Hosts  []string
+		}
+		RelativeURLs bool
+		Slugs        struct {
+			CJK string
+		}
+		Paths struct {
+			Tags    string
+			Archive string
+		}
+		Print struct {
+			Enabled bool
+			PDF     bool
+			Command []string
+		}
+		Manifest struct {
+			Icon            string
+			Name            string
+			ShortName       string
+			ThemeColor      string
+			BackgroundColor string
+		}
+		Syntax struct {
+			Theme    string
+			Dark     string
+			DarkMode string
+			Themes   map[string]map[string]string
+		}
+		Feeds struct {
+			Posts    int
+			TTL      int
+			Tags     bool
+			TagPosts int
+			MathML   bool
+			OPML     bool
+			Updated  bool
+		}
+		Excerpt struct {
+			Length      int
+			Ellipsis    string
+			Description int
+			Sources     []string
+		}
+		ReadingTime struct {
+			Rounding  string
+			Threshold float64
+			Below     string
+			// Code makes code blocks count for reading time and word count
+			Code bool
+		}
+		Humans struct {
+			Team []struct {
+				Name     string
+				Role     string
+				Contact  string
+				Location string
+			}
+			Thanks []string
+		}
+		Security struct {
+			Contact    []string
+			Policy     string
+			Encryption string
+			Languages  string
+			Expires    int
+		}
+		Statics struct {
+			Fingerprint bool
+			Files       []struct {
 				Src  string
 				Dest string
 			}
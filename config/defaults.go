@@ -0,0 +1,216 @@
+package config
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Defaults sets the options of cfg which aren't set to their defaults and
+// validates the options, the required blog options aren't checked
+func Defaults(cfg *Config) error {
+	if cfg.Generator.Tmp == "" {
+		cfg.Generator.Tmp = "tmp"
+	}
+	if cfg.Generator.Dest == "" {
+		cfg.Generator.Dest = "www"
+	}
+	if cfg.Generator.NPG == 0 {
+		cfg.Generator.NPG = 10
+	}
+	if cfg.Generator.Preview == "" {
+		cfg.Generator.Preview = cfg.Generator.Dest + "-preview"
+	}
+	if cfg.Generator.SlugHistoryFile == "" {
+		cfg.Generator.SlugHistoryFile = "slughistory.yml"
+	}
+	if cfg.Generator.DirMode == 0 {
+		cfg.Generator.DirMode = 0755
+	}
+	if cfg.Generator.FileMode == 0 {
+		cfg.Generator.FileMode = 0644
+	}
+	if cfg.Generator.Files == "" {
+		cfg.Generator.Files = "files"
+	}
+	if cfg.Generator.Images.Dir == "" {
+		cfg.Generator.Images.Dir = "images"
+	}
+	if cfg.Generator.Editor != "" && !strings.Contains(cfg.Generator.Editor, "{path}") {
+		return fmt.Errorf("editor has to contain {path}, not %s", cfg.Generator.Editor)
+	}
+	if cfg.Generator.Includes.Dir == "" {
+		cfg.Generator.Includes.Dir = "_includes"
+	}
+	if cfg.Generator.Includes.Depth == 0 {
+		cfg.Generator.Includes.Depth = 5
+	}
+	if cfg.Generator.Images.Workers == 0 {
+		cfg.Generator.Images.Workers = runtime.NumCPU()
+	}
+	if cfg.Generator.Images.OpenFiles < 0 {
+		return fmt.Errorf("images openfiles has to be positive, not %d", cfg.Generator.Images.OpenFiles)
+	}
+	if cfg.Generator.Images.MaxPixels == 0 {
+		cfg.Generator.Images.MaxPixels = 50000000
+	}
+	if cfg.Generator.Export.WarnSize == 0 {
+		cfg.Generator.Export.WarnSize = 1 << 20
+	}
+	if cfg.Blog.Language == "" {
+		cfg.Blog.Language = "en-us"
+	}
+	if cfg.Blog.Dateformat == "" {
+		cfg.Blog.Dateformat = "02.01.2006"
+	}
+	if cfg.Blog.Timezone == "" {
+		cfg.Blog.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(cfg.Blog.Timezone); err != nil {
+		return fmt.Errorf("Please provide a valid Blog timezone, e.g.: Europe/Vienna: %v", err)
+	}
+	if cfg.Blog.Frontpageposts == 0 {
+		cfg.Blog.Frontpageposts = 10
+	}
+	if cfg.Blog.Manifest.Name == "" {
+		cfg.Blog.Manifest.Name = cfg.Blog.Title
+	}
+	if cfg.Blog.Syntax.Theme == "" {
+		cfg.Blog.Syntax.Theme = "light"
+	}
+	if cfg.Blog.OGImage.Background == "" {
+		cfg.Blog.OGImage.Background = "#333333"
+	}
+	if cfg.Blog.OGImage.Color == "" {
+		cfg.Blog.OGImage.Color = "#ffffff"
+	}
+	if cfg.Blog.Anchors.Symbol == "" {
+		cfg.Blog.Anchors.Symbol = "#"
+	}
+	if cfg.Blog.Anchors.Position == "" {
+		cfg.Blog.Anchors.Position = "after"
+	}
+	if cfg.Blog.Anchors.Position != "before" && cfg.Blog.Anchors.Position != "after" {
+		return fmt.Errorf("anchors position has to be either before or after, not %s", cfg.Blog.Anchors.Position)
+	}
+	if cfg.Blog.JSON.Content == "" {
+		cfg.Blog.JSON.Content = "html"
+	}
+	switch cfg.Blog.JSON.Content {
+	case "html", "markdown", "both":
+	default:
+		return fmt.Errorf("json content has to be html, markdown or both, not %s", cfg.Blog.JSON.Content)
+	}
+	if cfg.Blog.ReadingTime.Rounding == "" {
+		cfg.Blog.ReadingTime.Rounding = "round"
+	}
+	switch cfg.Blog.ReadingTime.Rounding {
+	case "ceil", "round", "floor":
+	default:
+		return fmt.Errorf("readingtime rounding has to be ceil, round or floor, not %s", cfg.Blog.ReadingTime.Rounding)
+	}
+	if cfg.Blog.ReadingTime.Threshold > 0 && cfg.Blog.ReadingTime.Below == "" {
+		cfg.Blog.ReadingTime.Below = "less than a minute"
+	}
+	if cfg.Blog.Related.Strategy == "" {
+		cfg.Blog.Related.Strategy = "tags"
+	}
+	if cfg.Blog.Related.Strategy != "tags" && cfg.Blog.Related.Strategy != "similarity" {
+		return fmt.Errorf("related strategy has to be either tags or similarity, not %s", cfg.Blog.Related.Strategy)
+	}
+	if q := cfg.Generator.Images.JPEGQuality; q < 0 || q > 100 {
+		return fmt.Errorf("images jpegquality has to be between 1 and 100, not %d", q)
+	}
+	switch cfg.Blog.Comments.Default {
+	case "", "none", "false", "disqus", "utterances":
+	default:
+		return fmt.Errorf("comments default has to be disqus, utterances or none, not %s", cfg.Blog.Comments.Default)
+	}
+	if cfg.Blog.Comments.Utterances.IssueTerm == "" {
+		cfg.Blog.Comments.Utterances.IssueTerm = "pathname"
+	}
+	if cfg.Blog.Comments.Utterances.Theme == "" {
+		cfg.Blog.Comments.Utterances.Theme = "github-light"
+	}
+	switch cfg.Blog.HostRedirect.Format {
+	case "", "netlify", "apache", "nginx":
+	default:
+		return fmt.Errorf("hostredirect format has to be netlify, apache or nginx, not %s", cfg.Blog.HostRedirect.Format)
+	}
+	if cfg.Blog.TOC.MinLevel == 0 {
+		cfg.Blog.TOC.MinLevel = 2
+	}
+	if cfg.Blog.TOC.MaxLevel == 0 {
+		cfg.Blog.TOC.MaxLevel = 3
+	}
+	if cfg.Blog.TOC.MinLevel < 1 || cfg.Blog.TOC.MaxLevel > 6 || cfg.Blog.TOC.MinLevel > cfg.Blog.TOC.MaxLevel {
+		return fmt.Errorf("toc levels have to be between 1 and 6 with minlevel <= maxlevel, not %d and %d", cfg.Blog.TOC.MinLevel, cfg.Blog.TOC.MaxLevel)
+	}
+	if cfg.Blog.Slugs.CJK == "" {
+		cfg.Blog.Slugs.CJK = "keep"
+	}
+	if cfg.Blog.Slugs.CJK != "keep" && cfg.Blog.Slugs.CJK != "romanize" {
+		return fmt.Errorf("slugs cjk has to be either keep or romanize, not %s", cfg.Blog.Slugs.CJK)
+	}
+	for _, path := range []*string{&cfg.Blog.Paths.Tags, &cfg.Blog.Paths.Archive} {
+		*path = strings.Trim(*path, "/")
+		if strings.Contains(*path, "/") || *path == "." || *path == ".." {
+			return fmt.Errorf("paths have to be a single folder name, not %s", *path)
+		}
+	}
+	if cfg.Blog.Paths.Tags == "" {
+		cfg.Blog.Paths.Tags = "tags"
+	}
+	if cfg.Blog.Paths.Archive == "" {
+		cfg.Blog.Paths.Archive = "archive"
+	}
+	if cfg.Blog.Paths.Tags == cfg.Blog.Paths.Archive {
+		return fmt.Errorf("paths of tags and archive have to differ, both are %s", cfg.Blog.Paths.Tags)
+	}
+	if cfg.Blog.Syntax.DarkMode == "" {
+		cfg.Blog.Syntax.DarkMode = "media"
+	}
+	if cfg.Blog.Syntax.DarkMode != "media" && cfg.Blog.Syntax.DarkMode != "selector" {
+		return fmt.Errorf("syntax darkmode has to be either media or selector, not %s", cfg.Blog.Syntax.DarkMode)
+	}
+	if cfg.Blog.Feeds.Posts == 0 {
+		cfg.Blog.Feeds.Posts = 20
+	}
+	if cfg.Blog.Feeds.TagPosts == 0 {
+		cfg.Blog.Feeds.TagPosts = 10
+	}
+	if cfg.Blog.Maintenance.Template == "" {
+		cfg.Blog.Maintenance.Template = "static/maintenance.html"
+	}
+	if cfg.Blog.Maintenance.Title == "" {
+		cfg.Blog.Maintenance.Title = "Coming Soon"
+	}
+	if cfg.Blog.Security.Expires == 0 {
+		cfg.Blog.Security.Expires = 365
+	}
+	if cfg.Blog.Security.Expires < 0 {
+		return fmt.Errorf("security expires has to be a positive number of days, not %d", cfg.Blog.Security.Expires)
+	}
+	if cfg.Blog.Recentposts == 0 {
+		cfg.Blog.Recentposts = 5
+	}
+	if cfg.Blog.Excerpt.Description == 0 {
+		cfg.Blog.Excerpt.Description = 160
+	}
+	if cfg.Blog.Excerpt.Length == 0 {
+		cfg.Blog.Excerpt.Length = 200
+	}
+	if cfg.Blog.Excerpt.Ellipsis == "" {
+		cfg.Blog.Excerpt.Ellipsis = "…"
+	}
+	if len(cfg.Blog.Excerpt.Sources) == 0 {
+		cfg.Blog.Excerpt.Sources = []string{"short"}
+	}
+	for _, source := range cfg.Blog.Excerpt.Sources {
+		if source != "short" && source != "more" && source != "paragraph" {
+			return fmt.Errorf("excerpt sources have to be short, more or paragraph, not %s", source)
+		}
+	}
+	return nil
+}
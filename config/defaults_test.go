@@ -0,0 +1,13 @@
+package config
+
+import "testing"
+
+func TestDefaultsJPEGQuality(t *testing.T) {
+	for _, quality := range []int{-1, 101} {
+		cfg := &Config{}
+		cfg.Generator.Images.JPEGQuality = quality
+		if err := Defaults(cfg); err == nil {
+			t.Errorf("jpegquality %d was accepted", quality)
+		}
+	}
+}
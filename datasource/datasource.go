@@ -5,7 +5,9 @@ type DataSource interface {
 	Fetch(from, to string) ([]string, error)
 }
 
-// New creates a new GitDataSource
-func New() DataSource {
-	return &GitDataSource{}
+// New creates a new GitDataSource, paths matching one of the gitignore style
+// ignore patterns are skipped. With a glob the matching markdown files are
+// the posts instead of the folders with a post.md.
+func New(ignore []string, glob string) DataSource {
+	return &GitDataSource{Ignore: ignore, Glob: glob}
 }
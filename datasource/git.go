@@ -5,22 +5,28 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
 // GitDataSource is the git data source object
-type GitDataSource struct{}
+type GitDataSource struct {
+	Ignore []string
+	// Glob selects the markdown files which are posts, without it every
+	// folder with a post.md is a post
+	Glob string
+}
 
 func Push(from, to string) error {
 	fmt.Printf("Pushing data from %s into %s...\n", from, to)
 	//if err := createFolderIfNotExist(to); err != nil {
 	//	return err
 	//}
-	if err := pushRepo(from,to); err != nil {
+	if err := pushRepo(from, to); err != nil {
 		return err
 	}
 	fmt.Print("Pushing complete.\n")
-	return  nil
+	return nil
 }
 
 // Fetch creates the output folder, clears it and clones the repository there
@@ -35,7 +41,20 @@ func (ds *GitDataSource) Fetch(from, to string) ([]string, error) {
 	if err := cloneRepo(to, from); err != nil {
 		return nil, err
 	}
-	dirs, err := getContentFolders(to, ".md")
+	patterns, err := readIgnoreFile(to)
+	if err != nil {
+		return nil, err
+	}
+	ignore, err := newIgnoreMatcher(append(append([]string{}, ds.Ignore...), patterns...))
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	if ds.Glob != "" {
+		dirs, err = getContentFiles(to, ds.Glob, ignore)
+	} else {
+		dirs, err = getContentFolders(to, "post.md", ignore)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -133,19 +152,65 @@ func cloneRepo(path, repositoryURL string) error {
 	return nil
 }
 
-func getContentFolders(path string, fileType string) ([]string, error) {
+// getContentFolders returns every folder below root which contains fileName
+// and isn't ignored
+func getContentFolders(root string, fileName string, ignore *ignoreMatcher) ([]string, error) {
 	var result []string
-	err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+	err := walkContent(root, ignore, func(path, rel string, info os.FileInfo) {
+		if !info.IsDir() && info.Name() == fileName {
+			result = append(result, filepath.Dir(path))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// getContentFiles returns every file below root which matches the glob and
+// isn't ignored
+func getContentFiles(root, glob string, ignore *ignoreMatcher) ([]string, error) {
+	re, err := regexp.Compile("^" + globToRegexp(strings.TrimPrefix(glob, "/")) + "$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob %s: %v", glob, err)
+	}
+	var result []string
+	err = walkContent(root, ignore, func(path, rel string, info os.FileInfo) {
+		if !info.IsDir() && re.MatchString(rel) {
+			result = append(result, path)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// walkContent calls fn for every path below root which isn't ignored, with
+// the slash separated path relative to root
+func walkContent(root string, ignore *ignoreMatcher, fn func(path, rel string, info os.FileInfo)) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
-		if !info.IsDir() && strings.HasSuffix(info.Name(), fileType) {
-			result = append(result, path)
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && rel == ".git" {
+			return filepath.SkipDir
 		}
+		if rel != "." && ignore.Match(filepath.ToSlash(rel), info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		fn(path, filepath.ToSlash(rel), info)
 		return err
 	})
 	if err != nil {
-		return nil, fmt.Errorf("file not exit %s: %v", path, err)
+		return fmt.Errorf("file not exit %s: %v", root, err)
 	}
-	return result, nil
+	return nil
 }
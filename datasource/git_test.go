@@ -0,0 +1,23 @@
+package datasource
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetContentFilesGlob(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, "README.md", "content/notes.md", "content/2021/hello.md", "content/2021/hello.txt", "drafts/other.md", ".git/content/x.md")
+	ignore, err := newIgnoreMatcher(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := getContentFiles(root, "/content/**/*.md", ignore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"content/2021/hello.md", "content/notes.md"}
+	if got := relPaths(t, root, files); !reflect.DeepEqual(got, want) {
+		t.Errorf("glob matched %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package datasource
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// writeFiles writes empty files by their slash separated path below root
+func writeFiles(t *testing.T, root string, names ...string) {
+	for _, name := range names {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// relPaths returns the sorted slash separated paths relative to root
+func relPaths(t *testing.T, root string, paths []string) []string {
+	result := []string{}
+	for _, path := range paths {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, filepath.ToSlash(rel))
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestIgnoredFolders(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, "a/post.md", "tmp/b/post.md", "drafts/c/post.md", "drafts/keep/post.md")
+	if err := ioutil.WriteFile(filepath.Join(root, ignoreFile), []byte("# drafts\ndrafts/*\n!drafts/keep\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	patterns, err := readIgnoreFile(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ignore, err := newIgnoreMatcher(append([]string{"tmp/"}, patterns...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	folders, err := getContentFolders(root, "post.md", ignore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := relPaths(t, root, folders), []string{"a", "drafts/keep"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("content folders are %v, want %v", got, want)
+	}
+}
+
+func TestIgnoredFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, "posts/one.md", "posts/one.md~", "posts/two.md", "posts/nested/three.md", "posts/nested/skip.md")
+	ignore, err := newIgnoreMatcher([]string{"*~", "posts/nested/*.md", "!three.md"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := getContentFiles(root, "posts/**/*.md", ignore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"posts/nested/three.md", "posts/one.md", "posts/two.md"}
+	if got := relPaths(t, root, files); !reflect.DeepEqual(got, want) {
+		t.Errorf("content files are %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// getAliasFile returns the file of the redirect stub of an alias path
+func getAliasFile(destination, alias string) string {
+	clean := strings.Trim(path.Clean("/"+alias), "/")
+	if path.Ext(clean) == ".html" {
+		return filepath.Join(destination, filepath.FromSlash(clean))
+	}
+	return filepath.Join(destination, filepath.FromSlash(clean), "index.html")
+}
+
+// writeAliases writes a redirect stub to the post for every alias and old
+// slug, it must run after all pages are generated as aliases must not replace
+// real pages
+func writeAliases(posts []*Post, destination string, dirMode, fileMode os.FileMode) error {
+	for _, post := range posts {
+		for _, alias := range post.Meta.Aliases {
+			if strings.Trim(alias, "/") == "" {
+				return fmt.Errorf("alias %q of %s is the root page", alias, post.Dir)
+			}
+			filePath := getAliasFile(destination, alias)
+			if _, err := os.Stat(filePath); err == nil {
+				return fmt.Errorf("alias %q of %s collides with the generated page %s", alias, post.Dir, filePath)
+			}
+			if err := os.MkdirAll(filepath.Dir(filePath), dirMode); err != nil {
+				return fmt.Errorf("error creating directory at %s: %v", filepath.Dir(filePath), err)
+			}
+			if err := writeRedirectStub(filePath, post.URL, fileMode); err != nil {
+				return err
+			}
+		}
+		for _, slug := range post.OldSlugs {
+			filePath := getAliasFile(destination, slug)
+			// old slugs may be taken by other pages by now
+			if _, err := os.Stat(filePath); err == nil {
+				fmt.Printf("\tWarning: old slug %s of %s is used by another page\n", slug, post.Dir)
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(filePath), dirMode); err != nil {
+				return fmt.Errorf("error creating directory at %s: %v", filepath.Dir(filePath), err)
+			}
+			if err := writeRedirectStub(filePath, post.URL, fileMode); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
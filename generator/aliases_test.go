@@ -0,0 +1,39 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAliases(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"current/post.md": testPost("Current", "01.02.2021", "Current.", "aliases: [old-name, /2019/01/old/, legacy.html]"),
+	}).build()
+	target := "https://example.com/current/"
+	for _, name := range []string{"old-name/index.html", "2019/01/old/index.html", "legacy.html"} {
+		stub := site.read(name)
+		for _, want := range []string{
+			`<link rel="canonical" href="` + target + `">`,
+			`<meta http-equiv="refresh" content="0; url=` + target + `">`,
+			`<script>location.replace("` + target + `")</script>`,
+		} {
+			if !strings.Contains(stub, want) {
+				t.Errorf("redirect stub %s is missing %s", name, want)
+			}
+		}
+	}
+}
+
+func TestAliasCollision(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"current/post.md": testPost("Current", "01.02.2021", "Current.", "aliases: [tags/]"),
+	})
+	var err error
+	captureStdout(t, func() { err = site.generate() })
+	if err == nil || !strings.Contains(err.Error(), "collides with the generated page") {
+		t.Errorf("alias of a real page gives error %v", err)
+	}
+	if !strings.Contains(site.read("tags/index.html"), "<html") || strings.Contains(site.read("tags/index.html"), "Redirecting") {
+		t.Error("alias replaced a real page")
+	}
+}
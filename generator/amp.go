@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"image"
+	// image decoders used to determine the size of amp-img elements
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// ampDisallowed are elements which can't be converted to valid AMP
+var ampDisallowed = []string{"script", "iframe", "frame", "frameset", "object", "embed", "form", "input", "textarea", "select", "button", "style", "link", "base", "param", "applet"}
+
+// convertToAMP converts the html of a post to AMP, <img> elements become
+// <amp-img> elements with their size read from the image files
+func convertToAMP(post *Post) (template.HTML, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(post.HTML))
+	if err != nil {
+		return "", fmt.Errorf("error while parsing html: %v", err)
+	}
+	for _, tag := range ampDisallowed {
+		if doc.Find(tag).Length() > 0 {
+			return "", fmt.Errorf("post contains a <%s> element", tag)
+		}
+	}
+	// attributes like style and event handlers are not allowed in AMP
+	doc.Find("*").Each(func(i int, s *goquery.Selection) {
+		node := s.Nodes[0]
+		attrs := make([]html.Attribute, 0, len(node.Attr))
+		for _, attr := range node.Attr {
+			if attr.Key != "style" && !strings.HasPrefix(attr.Key, "on") {
+				attrs = append(attrs, attr)
+			}
+		}
+		node.Attr = attrs
+	})
+	var convErr error
+	doc.Find("img").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		src, _ := s.Attr("src")
+		width, height, err := getAMPImageSize(post, s)
+		if err != nil {
+			convErr = fmt.Errorf("error getting size of image %s: %v", src, err)
+			return false
+		}
+		alt, _ := s.Attr("alt")
+		ampImg := fmt.Sprintf(`<amp-img src="%s" alt="%s" width="%d" height="%d" layout="responsive"></amp-img>`,
+			template.HTMLEscapeString(getAMPImageSrc(post, src)), template.HTMLEscapeString(alt), width, height)
+		s.ReplaceWithHtml(ampImg)
+		return true
+	})
+	if convErr != nil {
+		return "", convErr
+	}
+	content, err := doc.Find("body").Html()
+	if err != nil {
+		return "", fmt.Errorf("error while generating html: %v", err)
+	}
+	return template.HTML(content), nil
+}
+
+func getAMPImageSize(post *Post, s *goquery.Selection) (int, int, error) {
+	width, werr := strconv.Atoi(s.AttrOr("width", ""))
+	height, herr := strconv.Atoi(s.AttrOr("height", ""))
+	if werr == nil && herr == nil {
+		return width, height, nil
+	}
+	src := s.AttrOr("src", "")
+	path := getImageFile(post, src)
+	if path == "" {
+		return 0, 0, fmt.Errorf("size is unknown, set width and height")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// getAMPImageSrc makes image paths relative to the post work from the amp/ page
+func getAMPImageSrc(post *Post, src string) string {
+	if strings.Contains(src, "://") || strings.HasPrefix(src, "/") {
+		return src
+	}
+	return post.Path + src
+}
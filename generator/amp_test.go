@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestConvertToAMPRemovesAttributes(t *testing.T) {
+	post := &Post{Path: "/post/", HTML: []byte(`<p style="color:red" onclick="x()" onmouseover="y()" class="lead" id="p">text</p>`)}
+	result, err := convertToAMP(post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, attr := range []string{"style", "onclick", "onmouseover"} {
+		if strings.Contains(string(result), attr+"=") {
+			t.Errorf("%s wasn't removed: %s", attr, result)
+		}
+	}
+	if !strings.Contains(string(result), `class="lead"`) || !strings.Contains(string(result), `id="p"`) {
+		t.Errorf("allowed attributes were removed: %s", result)
+	}
+}
+
+func TestConvertToAMPImages(t *testing.T) {
+	post := &Post{Path: "/post/", HTML: []byte(`<p><img src="cat.png" alt="A cat" width="40" height="30" style="border:0"></p>`)}
+	result, err := convertToAMP(post)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<amp-img src="/post/cat.png" alt="A cat" width="40" height="30" layout="responsive"></amp-img>`
+	if !strings.Contains(string(result), want) {
+		t.Errorf("got %s, want it to contain %s", result, want)
+	}
+	if strings.Contains(string(result), "<img") {
+		t.Errorf("img wasn't replaced: %s", result)
+	}
+}
+
+func TestConvertToAMPDisallowed(t *testing.T) {
+	post := &Post{HTML: []byte(`<p>text</p><iframe src="https://example.com"></iframe>`)}
+	if _, err := convertToAMP(post); err == nil {
+		t.Error("expected an error for an iframe")
+	}
+}
+
+func TestAMPLinks(t *testing.T) {
+	cfg := newTestConfig(t, func(cfg *config.Config) { cfg.Blog.AMP = true })
+	site := newTestSite(t, cfg, map[string]string{
+		"first/post.md": testPost("First", "01.02.2021", `<p style="color:red" onclick="x()">Hi</p>`+"\n\n"+`<img src="/cat.png" alt="A cat" width="40" height="30">`, "short: first"),
+	}).build()
+	page := site.read("first/index.html")
+	if !strings.Contains(page, `<link rel="amphtml" href="https://example.com/first/amp/">`) {
+		t.Errorf("post doesn't link its AMP version:\n%s", page)
+	}
+	amp := site.read("first/amp/index.html")
+	if !strings.Contains(amp, `<link rel="canonical" href="https://example.com/first/">`) {
+		t.Errorf("AMP version doesn't link the post as canonical:\n%s", amp)
+	}
+	if strings.Contains(amp, "onclick") || strings.Contains(amp, `style="color`) {
+		t.Errorf("AMP version contains disallowed attributes:\n%s", amp)
+	}
+	if !strings.Contains(amp, `<amp-img src="/cat.png"`) {
+		t.Errorf("AMP version has no amp-img:\n%s", amp)
+	}
+}
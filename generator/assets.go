@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+// fingerprintExts are the statics which get a content hash in their name
+var fingerprintExts = map[string]bool{".css": true, ".js": true}
+
+// AssetManifest maps the destination of a static file to its fingerprinted
+// destination, e.g. css/vec.css to css/vec.1a2b3c4d.css
+type AssetManifest map[string]string
+
+// newAssetManifest fingerprints the css and js statics if enabled in the config
+func newAssetManifest(cfg *config.Config) (AssetManifest, error) {
+	manifest := AssetManifest{}
+	if !cfg.Blog.Statics.Fingerprint {
+		return manifest, nil
+	}
+	for _, static := range cfg.Blog.Statics.Files {
+		ext := filepath.Ext(static.Dest)
+		if !fingerprintExts[ext] {
+			continue
+		}
+		hash, err := hashFile(static.Src)
+		if err != nil {
+			return nil, err
+		}
+		dest := filepath.ToSlash(filepath.Clean(static.Dest))
+		manifest[dest] = fmt.Sprintf("%s.%s%s", dest[:len(dest)-len(ext)], hash[:8], ext)
+	}
+	return manifest, nil
+}
+
+// Destination returns the fingerprinted destination of a static file
+func (m AssetManifest) Destination(dest string) string {
+	if fingerprinted, ok := m[filepath.ToSlash(filepath.Clean(dest))]; ok {
+		return fingerprinted
+	}
+	return dest
+}
+
+// Asset resolves a path like /css/vec.css to its fingerprinted version, unknown
+// paths are returned unchanged
+func (m AssetManifest) Asset(path string) string {
+	fingerprinted, ok := m[strings.TrimPrefix(path, "/")]
+	if !ok {
+		return path
+	}
+	if strings.HasPrefix(path, "/") {
+		return "/" + fingerprinted
+	}
+	return fingerprinted
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading file %s: %v", path, err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("error reading file %s: %v", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
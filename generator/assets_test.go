@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestAssetFingerprint(t *testing.T) {
+	src := filepath.Join("static", "css", "vec.css")
+	css, err := ioutil.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(css)
+	fingerprinted := "css/vec." + hex.EncodeToString(sum[:])[:8] + ".css"
+	cfg := newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.Statics.Fingerprint = true
+		cfg.Blog.Statics.Files = append(cfg.Blog.Statics.Files, struct {
+			Src  string
+			Dest string
+		}{src, "css/vec.css"})
+	})
+	assets, err := newAssetManifest(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := assets.Asset("/css/vec.css"); got != "/"+fingerprinted {
+		t.Errorf("asset of /css/vec.css is %s, want /%s", got, fingerprinted)
+	}
+	if got := assets.Asset("/css/other.css"); got != "/css/other.css" {
+		t.Errorf("unknown asset resolved to %s", got)
+	}
+
+	site := newTestSite(t, cfg, map[string]string{
+		"post/post.md": testPost("Post", "01.02.2021", "The post."),
+	}).build()
+	if site.read(fingerprinted) != string(css) {
+		t.Errorf("%s isn't a copy of %s", fingerprinted, src)
+	}
+	if site.exists("css/vec.css") {
+		t.Error("static was also copied without the hash")
+	}
+	if page := site.read("post/index.html"); !strings.Contains(page, fingerprinted+`"`) {
+		t.Error("page doesn't link the fingerprinted css")
+	}
+}
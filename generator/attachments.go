@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Attachment is a downloadable file of a post
+type Attachment struct {
+	Name string
+	Link string
+	Size int64
+}
+
+// HumanSize returns the size of the attachment, e.g. 1.5 MB
+func (a *Attachment) HumanSize() string {
+	const unit = 1024
+	if a.Size < unit {
+		return fmt.Sprintf("%d B", a.Size)
+	}
+	div, exp := int64(unit), 0
+	for n := a.Size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(a.Size)/float64(div), "KMGTPE"[exp])
+}
+
+// getAttachments returns the files in the folder dir of a post, which are
+// linked below the post path. A missing folder has no attachments.
+func getAttachments(path, dir, postPath string) (string, []*Attachment, error) {
+	if dir == "" {
+		return "", nil, nil
+	}
+	dirPath := filepath.Join(path, dir)
+	files, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil, nil
+		}
+		return "", nil, fmt.Errorf("error while reading folder %s: %v", dirPath, err)
+	}
+	attachments := []*Attachment{}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		attachments = append(attachments, &Attachment{
+			Name: file.Name(),
+			Link: postPath + filepath.ToSlash(dir) + "/" + file.Name(),
+			Size: file.Size(),
+		})
+	}
+	return dirPath, attachments, nil
+}
+
+// copyAttachments copies the attachments of a post to its folder
+func copyAttachments(post *Post, staticPath string, writer *IndexWriter) error {
+	dst := filepath.Join(staticPath, filepath.Base(post.FilesDir))
+	if err := createFolderIfNotExist(dst, writer.DirMode); err != nil {
+		return err
+	}
+	for _, attachment := range post.Attachments {
+		if err := copyFile(filepath.Join(post.FilesDir, attachment.Name), filepath.Join(dst, attachment.Name), writer.FileMode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
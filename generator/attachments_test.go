@@ -0,0 +1,43 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAttachments(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"first/post.md":          testPost("First Post", "01.02.2021", "The first post."),
+		"first/files/notes.txt":  "twelve bytes",
+		"first/files/slides.pdf": strings.Repeat("x", 1536),
+	}).build()
+	if got := site.read("first/files/notes.txt"); got != "twelve bytes" {
+		t.Errorf("attachment was copied as %q", got)
+	}
+	if !site.exists("first/files/slides.pdf") {
+		t.Error("second attachment wasn't copied")
+	}
+	page := site.read("first/index.html")
+	for _, want := range []string{
+		`<a href="/first/files/notes.txt" download>notes.txt</a> (12 B)`,
+		`<a href="/first/files/slides.pdf" download>slides.pdf</a> (1.5 KB)`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("post doesn't list %s", want)
+		}
+	}
+}
+
+func TestAttachmentHumanSize(t *testing.T) {
+	tests := map[int64]string{
+		0:           "0 B",
+		1023:        "1023 B",
+		1024:        "1.0 KB",
+		5 * 1 << 20: "5.0 MB",
+	}
+	for size, want := range tests {
+		if got := (&Attachment{Size: size}).HumanSize(); got != want {
+			t.Errorf("HumanSize of %d = %s, want %s", size, got, want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"html/template"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	// bareURL matches http(s) URLs in text, trailing punctuation is trimmed
+	bareURL = regexp.MustCompile(`https?://[^\s<>"]+`)
+	// mention matches @handle not preceded by letters, e.g. of email addresses
+	mention = regexp.MustCompile(`(^|[^\w@./])@(\w{1,39})\b`)
+)
+
+// autolinkSkipped are the elements whose text is never linked
+var autolinkSkipped = map[atom.Atom]bool{
+	atom.A: true, atom.Code: true, atom.Pre: true, atom.Kbd: true, atom.Samp: true,
+	atom.Script: true, atom.Style: true, atom.Textarea: true,
+}
+
+// AutolinkOptions configure the linking of bare URLs and @mentions in the
+// text of posts. Mentions link to Mentions with {handle} replaced, mentions
+// aren't linked if it is empty.
+type AutolinkOptions struct {
+	URLs     bool
+	Mentions string
+}
+
+// newAutolinks returns the transform linking bare URLs and mentions outside of
+// links and code
+func newAutolinks(opts AutolinkOptions) Transform {
+	return func(post *Post, doc *goquery.Document) error {
+		if !opts.URLs && opts.Mentions == "" {
+			return nil
+		}
+		for _, node := range doc.Nodes {
+			autolinkNode(node, opts)
+		}
+		return nil
+	}
+}
+
+func autolinkNode(n *html.Node, opts AutolinkOptions) {
+	if n.Type == html.ElementNode && autolinkSkipped[n.DataAtom] {
+		return
+	}
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.TextNode {
+			autolinkText(c, opts)
+		} else {
+			autolinkNode(c, opts)
+		}
+		c = next
+	}
+}
+
+// autolinkText replaces the text node n by text and links if it contains
+// URLs or mentions
+func autolinkText(n *html.Node, opts AutolinkOptions) {
+	linked, ok := linkText(n.Data, opts)
+	if !ok {
+		return
+	}
+	nodes, err := html.ParseFragment(strings.NewReader(linked), n.Parent)
+	if err != nil {
+		return
+	}
+	for _, node := range nodes {
+		n.Parent.InsertBefore(node, n)
+	}
+	n.Parent.RemoveChild(n)
+}
+
+// linkText returns the escaped html of text with links for its URLs and
+// mentions, false if there are none
+func linkText(text string, opts AutolinkOptions) (string, bool) {
+	var b strings.Builder
+	found := false
+	last := 0
+	write := func(start, end int, href, label string) {
+		b.WriteString(template.HTMLEscapeString(text[last:start]))
+		b.WriteString(`<a href="` + template.HTMLEscapeString(href) + `">` + template.HTMLEscapeString(label) + `</a>`)
+		last = end
+		found = true
+	}
+	type match struct{ start, end int }
+	matches := []match{}
+	if opts.URLs {
+		for _, loc := range bareURL.FindAllStringIndex(text, -1) {
+			end := loc[0] + len(strings.TrimRight(text[loc[0]:loc[1]], ".,;:!?)'"))
+			matches = append(matches, match{loc[0], end})
+		}
+	}
+	if opts.Mentions != "" {
+		for _, loc := range mention.FindAllStringSubmatchIndex(text, -1) {
+			// the mention starts at the @ before the handle
+			matches = append(matches, match{loc[4] - 1, loc[5]})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].start < matches[j].start })
+	for _, m := range matches {
+		if m.start < last {
+			// mentions inside of URLs, e.g. https://host/@user
+			continue
+		}
+		label := text[m.start:m.end]
+		if strings.HasPrefix(label, "@") {
+			write(m.start, m.end, strings.Replace(opts.Mentions, "{handle}", label[1:], -1), label)
+		} else {
+			write(m.start, m.end, label, label)
+		}
+	}
+	if !found {
+		return "", false
+	}
+	b.WriteString(template.HTMLEscapeString(text[last:]))
+	return b.String(), true
+}
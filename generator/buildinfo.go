@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"os/exec"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// BuildInfo describes the build which generated the pages. It differs between
+// builds of the same sources, so it is only added with generator.buildinfo.
+type BuildInfo struct {
+	Version string
+	Time    string
+	// Commit is the commit of the source repository, empty if it isn't a git
+	// repository
+	Commit string
+}
+
+// newBuildInfo returns the build info of a build of the sources at root
+func newBuildInfo(root string, now time.Time) *BuildInfo {
+	return &BuildInfo{
+		Version: getGeneratorVersion(),
+		Time:    now.UTC().Format(time.RFC3339),
+		Commit:  getSourceCommit(root),
+	}
+}
+
+// getGeneratorVersion returns the module version of the generator, binaries
+// built from a checkout use the revision they were built from
+func getGeneratorVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+	if version := info.Main.Version; version != "" && version != "(devel)" {
+		return version
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" && len(setting.Value) >= 12 {
+			return setting.Value[:12]
+		}
+	}
+	return "devel"
+}
+
+// getSourceCommit returns the commit checked out at root
+func getSourceCommit(root string) string {
+	if root == "" {
+		return ""
+	}
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = root
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
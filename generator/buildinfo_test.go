@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"os/exec"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestBuildInfo(t *testing.T) {
+	files := map[string]string{"first/post.md": testPost("First Post", "01.02.2021", "The first post.")}
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Generator.BuildInfo = true
+	}), files).build()
+	page := site.read("first/index.html")
+	if !regexp.MustCompile(`<meta name="generator" content="blog-generator [^"]+">`).MatchString(page) {
+		t.Error("post has no generator meta tag")
+	}
+	if !regexp.MustCompile(`<meta name="build-time" content="\d{4}-\d\d-\d\dT\d\d:\d\d:\d\dZ">`).MatchString(page) {
+		t.Error("post has no build time")
+	}
+	if strings.Contains(page, "build-commit") {
+		t.Error("sources outside of a git repository have a build commit")
+	}
+
+	disabled := newTestSite(t, newTestConfig(t, nil), files).build().read("first/index.html")
+	if strings.Contains(disabled, `name="generator"`) || strings.Contains(disabled, "build-time") {
+		t.Error("build info was added without generator.buildinfo")
+	}
+}
+
+func TestBuildInfoCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Generator.BuildInfo = true
+	}), map[string]string{"first/post.md": testPost("First Post", "01.02.2021", "The first post.")})
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=Tester", "-c", "user.email=tester@example.com", "commit", "-qm", "posts"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = site.Root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	commit := getSourceCommit(site.Root)
+	if len(commit) != 40 {
+		t.Fatalf("got commit %q", commit)
+	}
+	if !strings.Contains(site.build().read("first/index.html"), `<meta name="build-commit" content="`+commit+`">`) {
+		t.Error("post doesn't name the commit of the sources")
+	}
+}
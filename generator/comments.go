@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	commentsDisqus     = "disqus"
+	commentsUtterances = "utterances"
+)
+
+// CommentsConfig holds the comment backends of the blog, Default is the
+// backend of posts which don't select one
+type CommentsConfig struct {
+	Default             string
+	DisqusShortname     string
+	UtterancesRepo      string
+	UtterancesIssueTerm string
+	UtterancesTheme     string
+}
+
+// Comments is the data of the comment embed of a post page
+type Comments struct {
+	Backend    string
+	URL        string
+	Identifier string
+	Shortname  string
+	Repo       string
+	IssueTerm  string
+	Theme      string
+}
+
+// getPostComments returns the comment embed of a post, nil if the post or the
+// blog has comments disabled with none or false
+func getPostComments(post *Post, cfg *CommentsConfig) (*Comments, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	backend := strings.ToLower(strings.TrimSpace(post.Meta.Comments))
+	if backend == "" || backend == "true" {
+		backend = cfg.Default
+	}
+	comments := &Comments{Backend: backend, URL: post.URL, Identifier: post.Path}
+	switch backend {
+	case "", "none", "false":
+		return nil, nil
+	case commentsDisqus:
+		if cfg.DisqusShortname == "" {
+			return nil, fmt.Errorf("post %s uses disqus comments but no comments.disqus.shortname is configured", post.Name)
+		}
+		comments.Shortname = cfg.DisqusShortname
+	case commentsUtterances:
+		if cfg.UtterancesRepo == "" {
+			return nil, fmt.Errorf("post %s uses utterances comments but no comments.utterances.repo is configured", post.Name)
+		}
+		comments.Repo = cfg.UtterancesRepo
+		comments.IssueTerm = cfg.UtterancesIssueTerm
+		comments.Theme = cfg.UtterancesTheme
+	default:
+		return nil, fmt.Errorf("comments of post %s have to be disqus, utterances or none, not %s", post.Name, post.Meta.Comments)
+	}
+	return comments, nil
+}
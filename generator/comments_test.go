@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestCommentsEmbed(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.Comments.Default = commentsDisqus
+		cfg.Blog.Comments.Disqus.Shortname = "testblog"
+		cfg.Blog.Comments.Utterances.Repo = "tester/comments"
+	}), map[string]string{
+		"disqus/post.md":     testPost("Disqus", "01.02.2021", "Default comments."),
+		"utterances/post.md": testPost("Utterances", "02.02.2021", "Other comments.", "comments: utterances"),
+		"none/post.md":       testPost("None", "03.02.2021", "No comments.", "comments: none"),
+	}).build()
+	disqus := site.read("disqus/index.html")
+	if !strings.Contains(disqus, `<div id="disqus_thread"></div>`) || !strings.Contains(disqus, `"testblog"`) || !strings.Contains(disqus, `this.page.identifier = "/disqus/";`) {
+		t.Error("default comments don't embed disqus with the shortname and identifier")
+	}
+	want := `<script src="https://utteranc.es/client.js" repo="tester/comments" issue-term="pathname" theme="github-light" crossorigin="anonymous" async></script>`
+	if utterances := site.read("utterances/index.html"); !strings.Contains(utterances, want) || strings.Contains(utterances, "disqus_thread") {
+		t.Error("post selecting utterances doesn't embed only utterances")
+	}
+	if strings.Contains(site.read("none/index.html"), `class="disqus comments"`) {
+		t.Error("post without comments embeds comments")
+	}
+}
+
+func TestCommentsDefaultNone(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.Comments.Default = "none"
+	}), map[string]string{
+		"first/post.md": testPost("First", "01.02.2021", "No comments."),
+	}).build()
+	if strings.Contains(site.read("first/index.html"), `class="disqus comments"`) {
+		t.Error("comments are embedded with the none default")
+	}
+}
+
+func TestCommentsUnknownBackend(t *testing.T) {
+	post := &Post{Name: "first", Meta: &Meta{Comments: "facebook"}}
+	if _, err := getPostComments(post, &CommentsConfig{Default: commentsDisqus, DisqusShortname: "testblog"}); err == nil {
+		t.Error("expected an error for an unknown comments backend")
+	}
+	if _, err := getPostComments(&Post{Name: "first", Meta: &Meta{}}, &CommentsConfig{Default: commentsUtterances}); err == nil {
+		t.Error("expected an error for utterances without a repo")
+	}
+}
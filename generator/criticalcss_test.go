@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestCriticalCSS(t *testing.T) {
+	critical := filepath.Join(t.TempDir(), "critical.css")
+	writeTestFiles(t, filepath.Dir(critical), map[string]string{"critical.css": "body{margin:0}"})
+	files := map[string]string{"post/post.md": testPost("Post", "01.02.2021", "The post.")}
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.CriticalCSS.Enabled = true
+		cfg.Blog.CriticalCSS.File = critical
+	}), files).build()
+	for _, name := range []string{"post/index.html", "blog/index.html"} {
+		page := site.read(name)
+		if !strings.Contains(page, "<style>body{margin:0}</style>") {
+			t.Errorf("%s doesn't inline the critical css", name)
+		}
+		if !strings.Contains(page, `<link rel="stylesheet" href="/css/vec.css" media="print" onload="this.media='all'">`) {
+			t.Errorf("%s doesn't defer vec.css", name)
+		}
+		if !strings.Contains(page, `<noscript><link rel="stylesheet" href="/css/vec.css"></noscript>`) {
+			t.Errorf("%s has no fallback without scripts", name)
+		}
+	}
+
+	disabled := newTestSite(t, newTestConfig(t, nil), files).build().read("post/index.html")
+	if strings.Contains(disabled, "<style>") || strings.Contains(disabled, "onload=") || !strings.Contains(disabled, `<link rel="stylesheet" href="/css/vec.css">`) {
+		t.Error("vec.css isn't linked normally with critical css disabled")
+	}
+}
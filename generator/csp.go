@@ -0,0 +1,170 @@
+package generator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/html"
+)
+
+// headStart matches the start tag of the head, the policy meta tag has to
+// come before the inline content it allows
+var headStart = regexp.MustCompile(`(?i)<head(\s[^>]*)?>`)
+
+// policyMeta matches the policy meta tag inserted by apply, pages written
+// twice like the template statics already have one
+var policyMeta = regexp.MustCompile(`\n\s*<meta http-equiv="Content-Security-Policy" content="[^"]*">`)
+
+// ContentSecurityPolicy adds the configured policy to every page written by
+// the IndexWriter. The hashes of inline scripts, styles and event handlers of
+// the page are added to script-src and style-src.
+type ContentSecurityPolicy struct {
+	Directives map[string]string
+	mu         sync.Mutex
+	// pages are the policies of the pages by their path
+	pages map[string]string
+}
+
+// newContentSecurityPolicy returns the policy of directives, nil if there are
+// none
+func newContentSecurityPolicy(directives map[string]string) *ContentSecurityPolicy {
+	if len(directives) == 0 {
+		return nil
+	}
+	return &ContentSecurityPolicy{Directives: directives, pages: map[string]string{}}
+}
+
+// apply inserts the policy of page as meta tag into its head and records it
+// for the headers file
+func (c *ContentSecurityPolicy) apply(page []byte, destination, filePath string) []byte {
+	page = policyMeta.ReplaceAll(page, nil)
+	policy := c.getPolicy(page)
+	loc := headStart.FindIndex(page)
+	if loc == nil {
+		return page
+	}
+	meta := fmt.Sprintf("\n    <meta http-equiv=\"Content-Security-Policy\" content=\"%s\">", template.HTMLEscapeString(policy))
+	result := make([]byte, 0, len(page)+len(meta))
+	result = append(result, page[:loc[1]]...)
+	result = append(result, meta...)
+	result = append(result, page[loc[1]:]...)
+	if rel, err := filepath.Rel(destination, filePath); err == nil && !strings.HasPrefix(rel, "..") {
+		path := "/" + strings.TrimSuffix(filepath.ToSlash(rel), "index.html")
+		c.mu.Lock()
+		c.pages[path] = policy
+		c.mu.Unlock()
+	}
+	return result
+}
+
+// getPolicy returns the policy of a page with the hashes of its inline
+// content
+func (c *ContentSecurityPolicy) getPolicy(page []byte) string {
+	scripts, handlers, styles := getInlineHashes(page)
+	directives := map[string]string{}
+	for name, value := range c.Directives {
+		directives[strings.ToLower(name)] = strings.TrimSpace(value)
+	}
+	if len(handlers) > 0 {
+		scripts = append(append(scripts, "'unsafe-hashes'"), handlers...)
+	}
+	addCSPSources(directives, "script-src", scripts)
+	addCSPSources(directives, "style-src", styles)
+	names := make([]string, 0, len(directives))
+	for name := range directives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, strings.TrimSpace(name+" "+directives[name]))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// addCSPSources adds sources to a directive, a directive which isn't
+// configured starts with the sources of default-src it replaces
+func addCSPSources(directives map[string]string, name string, sources []string) {
+	if len(sources) == 0 {
+		return
+	}
+	value, ok := directives[name]
+	if !ok {
+		value = directives["default-src"]
+	}
+	directives[name] = strings.TrimSpace(value + " " + strings.Join(sources, " "))
+}
+
+// getInlineHashes returns the CSP hashes of the inline scripts, event handler
+// attributes and inline styles of page, each hash once
+func getInlineHashes(page []byte) (scripts, handlers, styles []string) {
+	seen := map[string]bool{}
+	add := func(list *[]string, content []byte) {
+		sum := sha256.Sum256(content)
+		hash := "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+		if !seen[hash] {
+			seen[hash] = true
+			*list = append(*list, hash)
+		}
+	}
+	z := html.NewTokenizer(bytes.NewReader(page))
+	inline := ""
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return
+		case html.StartTagToken:
+			name, hasAttr := z.TagName()
+			inline = ""
+			src := false
+			for hasAttr {
+				var key, value []byte
+				key, value, hasAttr = z.TagAttr()
+				if bytes.HasPrefix(key, []byte("on")) {
+					add(&handlers, value)
+				}
+				if string(key) == "src" {
+					src = true
+				}
+			}
+			if (string(name) == "script" && !src) || string(name) == "style" {
+				inline = string(name)
+			}
+		case html.TextToken:
+			switch inline {
+			case "script":
+				add(&scripts, z.Raw())
+			case "style":
+				add(&styles, z.Raw())
+			}
+		case html.EndTagToken:
+			inline = ""
+		}
+	}
+}
+
+// writeHeaders writes the policies of the pages to a _headers file for hosts
+// like Netlify
+func (c *ContentSecurityPolicy) writeHeaders(destination string, fileMode os.FileMode) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	paths := make([]string, 0, len(c.pages))
+	for path := range c.pages {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var b bytes.Buffer
+	for _, path := range paths {
+		fmt.Fprintf(&b, "%s\n  Content-Security-Policy: %s\n", path, c.pages[path])
+	}
+	return writeTextFile(filepath.Join(destination, "_headers"), b.Bytes(), fileMode)
+}
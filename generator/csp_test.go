@@ -0,0 +1,62 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/eleztian/blog-generator/config"
+)
+
+func cspHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+}
+
+func TestCSPWithCriticalCSS(t *testing.T) {
+	critical := filepath.Join(t.TempDir(), "critical.css")
+	about := filepath.Join(filepath.Dir(critical), "about.html")
+	writeTestFiles(t, filepath.Dir(critical), map[string]string{"critical.css": "body{margin:0}", "about.html": "<p>About.</p>"})
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.Statics.Templates = append(cfg.Blog.Statics.Templates, struct {
+			Src  string
+			Dest string
+		}{about, "about"})
+		cfg.Blog.CriticalCSS.Enabled = true
+		cfg.Blog.CriticalCSS.File = critical
+		cfg.Blog.CSP.Policy = map[string]string{"default-src": "'self'", "img-src": "*"}
+		cfg.Blog.CSP.Headers = true
+	}), map[string]string{
+		"post/post.md": testPost("Post", "01.02.2021", "The post."),
+	}).build()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(site.read("post/index.html")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := doc.Find(`head > meta[http-equiv="Content-Security-Policy"]`)
+	if meta.Length() != 1 {
+		t.Fatalf("post has %d policy meta tags, want 1", meta.Length())
+	}
+	policy := meta.AttrOr("content", "")
+	for _, want := range []string{
+		"default-src 'self'",
+		"img-src *",
+		"script-src 'self' ",
+		"'unsafe-hashes' " + cspHash("this.media='all'"),
+		"style-src 'self' " + cspHash("body{margin:0}"),
+	} {
+		if !strings.Contains(policy, want) {
+			t.Errorf("policy %q doesn't contain %q", policy, want)
+		}
+	}
+	if headers := site.read("_headers"); !strings.Contains(headers, "/post/\n  Content-Security-Policy: "+policy+"\n") {
+		t.Errorf("_headers doesn't hold the policy of the post:\n%s", headers)
+	}
+	page := site.read("about/index.html")
+	if n := strings.Count(page, `http-equiv="Content-Security-Policy"`); n != 1 {
+		t.Errorf("statics page has %d policy meta tags, want 1:\n%s", n, page)
+	}
+}
@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// autoDateFormat as date format detects the layout used by the posts
+const autoDateFormat = "auto"
+
+// dateFormatSamples is the number of posts used to detect the date format
+const dateFormatSamples = 5
+
+// dateFormatCandidates are the layouts tried when detecting the date format
+var dateFormatCandidates = []string{
+	"2006-01-02",
+	"02.01.2006",
+	"02.Jan.2006",
+	"2006/01/02",
+	"01/02/2006",
+	"02 Jan 2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"2006-01-02 15:04",
+	time.RFC3339,
+}
+
+// detectDateFormat finds the candidate layout used by the first posts and
+// warns about all posts whose date doesn't match it
+func detectDateFormat(sources []string) (string, error) {
+	dates := map[string]string{}
+	var order []string
+	for _, path := range sources {
+		date, err := readPostDate(path)
+		if err != nil || date == "" {
+			continue
+		}
+		dates[path] = date
+		order = append(order, path)
+	}
+	if len(order) == 0 {
+		return "", fmt.Errorf("could not detect the date format, no post has a date")
+	}
+	samples := order
+	if len(samples) > dateFormatSamples {
+		samples = samples[:dateFormatSamples]
+	}
+	best, bestCount := "", 0
+	for _, layout := range dateFormatCandidates {
+		count := 0
+		for _, path := range samples {
+			if _, err := time.Parse(layout, dates[path]); err == nil {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = layout, count
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("could not detect the date format, %q matches none of %v", dates[samples[0]], dateFormatCandidates)
+	}
+	fmt.Printf("Detected date format %q\n", best)
+	for _, path := range order {
+		if _, err := time.Parse(best, dates[path]); err != nil {
+			fmt.Printf("Warning: date %q of %s doesn't match the detected date format %q\n", dates[path], path, best)
+		}
+	}
+	return best, nil
+}
+
+// matchDateFormat returns the first candidate layout matching date
+func matchDateFormat(date string) (string, error) {
+	for _, layout := range dateFormatCandidates {
+		if _, err := time.Parse(layout, date); err == nil {
+			return layout, nil
+		}
+	}
+	return "", fmt.Errorf("could not detect the date format, %q matches none of %v", date, dateFormatCandidates)
+}
+
+// readPostDate returns the unparsed date of a post
+func readPostDate(source string) (string, error) {
+	path, filePath := getPostSource(source)
+	raw, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	h, err := readRawMeta(path, bufio.NewReader(bytes.NewReader(normalizeNewlines(raw))))
+	if err != nil {
+		return "", err
+	}
+	meta := struct{ Date string }{}
+	if err := yaml.Unmarshal(h, &meta); err != nil {
+		return "", err
+	}
+	return meta.Date, nil
+}
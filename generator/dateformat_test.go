@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeDatedPosts writes a post for each date and returns their folders
+func writeDatedPosts(t *testing.T, dates ...string) []string {
+	root := t.TempDir()
+	files := map[string]string{}
+	sources := []string{}
+	for i, date := range dates {
+		name := string(rune('a' + i))
+		files[name+"/post.md"] = testPost("Post "+name, date, "Body.")
+		sources = append(sources, filepath.Join(root, name))
+	}
+	writeTestFiles(t, root, files)
+	return sources
+}
+
+func TestDetectDateFormat(t *testing.T) {
+	tests := []struct {
+		dates []string
+		want  string
+	}{
+		{[]string{"2021-02-01", "2021-03-15", "2021-12-31"}, "2006-01-02"},
+		{[]string{"01.02.2021", "15.03.2021"}, "02.01.2006"},
+		{[]string{"Feb 1, 2021", "Mar 15, 2021"}, "Jan 2, 2006"},
+	}
+	for _, test := range tests {
+		sources := writeDatedPosts(t, test.dates...)
+		var got string
+		output := captureStdout(t, func() {
+			var err error
+			if got, err = detectDateFormat(sources); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if got != test.want {
+			t.Errorf("detected %q for %v, want %q", got, test.dates, test.want)
+		}
+		if strings.Contains(output, "Warning") {
+			t.Errorf("consistent dates %v gave a warning:\n%s", test.dates, output)
+		}
+	}
+}
+
+func TestDetectDateFormatDisagreement(t *testing.T) {
+	sources := writeDatedPosts(t, "2021-02-01", "2021-03-15", "15.03.2021", "2021-04-01")
+	var got string
+	output := captureStdout(t, func() {
+		var err error
+		if got, err = detectDateFormat(sources); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if got != "2006-01-02" {
+		t.Errorf("detected %q, want the layout of most posts", got)
+	}
+	warnings := strings.Count(output, "Warning")
+	if warnings != 1 || !strings.Contains(output, `"15.03.2021" of `+sources[2]) {
+		t.Errorf("warnings don't name just the disagreeing post:\n%s", output)
+	}
+}
+
+func TestDetectDateFormatFails(t *testing.T) {
+	if _, err := detectDateFormat(writeDatedPosts(t, "someday")); err == nil {
+		t.Error("unknown date layout was detected")
+	}
+}
@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// defaultsFile holds meta data defaults in the root of the blog repository
+const defaultsFile = "defaults.yml"
+
+// metaDefaults maps folders below the root, "" being the root itself, to the
+// meta data values of the posts within them
+type metaDefaults map[string]map[string]interface{}
+
+// readDefaults reads the defaults.yml in root, if any
+func readDefaults(root string) (metaDefaults, error) {
+	if root == "" {
+		return nil, nil
+	}
+	filePath := filepath.Join(root, defaultsFile)
+	raw, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error while reading file %s: %v", filePath, err)
+	}
+	defaults := metaDefaults{}
+	if err := yaml.Unmarshal(raw, &defaults); err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", defaultsFile, err)
+	}
+	result := metaDefaults{}
+	for scope, values := range defaults {
+		result[strings.Trim(filepath.ToSlash(scope), "/")] = values
+	}
+	return result, nil
+}
+
+// valuesFor returns the defaults of the post folder rel, defaults of deeper
+// folders take precedence over the ones of their parents
+func (d metaDefaults) valuesFor(rel string) map[string]interface{} {
+	rel = filepath.ToSlash(rel)
+	scopes := []string{}
+	for scope := range d {
+		if scope == "" || rel == scope || strings.HasPrefix(rel, scope+"/") {
+			scopes = append(scopes, scope)
+		}
+	}
+	// all matching scopes are ancestors of rel, the shorter the less specific
+	sort.Slice(scopes, func(i, j int) bool {
+		return len(scopes[i]) < len(scopes[j])
+	})
+	values := map[string]interface{}{}
+	for _, scope := range scopes {
+		for key, value := range d[scope] {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// applyDefaults fills the fields the raw meta data leaves unset with defaults
+func applyDefaults(raw []byte, defaults map[string]interface{}) ([]byte, error) {
+	if len(defaults) == 0 {
+		return raw, nil
+	}
+	meta := map[string]interface{}{}
+	if err := yaml.Unmarshal(raw, &meta); err != nil {
+		return nil, fmt.Errorf("error reading yml: %v", err)
+	}
+	for key, value := range defaults {
+		if _, ok := meta[key]; !ok {
+			meta[key] = value
+		}
+	}
+	return yaml.Marshal(meta)
+}
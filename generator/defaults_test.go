@@ -0,0 +1,44 @@
+package generator
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMetaDefaults(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		defaultsFile:                    "/:\n  short: Default short\n  tags: [general]\nposts/news/:\n  category: news\n  tags: [news]\n",
+		"posts/news/filled/post.md":     testPost("Filled", "01.02.2021", "Filled."),
+		"posts/news/overridden/post.md": testPost("Overridden", "02.02.2021", "Overridden.", "tags: [go]", "category: tech"),
+		"posts/other/post.md":           testPost("Other", "03.02.2021", "Other."),
+		"posts/newsletter/post.md":      testPost("Newsletter", "04.02.2021", "Newsletter."),
+	})
+	defaults, err := readDefaults(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loader := newTestLoader(t, root, newTestConfig(t, nil))
+	loader.Defaults = defaults
+	tests := []struct {
+		source   string
+		short    string
+		tags     []string
+		category string
+	}{
+		{"posts/news/filled", "Default short", []string{"news"}, "news"},
+		{"posts/news/overridden", "Default short", []string{"go"}, "tech"},
+		{"posts/other", "Default short", []string{"general"}, "posts"},
+		{"posts/newsletter", "Default short", []string{"general"}, "posts"},
+	}
+	for _, test := range tests {
+		post, err := loader.newPost(filepath.Join(root, filepath.FromSlash(test.source)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if post.Meta.Short != test.short || !reflect.DeepEqual(post.Meta.Tags, test.tags) || post.Category != test.category {
+			t.Errorf("%s has short %q, tags %v and category %q, want %q, %v and %q", test.source, post.Meta.Short, post.Meta.Tags, post.Category, test.short, test.tags, test.category)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// DraftsPath is the route the handler of NewDraftsHandler is registered at
+const DraftsPath = "/_drafts/"
+
+var draftsListTemplate = template.Must(template.New("drafts").Parse(`<ul class="drafts">
+{{- range .Posts }}
+<li><a href="{{ $.Prefix }}{{ .Name }}/">{{ .Meta.Title }}</a> <em>{{ .Meta.Status }}</em></li>
+{{- else }}
+<li>No drafts</li>
+{{- end }}
+</ul>`))
+
+// NewDraftsHandler returns an http.Handler listing all posts with status
+// draft or review at /_drafts/ and rendering them from their source at
+// /_drafts/<post>/
+func NewDraftsHandler(config *SiteConfig) http.Handler {
+	return &draftsHandler{site: &SiteGenerator{Config: config}}
+}
+
+type draftsHandler struct {
+	site *SiteGenerator
+}
+
+func (h *draftsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	posts, err := h.site.loadPosts()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	drafts := []*Post{}
+	for _, post := range posts {
+		if post.Meta.Status == statusDraft || post.Meta.Status == statusReview {
+			drafts = append(drafts, post)
+		}
+	}
+	rest := strings.TrimPrefix(r.URL.Path, DraftsPath)
+	if rest == "" || rest == "/" {
+		h.serveList(w, drafts)
+		return
+	}
+	for _, post := range drafts {
+		if rest == post.Name || rest == post.Name+"/" {
+			h.servePost(w, post)
+			return
+		}
+		if prefix := post.Name + "/" + getImagesPath(post); post.ImagesDir != "" && strings.HasPrefix(rest, prefix) {
+			http.ServeFile(w, r, filepath.Join(post.ImagesDir, filepath.FromSlash(strings.TrimPrefix(rest, prefix))))
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+func (h *draftsHandler) serveList(w http.ResponseWriter, drafts []*Post) {
+	var content bytes.Buffer
+	data := struct {
+		Prefix string
+		Posts  []*Post
+	}{DraftsPath, drafts}
+	if err := draftsListTemplate.Execute(&content, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.render(w, "Drafts", "", template.HTML(content.String()))
+}
+
+func (h *draftsHandler) servePost(w http.ResponseWriter, post *Post) {
+	h.render(w, post.Meta.Title, post.Meta.Short, template.HTML(string(post.HTML)))
+}
+
+// render executes the site template for a page of the drafts preview
+func (h *draftsHandler) render(w http.ResponseWriter, title, description string, content template.HTML) {
+	cfg := h.site.Config.Config
+	assets, err := newAssetManifest(cfg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	funcs := newTemplateFuncs(assets, newSVGSprite(cfg.Blog.Sprite), newSitePaths(cfg))
+	t, err := getTemplate(filepath.Join("static", "template.html"), funcs)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writer := &IndexWriter{
+		BlogTitle:       cfg.Blog.Title,
+		BlogDescription: cfg.Blog.Description,
+		BlogAuthor:      cfg.Blog.Author,
+		BlogURL:         cfg.Blog.URL,
+		Github:          cfg.Blog.Github,
+		Twitter:         cfg.Blog.Twitter,
+		GooglePluse:     cfg.Blog.GooglePluse,
+		Funcs:           funcs,
+		Paths:           newSitePaths(cfg),
+	}
+	td := writer.NewIndexData(DraftsPath, title, description, content)
+	td.NoIndex = true
+	var page bytes.Buffer
+	if err := t.Execute(&page, td); err != nil {
+		http.Error(w, fmt.Sprintf("error executing template: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(page.Bytes())
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// getDraftsPage requests path from the drafts handler of site
+func getDraftsPage(t *testing.T, site *testSite, path string) (int, string) {
+	t.Helper()
+	handler := NewDraftsHandler(&SiteConfig{Sources: site.sources(), SourceRoot: site.Root, Destination: site.Dest, Config: site.Config})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	res, err := http.Get(server.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.StatusCode, string(body)
+}
+
+func TestDraftsHandler(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"published/post.md": testPost("Published Post", "01.02.2021", "Published."),
+		"draft/post.md":     testPost("Draft Post", "02.02.2021", "The draft text.", "status: draft"),
+		"review/post.md":    testPost("Review Post", "03.02.2021", "Review.", "status: review"),
+	})
+	status, list := getDraftsPage(t, site, DraftsPath)
+	if status != http.StatusOK {
+		t.Fatalf("drafts list has status %d", status)
+	}
+	for _, want := range []string{`<a href="/_drafts/draft/">Draft Post</a>`, `<a href="/_drafts/review/">Review Post</a>`} {
+		if !strings.Contains(list, want) {
+			t.Errorf("drafts list is missing %s", want)
+		}
+	}
+	if strings.Contains(list, "Published Post") {
+		t.Error("drafts list contains a published post")
+	}
+	if _, page := getDraftsPage(t, site, DraftsPath+"draft/"); !strings.Contains(page, "The draft text.") {
+		t.Error("draft isn't rendered from its source")
+	}
+	if status, _ := getDraftsPage(t, site, DraftsPath+"published/"); status != http.StatusNotFound {
+		t.Errorf("published post has status %d, want 404", status)
+	}
+	if site.exists("draft/index.html") {
+		t.Error("serving the drafts published them")
+	}
+}
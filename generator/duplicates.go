@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// checkDuplicates fails if posts are generated to the same output path and
+// warns about posts sharing a title
+func checkDuplicates(posts []*Post) error {
+	paths := map[string][]string{}
+	titles := map[string][]string{}
+	for _, post := range posts {
+		// posts in review are generated to the preview destination
+		key := post.Path
+		if isReview(post) {
+			key = "preview:" + key
+		}
+		paths[key] = append(paths[key], post.Dir)
+		for _, alias := range post.Meta.Aliases {
+			aliasKey := "/" + strings.Trim(path.Clean("/"+alias), "/") + "/"
+			paths[aliasKey] = append(paths[aliasKey], post.Dir+" (alias)")
+		}
+		if title := strings.ToLower(strings.TrimSpace(post.Meta.Title)); title != "" {
+			titles[title] = append(titles[title], post.Dir)
+		}
+	}
+	for _, title := range sortedKeys(titles) {
+		if dirs := titles[title]; len(dirs) > 1 {
+			fmt.Printf("Warning: posts %s share the title %q\n", strings.Join(dirs, ", "), title)
+		}
+	}
+	collisions := []string{}
+	for _, path := range sortedKeys(paths) {
+		if dirs := paths[path]; len(dirs) > 1 {
+			collisions = append(collisions, fmt.Sprintf("%s are generated to %s", strings.Join(dirs, ", "), strings.TrimPrefix(path, "preview:")))
+		}
+	}
+	if len(collisions) > 0 {
+		return fmt.Errorf("posts would overwrite each other: %s", strings.Join(collisions, "; "))
+	}
+	return nil
+}
+
+// reportDuplicateContent warns about posts with identical markdown bodies,
+// leading and trailing whitespace is ignored. In strict mode duplicates fail.
+func reportDuplicateContent(posts []*Post, strict bool) error {
+	files := map[string][]string{}
+	for _, post := range posts {
+		body := bytes.TrimSpace(post.Markdown)
+		if len(body) == 0 {
+			continue
+		}
+		hash := fmt.Sprintf("%x", sha256.Sum256(body))
+		files[hash] = append(files[hash], post.File)
+	}
+	count := 0
+	for _, hash := range sortedKeys(files) {
+		if sources := files[hash]; len(sources) > 1 {
+			fmt.Printf("Warning: posts %s have identical content\n", strings.Join(sources, ", "))
+			count++
+		}
+	}
+	if strict && count > 0 {
+		return fmt.Errorf("found %d posts with duplicate content", count)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestDuplicatePathsFail(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"first/post.md":  testPost("First", "01.02.2021", "First.", "slug: same"),
+		"second/post.md": testPost("Second", "02.02.2021", "Second.", "slug: /same/"),
+	})
+	var err error
+	captureStdout(t, func() { err = site.generate() })
+	if err == nil {
+		t.Fatal("posts with the same path were generated")
+	}
+	for _, want := range []string{filepath.Join(site.Root, "first"), filepath.Join(site.Root, "second"), "/same/"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q doesn't name %s", err, want)
+		}
+	}
+}
+
+func TestDuplicateTitlesWarn(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"first/post.md":  testPost("Same Title", "01.02.2021", "First."),
+		"second/post.md": testPost("same title", "02.02.2021", "Second."),
+	})
+	output := captureStdout(t, func() { site.build() })
+	if !strings.Contains(output, "Warning: posts "+filepath.Join(site.Root, "first")+", "+filepath.Join(site.Root, "second")+` share the title "same title"`) {
+		t.Errorf("duplicate titles gave no warning naming both posts:\n%s", output)
+	}
+}
+
+func TestDuplicateContentWarns(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+			cfg.Generator.DuplicateContent = true
+			cfg.Generator.Strict = strict
+		}), map[string]string{
+			"first/post.md":  testPost("First", "01.02.2021", "The same body.\n"),
+			"second/post.md": testPost("Second", "02.02.2021", "  The same body."),
+			"third/post.md":  testPost("Third", "03.02.2021", "Another body."),
+		})
+		var err error
+		output := captureStdout(t, func() { err = site.generate() })
+		if strict != (err != nil) {
+			t.Errorf("strict %v: generation returned %v", strict, err)
+		}
+		first, second := filepath.Join(site.Root, "first", "post.md"), filepath.Join(site.Root, "second", "post.md")
+		var warning string
+		for _, line := range strings.Split(output, "\n") {
+			if strings.Contains(line, "have identical content") {
+				warning = line
+			}
+		}
+		if !strings.Contains(warning, first) || !strings.Contains(warning, second) || strings.Contains(warning, "third") {
+			t.Errorf("strict %v: duplicate content warning %q doesn't name exactly both posts", strict, warning)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"html/template"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultEditor opens the source of posts in Visual Studio Code if no editor
+// is configured
+const defaultEditor = "vscode://file/{path}"
+
+// setEditLinks sets the links opening the sources of posts in the editor,
+// format has {path} replaced by the absolute path of the source file. Posts
+// cloned from a local repository link to their file in the repository
+// instead of the clone in sourceRoot.
+func setEditLinks(posts []*Post, format, sourceRoot, repo string) {
+	if format == "" {
+		format = defaultEditor
+	}
+	for _, post := range posts {
+		post.EditLink = getEditLink(getEditPath(post.File, sourceRoot, repo), format)
+	}
+}
+
+// getEditPath returns the absolute path of the source file of a post
+func getEditPath(file, sourceRoot, repo string) string {
+	if info, err := os.Stat(repo); err == nil && info.IsDir() && sourceRoot != "" {
+		if rel, err := filepath.Rel(sourceRoot, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = filepath.Join(repo, rel)
+		}
+	}
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+	return filepath.ToSlash(file)
+}
+
+// getEditLink returns the editor link of the absolute path, the templates
+// would filter it otherwise as they only allow web schemes
+func getEditLink(path, format string) template.URL {
+	escaped := (&url.URL{Path: strings.TrimPrefix(path, "/")}).EscapedPath()
+	return template.URL(strings.Replace(format, "{path}", escaped, -1))
+}
@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestEditLinkOnlyInDev(t *testing.T) {
+	for _, dev := range []bool{false, true} {
+		site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+			cfg.Generator.Dev = dev
+		}), map[string]string{
+			"first/post.md": testPost("First Post", "01.02.2021", "The first post."),
+		}).build()
+		file := filepath.ToSlash(filepath.Join(site.Root, "first", "post.md"))
+		want := `<p class="edit-link"><a href="vscode://file/` + strings.TrimPrefix(file, "/") + `">Edit this post</a></p>`
+		page := site.read("first/index.html")
+		if got := strings.Contains(page, want); got != dev {
+			t.Errorf("dev %v: post has the edit link %v", dev, got)
+		}
+		if !dev && strings.Contains(page, "edit-link") {
+			t.Error("post has an edit link outside of dev")
+		}
+	}
+}
+
+func TestEditLinkFormat(t *testing.T) {
+	posts := []*Post{{File: "/blog/my post/post.md"}}
+	setEditLinks(posts, "idea://open?file={path}", "", "")
+	if want := "idea://open?file=blog/my%20post/post.md"; string(posts[0].EditLink) != want {
+		t.Errorf("got edit link %s, want %s", posts[0].EditLink, want)
+	}
+}
@@ -0,0 +1,24 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// reportEmptyPosts warns about posts whose rendered body is empty, e.g. a
+// post.md with only meta data. Posts with stub set in their meta data are
+// empty on purpose. In strict mode empty posts fail.
+func reportEmptyPosts(posts []*Post, strict bool) error {
+	empty := []string{}
+	for _, post := range posts {
+		if post.Meta.Stub || len(bytes.TrimSpace(post.HTML)) > 0 {
+			continue
+		}
+		fmt.Printf("Warning: post %s has no content\n", post.File)
+		empty = append(empty, post.File)
+	}
+	if strict && len(empty) > 0 {
+		return fmt.Errorf("found %d posts without content, first: %s", len(empty), empty[0])
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestEmptyPosts(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+			cfg.Generator.Strict = strict
+		}), map[string]string{
+			"empty/post.md": testPost("Empty", "01.02.2021", "\n  \n"),
+			"stub/post.md":  testPost("Stub", "02.02.2021", "", "stub: true"),
+			"full/post.md":  testPost("Full", "03.02.2021", "Content."),
+		})
+		var err error
+		output := captureStdout(t, func() { err = site.generate() })
+		if strict != (err != nil) {
+			t.Errorf("strict %v: generation returned %v", strict, err)
+		}
+		if want := "Warning: post " + filepath.Join(site.Root, "empty", "post.md") + " has no content"; !strings.Contains(output, want) {
+			t.Errorf("strict %v: empty post wasn't reported:\n%s", strict, output)
+		}
+		if strings.Count(output, "has no content") != 1 {
+			t.Errorf("strict %v: other posts were reported as empty:\n%s", strict, output)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Enclosure is a media file attached to a post, e.g. the audio of a podcast
+// episode. Either URL and Length or a File of the post folder have to be set.
+type Enclosure struct {
+	URL    string
+	Length int64
+	Type   string
+	File   string
+}
+
+// checkEnclosure validates the enclosure of a post and measures local files
+func checkEnclosure(path string, enclosure *Enclosure) error {
+	if enclosure.File != "" {
+		clean := filepath.Clean(filepath.FromSlash(enclosure.File))
+		if filepath.IsAbs(clean) || strings.HasPrefix(clean, "..") {
+			return fmt.Errorf("enclosure %s has to be inside of the post folder %s", enclosure.File, path)
+		}
+		filePath := filepath.Join(path, clean)
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return fmt.Errorf("error reading enclosure %s: %v", filePath, err)
+		}
+		enclosure.File = filepath.ToSlash(clean)
+		enclosure.Length = info.Size()
+		if enclosure.Type == "" {
+			enclosure.Type = mime.TypeByExtension(filepath.Ext(clean))
+		}
+	} else if enclosure.URL == "" {
+		return fmt.Errorf("enclosure needs either a url or a file")
+	}
+	if enclosure.Length <= 0 {
+		return fmt.Errorf("enclosure %s needs a length in bytes", enclosure.URL)
+	}
+	mediaType, _, err := mime.ParseMediaType(enclosure.Type)
+	if err != nil {
+		return fmt.Errorf("enclosure has an invalid type %q: %v", enclosure.Type, err)
+	}
+	if !strings.HasPrefix(mediaType, "audio/") && !strings.HasPrefix(mediaType, "video/") {
+		return fmt.Errorf("enclosure type %q is not an audio or video type", enclosure.Type)
+	}
+	return nil
+}
+
+// getEnclosureURL returns the absolute URL of the enclosure
+func getEnclosureURL(post *Post) string {
+	enclosure := post.Meta.Enclosure
+	if enclosure.File != "" {
+		return post.URL + enclosure.File
+	}
+	return enclosure.URL
+}
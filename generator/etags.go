@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// etagsFile is the name of the file holding the ETags of the output
+const etagsFile = ".etags.json"
+
+// etagPartSize is the part size used by S3 for multipart uploads
+const etagPartSize = 8 * 1024 * 1024
+
+// writeETags writes a mapping of every output file to the ETag S3 computes
+// for it, files larger than one part get the multipart ETag
+func writeETags(destination string, fileMode os.FileMode) error {
+	etags := map[string]string{}
+	err := filepath.Walk(destination, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(destination, path)
+		if err != nil {
+			return err
+		}
+		if rel == etagsFile {
+			return nil
+		}
+		etag, err := computeETag(path, etagPartSize)
+		if err != nil {
+			return err
+		}
+		etags[filepath.ToSlash(rel)] = etag
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error computing etags of %s: %v", destination, err)
+	}
+	// maps are marshalled with sorted keys, so the file is deterministic
+	data, err := json.MarshalIndent(etags, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error creating etags: %v", err)
+	}
+	filePath := filepath.Join(destination, etagsFile)
+	f, err := createFile(filePath, fileMode)
+	if err != nil {
+		return fmt.Errorf("error creating file %s: %v", filePath, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("error writing file %s: %v", filePath, err)
+	}
+	return nil
+}
+
+// computeETag returns the MD5 of the file, or for files larger than partSize
+// the MD5 of the concatenated part MD5s followed by the number of parts
+func computeETag(path string, partSize int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	if info.Size() <= partSize {
+		h := md5.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+	parts := md5.New()
+	n := 0
+	for {
+		h := md5.New()
+		written, err := io.CopyN(h, f, partSize)
+		if written > 0 {
+			parts.Write(h.Sum(nil))
+			n++
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(parts.Sum(nil)), n), nil
+}
@@ -0,0 +1,45 @@
+package generator
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestETags(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Generator.ETags = true
+	}), map[string]string{
+		"post/post.md": testPost("Post", "01.02.2021", "The post."),
+	}).build()
+	etags := map[string]string{}
+	if err := json.Unmarshal([]byte(site.read(etagsFile)), &etags); err != nil {
+		t.Fatal(err)
+	}
+	sum := md5.Sum([]byte(site.read("post/index.html")))
+	if etag := etags["post/index.html"]; etag != hex.EncodeToString(sum[:]) {
+		t.Errorf("etag of post/index.html is %s, want its md5 %x", etag, sum)
+	}
+	if _, ok := etags[etagsFile]; ok {
+		t.Error("etags contain the etag file")
+	}
+}
+
+func TestMultipartETag(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"large.bin": strings.Repeat("a", 10) + strings.Repeat("b", 5)})
+	first, second := md5.Sum([]byte(strings.Repeat("a", 10))), md5.Sum([]byte(strings.Repeat("b", 5)))
+	parts := md5.Sum(append(first[:], second[:]...))
+	etag, err := computeETag(filepath.Join(dir, "large.bin"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := hex.EncodeToString(parts[:]) + "-2"; etag != want {
+		t.Errorf("multipart etag is %s, want %s", etag, want)
+	}
+}
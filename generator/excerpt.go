@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// seoEllipsis is appended to truncated meta descriptions
+const seoEllipsis = "…"
+
+// moreSeparator ends the summary at the start of a post
+const moreSeparator = "<!--more-->"
+
+// truncate shortens s to at most length runes without cutting words in half.
+// Trailing punctuation is removed and ellipsis is appended if s was truncated.
+func truncate(s string, length int, ellipsis string) string {
+	s = strings.TrimSpace(s)
+	runes := []rune(s)
+	if length <= 0 || len(runes) <= length {
+		return s
+	}
+	cut := runes[:length]
+	if !unicode.IsSpace(runes[length]) {
+		// step back to the last word boundary, a single long word is cut hard
+		for i := len(cut) - 1; i > 0; i-- {
+			if unicode.IsSpace(cut[i]) {
+				cut = cut[:i]
+				break
+			}
+		}
+	}
+	result := strings.TrimRightFunc(string(cut), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+	return result + ellipsis
+}
+
+// seoDescription truncates a meta description to at most length runes,
+// including the ellipsis, at a word boundary
+func seoDescription(s string, length int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if length <= 0 || len([]rune(s)) <= length {
+		return s
+	}
+	return truncate(s, length-len([]rune(seoEllipsis)), seoEllipsis)
+}
+
+// getSummary returns the first non-empty summary of the sources in order,
+// "short" is the short description of the meta data, "more" the text before
+// the <!--more--> separator and "paragraph" the first paragraph of a post
+func getSummary(post *Post, sources []string) string {
+	for _, source := range sources {
+		var summary string
+		switch source {
+		case "short":
+			summary = strings.TrimSpace(post.Meta.Short)
+		case "more":
+			if i := bytes.Index(post.HTML, []byte(moreSeparator)); i >= 0 {
+				summary = htmlText(post.HTML[:i])
+			}
+		case "paragraph":
+			summary = firstParagraph(post.HTML)
+		}
+		if summary != "" {
+			return summary
+		}
+	}
+	return ""
+}
+
+// firstParagraph returns the text of the first non-empty paragraph of html
+func firstParagraph(html []byte) string {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		return ""
+	}
+	doc.Find("a.anchor").Remove()
+	text := ""
+	doc.Find("p").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		text = strings.Join(strings.Fields(s.Text()), " ")
+		return text == ""
+	})
+	return text
+}
+
+// htmlText returns the text of html with collapsed whitespace, heading
+// anchors are left out
+func htmlText(html []byte) string {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		return ""
+	}
+	doc.Find("a.anchor").Remove()
+	return strings.Join(strings.Fields(doc.Text()), " ")
+}
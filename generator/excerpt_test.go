@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s      string
+		length int
+		want   string
+	}{
+		{"short text", 20, "short text"},
+		{"  padded  ", 20, "padded"},
+		{"cut between words here", 11, "cut between…"},
+		{"cut in the middle of words", 12, "cut in the…"},
+		{"ends with punctuation, and more", 22, "ends with punctuation…"},
+		{"averyveryverylongword", 5, "avery…"},
+		{"anything", 0, "anything"},
+		{"Grüße aus Wien", 6, "Grüße…"},
+	}
+	for _, test := range tests {
+		if got := truncate(test.s, test.length, "…"); got != test.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", test.s, test.length, got, test.want)
+		}
+	}
+}
+
+func TestSEODescription(t *testing.T) {
+	text := "The quick brown fox jumps over the lazy dog, twice."
+	tests := []struct {
+		length int
+		want   string
+	}{
+		{0, text},
+		{100, text},
+		{20, "The quick brown fox…"},
+		{18, "The quick brown…"},
+		{13, "The quick…"},
+	}
+	for _, test := range tests {
+		got := seoDescription("  The quick\nbrown fox jumps over  the lazy dog, twice. ", test.length)
+		if got != test.want {
+			t.Errorf("seoDescription with length %d = %q, want %q", test.length, got, test.want)
+		}
+		if test.length > 0 && utf8.RuneCountInString(got) > test.length {
+			t.Errorf("description %q is longer than %d", got, test.length)
+		}
+	}
+}
+
+func TestSEODescriptionMeta(t *testing.T) {
+	short := strings.Repeat("word ", 30) + "end"
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.Excerpt.Description = 32
+	}), map[string]string{
+		"post/post.md": testPost("Post", "01.02.2021", "Body.", "short: "+short),
+	}).build()
+	want := `<meta name="description" content="word word word word word word…">`
+	if page := site.read("post/index.html"); !strings.Contains(page, want) {
+		t.Errorf("page doesn't have the description %s", want)
+	}
+}
+
+func TestGetSummarySourcePrecedence(t *testing.T) {
+	html := []byte("<h2>Intro</h2>\n<p>First <em>paragraph</em>.</p>\n" + moreSeparator + "\n<p>Rest.</p>\n")
+	withShort := &Post{Meta: &Meta{Short: " The short. "}, HTML: html}
+	noShort := &Post{Meta: &Meta{}, HTML: html}
+	noMore := &Post{Meta: &Meta{}, HTML: []byte("<p></p><p>Second paragraph.</p>")}
+	tests := []struct {
+		post    *Post
+		sources []string
+		want    string
+	}{
+		{withShort, []string{"short", "more", "paragraph"}, "The short."},
+		{withShort, []string{"more", "short"}, "Intro First paragraph."},
+		{withShort, []string{"paragraph", "short"}, "First paragraph."},
+		{noShort, []string{"short", "more"}, "Intro First paragraph."},
+		{noMore, []string{"short", "more", "paragraph"}, "Second paragraph."},
+		{noMore, []string{"short", "more"}, ""},
+	}
+	for _, test := range tests {
+		if got := getSummary(test.post, test.sources); got != test.want {
+			t.Errorf("getSummary with %v = %q, want %q", test.sources, got, test.want)
+		}
+	}
+}
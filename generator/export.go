@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// importRule matches the @import rules of stylesheets, which would load
+// external stylesheets in the exports
+var importRule = regexp.MustCompile(`@import[^;]*;`)
+
+// sourceMapComment matches the source map reference of minified stylesheets
+var sourceMapComment = regexp.MustCompile(`/\*# sourceMappingURL=[^*]*\*/`)
+
+// ExportConfig holds the configuration for single-file exports of posts
+type ExportConfig struct {
+	Destination string
+	WarnSize    int64
+	// Template renders the exports, which reference no other files
+	Template *template.Template
+	// CSS is inlined into every export
+	CSS template.CSS
+}
+
+// newExportCSS returns the stylesheets inlined into the exports, without
+// the @import rules and source maps
+func newExportCSS(stylesheets ...[]byte) template.CSS {
+	var b strings.Builder
+	for _, css := range stylesheets {
+		css = importRule.ReplaceAll(css, nil)
+		css = sourceMapComment.ReplaceAll(css, nil)
+		b.Write(bytes.TrimSpace(css))
+		b.WriteString("\n")
+	}
+	return template.CSS(b.String())
+}
+
+// generateExport writes the post as one standalone html file with its CSS
+// inlined, its images embedded as data URIs and absolute links
+func (g *PostGenerator) generateExport(td *IndexData) error {
+	post := g.Config.Post
+	export := g.Config.Export
+	content, err := embedImages(post, export.WarnSize)
+	if err != nil {
+		return fmt.Errorf("error exporting post %s: %v", post.Name, err)
+	}
+	absolute, err := makeLinksAbsolute([]byte(content), post.URL)
+	if err != nil {
+		return fmt.Errorf("error exporting post %s: %v", post.Name, err)
+	}
+	exported := *td
+	exported.Content = template.HTML(absolute)
+	exported.CriticalCSS = export.CSS
+	// the links of the export have to work outside of the site
+	writer := *g.Config.Writer
+	writer.RelativeURLs = false
+	filePath := filepath.Join(export.Destination, filepath.FromSlash(post.Name)+".html")
+	return writer.WriteHTMLFile(filePath, &exported, export.Template)
+}
+
+// embedImages returns the html of the post with the src of its local images
+// replaced by base64 data URIs, images larger than warnSize are reported
+func embedImages(post *Post, warnSize int64) (template.HTML, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(post.HTML))
+	if err != nil {
+		return "", fmt.Errorf("error while parsing html: %v", err)
+	}
+	var embedErr error
+	doc.Find("img").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		src := s.AttrOr("src", "")
+		path := getImageFile(post, src)
+		if path == "" {
+			fmt.Printf("\tWarning: image %s of %s is not embedded in the export\n", src, post.Name)
+			return true
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			embedErr = fmt.Errorf("error reading image %s: %v", path, err)
+			return false
+		}
+		if warnSize > 0 && int64(len(data)) > warnSize {
+			fmt.Printf("\tWarning: embedding large image %s (%d bytes) in the export of %s\n", src, len(data), post.Name)
+		}
+		s.SetAttr("src", fmt.Sprintf("data:%s;base64,%s", getImageMimeType(path, data), base64.StdEncoding.EncodeToString(data)))
+		return true
+	})
+	if embedErr != nil {
+		return "", embedErr
+	}
+	html, err := doc.Find("body").Html()
+	if err != nil {
+		return "", fmt.Errorf("error while generating html: %v", err)
+	}
+	return template.HTML(html), nil
+}
+
+func getImageMimeType(path string, data []byte) string {
+	if t := mime.TypeByExtension(strings.ToLower(filepath.Ext(path))); strings.HasPrefix(t, "image/") {
+		return t
+	}
+	return http.DetectContentType(data)
+}
@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestExportIsStandalone(t *testing.T) {
+	cfg := newTestConfig(t, func(cfg *config.Config) { cfg.Generator.Export.Enabled = true })
+	site := newTestSite(t, cfg, map[string]string{
+		"post/post.md":        testPost("Post", "01.02.2021", "![A cat](images/cat.png)\n\nSee [the other post](/other/).", "short: a post"),
+		"post/images/cat.png": testPNG(t, 4, 4),
+		"other/post.md":       testPost("Other", "02.02.2021", "Text.", "short: other"),
+	}).build()
+	export := site.read("export/post.html")
+	if !strings.Contains(export, `src="data:image/png;base64,`) {
+		t.Errorf("image isn't embedded:\n%s", export)
+	}
+	if !strings.Contains(export, `href="https://example.com/other/"`) {
+		t.Errorf("link isn't absolute:\n%s", export)
+	}
+	if !strings.Contains(export, "<style>") {
+		t.Errorf("css isn't inlined:\n%s", export)
+	}
+	external := regexp.MustCompile(`<link|<script|@import|url\(|src="(?:[^d]|d[^a])`)
+	if match := external.FindString(export); match != "" {
+		t.Errorf("export references other files with %s:\n%s", match, export)
+	}
+}
+
+func TestNewExportCSS(t *testing.T) {
+	css := newExportCSS([]byte("@import url(//fonts.example.com/css);body{color:red}\n/*# sourceMappingURL=vec.css.map */"), []byte(".k{color:blue}"))
+	want := "body{color:red}\n.k{color:blue}\n"
+	if string(css) != want {
+		t.Errorf("got %q, want %q", css, want)
+	}
+}
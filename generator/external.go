@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"fmt"
+	"html/template"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// externalIndicatorClass is the class of the indicator appended to external
+// links, links followed by it are not marked again
+const externalIndicatorClass = "external-link"
+
+// ExternalLinkOptions configure the handling of links to other hosts than
+// Host. With NewTab they open in a new tab and a non-empty Indicator is
+// appended after them, e.g. "↗".
+type ExternalLinkOptions struct {
+	Host      string
+	NewTab    bool
+	Indicator string
+}
+
+// newExternalLinks returns the transform marking the external links of posts
+func newExternalLinks(opts ExternalLinkOptions) Transform {
+	return func(post *Post, doc *goquery.Document) error {
+		if !opts.NewTab && opts.Indicator == "" {
+			return nil
+		}
+		indicator := fmt.Sprintf(`<span class="%s" aria-hidden="true">%s</span>`, externalIndicatorClass, template.HTMLEscapeString(opts.Indicator))
+		doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
+			if !isExternalLink(s.AttrOr("href", ""), opts.Host) {
+				return
+			}
+			if opts.NewTab {
+				s.SetAttr("target", "_blank")
+				s.SetAttr("rel", "noopener noreferrer")
+			}
+			if opts.Indicator != "" && !s.Next().HasClass(externalIndicatorClass) {
+				s.AfterHtml(indicator)
+			}
+		})
+		return nil
+	}
+}
+
+// getURLHost returns the host name of rawURL, empty if it can't be parsed
+func getURLHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// isExternalLink reports if href is an absolute http(s) link to another host
+func isExternalLink(href, host string) bool {
+	u, err := url.Parse(href)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return !strings.EqualFold(u.Hostname(), host)
+}
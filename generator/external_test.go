@@ -0,0 +1,25 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExternalLinkIndicator(t *testing.T) {
+	transform := newExternalLinks(ExternalLinkOptions{Host: "example.com", NewTab: true, Indicator: "↗"})
+	html := `<p><a href="https://go.dev/">Go</a> <a href="https://example.com/about/">About</a> <a href="/tags/">Tags</a> <a href="mailto:me@example.org">Mail</a></p>`
+	once := applyTestTransform(t, transform, html)
+	want := `<p><a href="https://go.dev/" target="_blank" rel="noopener noreferrer">Go</a><span class="external-link" aria-hidden="true">↗</span> <a href="https://example.com/about/">About</a> <a href="/tags/">Tags</a> <a href="mailto:me@example.org">Mail</a></p>`
+	if once != want {
+		t.Errorf("got\n%s\nwant\n%s", once, want)
+	}
+	if twice := applyTestTransform(t, transform, once); strings.Count(twice, externalIndicatorClass) != 1 {
+		t.Errorf("second run added another indicator:\n%s", twice)
+	}
+	if got := applyTestTransform(t, newExternalLinks(ExternalLinkOptions{Host: "example.com"}), html); got != html {
+		t.Errorf("disabled external links changed the links:\n%s", got)
+	}
+	if got := applyTestTransform(t, newExternalLinks(ExternalLinkOptions{Host: "Example.COM", Indicator: "↗"}), html); strings.Count(got, externalIndicatorClass) != 1 {
+		t.Errorf("host isn't compared case-insensitively:\n%s", got)
+	}
+}
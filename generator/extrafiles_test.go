@@ -0,0 +1,45 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtraFiles(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"demo/post.md":    testPost("Demo", "01.02.2021", "A demo.", "extraCSS: [demo.css]", "extraJS: [js/demo.js]"),
+		"demo/demo.css":   "body { color: red; }",
+		"demo/js/demo.js": "console.log('demo')",
+		"plain/post.md":   testPost("Plain", "02.02.2021", "Plain."),
+	}).build()
+	if site.read("demo/demo.css") != "body { color: red; }" || !site.exists("demo/js/demo.js") {
+		t.Error("extra files weren't copied")
+	}
+	page := site.read("demo/index.html")
+	if !strings.Contains(page, `<link rel="stylesheet" href="/demo/demo.css">`) {
+		t.Error("extra css isn't linked")
+	}
+	if !strings.Contains(page, `<script src="/demo/js/demo.js"></script>`) {
+		t.Error("extra js isn't linked")
+	}
+	if plain := site.read("plain/index.html"); strings.Contains(plain, "demo.css") || strings.Contains(plain, "demo.js") {
+		t.Error("extra files are linked on another post")
+	}
+}
+
+func TestExtraFileErrors(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"missing/post.md": testPost("Missing", "01.02.2021", "Missing.", "extraCSS: [missing.css]"),
+		"outside/post.md": testPost("Outside", "01.02.2021", "Outside.", "extraJS: [../other.js]"),
+		"other.js":        "",
+	})
+	loader := newTestLoader(t, root, newTestConfig(t, nil))
+	if _, err := loader.newPost(filepath.Join(root, "missing")); err == nil || !strings.Contains(err.Error(), "missing.css") {
+		t.Errorf("missing extra file gives error %v", err)
+	}
+	if _, err := loader.newPost(filepath.Join(root, "outside")); err == nil || !strings.Contains(err.Error(), "inside of the post folder") {
+		t.Errorf("extra file outside of the post gives error %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/beevik/etree"
+)
+
+// feedFile is the name of the generated feeds
+const feedFile = "index.xml"
+
+// reportInvalidFeeds validates every generated feed in destination, in strict
+// mode invalid feeds fail the generation
+func reportInvalidFeeds(destination string, strict bool) error {
+	count := 0
+	err := filepath.Walk(destination, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Name() != feedFile {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		rel, err := filepath.Rel(destination, path)
+		if err != nil {
+			return err
+		}
+		for _, problem := range validateFeed(f) {
+			fmt.Printf("feed: %s: %s\n", filepath.ToSlash(rel), problem)
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error validating feeds of %s: %v", destination, err)
+	}
+	if strict && count > 0 {
+		return fmt.Errorf("found %d feed problems", count)
+	}
+	return nil
+}
+
+// validateFeed returns the problems of an RSS or Atom feed: xml which can't
+// be parsed, missing required elements, dates which can't be parsed and
+// links which aren't absolute
+func validateFeed(r io.Reader) []string {
+	doc := etree.NewDocument()
+	if _, err := doc.ReadFrom(r); err != nil {
+		return []string{fmt.Sprintf("error parsing xml: %v", err)}
+	}
+	root := doc.Root()
+	if root == nil {
+		return []string{"no root element"}
+	}
+	switch root.Tag {
+	case "rss":
+		return validateRSS(root)
+	case "feed":
+		return validateAtom(root)
+	}
+	return []string{fmt.Sprintf("unknown root element <%s>", root.Tag)}
+}
+
+func validateRSS(rss *etree.Element) []string {
+	problems := []string{}
+	if version := rss.SelectAttrValue("version", ""); version != "2.0" {
+		problems = append(problems, fmt.Sprintf("rss version has to be 2.0, not %q", version))
+	}
+	channel := rss.SelectElement("channel")
+	if channel == nil {
+		return append(problems, "missing <channel>")
+	}
+	for _, name := range []string{"title", "link", "description"} {
+		problems = append(problems, checkFeedText(channel, "channel", name)...)
+	}
+	problems = append(problems, checkFeedLink(channel, "channel")...)
+	for i, item := range channel.SelectElements("item") {
+		where := fmt.Sprintf("item %d", i+1)
+		if getFeedText(item, "title") == "" && getFeedText(item, "description") == "" {
+			problems = append(problems, where+" has neither <title> nor <description>")
+		}
+		problems = append(problems, checkFeedLink(item, where)...)
+		if date := getFeedText(item, "pubDate"); date != "" {
+			if !isRSSDate(date) {
+				problems = append(problems, fmt.Sprintf("%s has an invalid <pubDate> %q", where, date))
+			}
+		}
+	}
+	return problems
+}
+
+func validateAtom(feed *etree.Element) []string {
+	problems := []string{}
+	for _, name := range []string{"title", "id", "updated"} {
+		problems = append(problems, checkFeedText(feed, "feed", name)...)
+	}
+	problems = append(problems, checkAtomDate(feed, "feed")...)
+	for i, entry := range feed.SelectElements("entry") {
+		where := fmt.Sprintf("entry %d", i+1)
+		for _, name := range []string{"title", "id", "updated"} {
+			problems = append(problems, checkFeedText(entry, where, name)...)
+		}
+		problems = append(problems, checkAtomDate(entry, where)...)
+	}
+	return problems
+}
+
+// isRSSDate reports if date is an RFC 1123 date with a numeric or named zone
+func isRSSDate(date string) bool {
+	if _, err := time.Parse(rssDateFormat, date); err == nil {
+		return true
+	}
+	_, err := time.Parse(time.RFC1123, date)
+	return err == nil
+}
+
+// getFeedText returns the text of the child name of element
+func getFeedText(element *etree.Element, name string) string {
+	if child := element.SelectElement(name); child != nil {
+		return child.Text()
+	}
+	return ""
+}
+
+func checkFeedText(element *etree.Element, where, name string) []string {
+	if getFeedText(element, name) == "" {
+		return []string{fmt.Sprintf("%s is missing <%s>", where, name)}
+	}
+	return nil
+}
+
+func checkFeedLink(element *etree.Element, where string) []string {
+	link := getFeedText(element, "link")
+	if link == "" {
+		return nil
+	}
+	if u, err := url.Parse(link); err != nil || !u.IsAbs() {
+		return []string{fmt.Sprintf("%s has a <link> which isn't absolute: %q", where, link)}
+	}
+	return nil
+}
+
+func checkAtomDate(element *etree.Element, where string) []string {
+	date := getFeedText(element, "updated")
+	if date == "" {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, date); err != nil {
+		return []string{fmt.Sprintf("%s has an invalid <updated> %q", where, date)}
+	}
+	return nil
+}
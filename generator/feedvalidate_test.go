@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateFeed(t *testing.T) {
+	tests := []struct {
+		name string
+		feed string
+		want []string
+	}{
+		{"valid rss", `<rss version="2.0"><channel><title>T</title><link>https://example.com/</link><description>D</description>` +
+			`<item><title>A</title><link>https://example.com/a/</link><pubDate>Mon, 01 Feb 2021 00:00:00 +0000</pubDate></item></channel></rss>`, []string{}},
+		{"malformed rss", `<rss version="0.91"><channel><title>T</title><link>/</link>` +
+			`<item><link>https://example.com/a/</link><pubDate>2021-02-01</pubDate></item></channel></rss>`, []string{
+			`rss version has to be 2.0, not "0.91"`,
+			"channel is missing <description>",
+			`channel has a <link> which isn't absolute: "/"`,
+			"item 1 has neither <title> nor <description>",
+			`item 1 has an invalid <pubDate> "2021-02-01"`,
+		}},
+		{"valid atom", `<feed xmlns="http://www.w3.org/2005/Atom"><title>T</title><id>urn:t</id><updated>2021-02-01T00:00:00Z</updated>` +
+			`<entry><title>A</title><id>urn:a</id><updated>2021-02-01T00:00:00Z</updated></entry></feed>`, []string{}},
+		{"malformed atom", `<feed><title>T</title><id>urn:t</id><updated>yesterday</updated><entry><title>A</title></entry></feed>`, []string{
+			`feed has an invalid <updated> "yesterday"`,
+			"entry 1 is missing <id>",
+			"entry 1 is missing <updated>",
+		}},
+		{"unknown root", `<opml version="2.0"></opml>`, []string{"unknown root element <opml>"}},
+	}
+	for _, test := range tests {
+		if got := validateFeed(strings.NewReader(test.feed)); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got problems %q, want %q", test.name, got, test.want)
+		}
+	}
+	if got := validateFeed(strings.NewReader(`<rss><channel>`)); len(got) != 1 || !strings.HasPrefix(got[0], "error parsing xml") {
+		t.Errorf("broken xml: got problems %q", got)
+	}
+}
+
+func TestGeneratedFeedIsValid(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"post/post.md": testPost("Post", "01.02.2021", "Text."),
+	}).build()
+	if problems := validateFeed(strings.NewReader(site.read("index.xml"))); len(problems) > 0 {
+		t.Errorf("generated feed has problems %q", problems)
+	}
+}
@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// galleryExtensions are the file extensions of images shown in the gallery
+var galleryExtensions = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".gif": true, ".webp": true, ".svg": true}
+
+// GalleryImage is an image of the gallery and the posts it appears in
+type GalleryImage struct {
+	Name  string
+	Src   string
+	Posts []*PostLink
+}
+
+// GalleryGenerator object
+type GalleryGenerator struct {
+	Config *GalleryConfig
+}
+
+// GalleryConfig holds the configuration of the gallery page
+type GalleryConfig struct {
+	Posts       []*Post
+	Template    *template.Template
+	Destination string
+	Writer      *IndexWriter
+}
+
+// Generate writes gallery/index.html with every image of the posts
+func (g *GalleryGenerator) Generate() error {
+	fmt.Println("\tGenerating Gallery...")
+	galleryTemplatePath := filepath.Join("static", "gallery.html")
+	tmpl, err := getTemplate(galleryTemplatePath, g.Config.Writer.Funcs)
+	if err != nil {
+		return err
+	}
+	images, err := collectGalleryImages(g.Config.Posts)
+	if err != nil {
+		return err
+	}
+	buf := bytes.Buffer{}
+	if err := tmpl.Execute(&buf, images); err != nil {
+		return fmt.Errorf("error executing template %s: %v", galleryTemplatePath, err)
+	}
+	path := filepath.Join(g.Config.Destination, "gallery")
+	if err := g.Config.Writer.WriteIndexHTML(path, "Gallery", "Gallery", template.HTML(buf.String()), g.Config.Template); err != nil {
+		return err
+	}
+	fmt.Println("\tFinished generating Gallery...")
+	return nil
+}
+
+// collectGalleryImages returns the images of all posts, images with the same
+// content are listed once with links to all posts using them
+func collectGalleryImages(posts []*Post) ([]*GalleryImage, error) {
+	result := []*GalleryImage{}
+	byHash := map[[sha256.Size]byte]*GalleryImage{}
+	for _, post := range posts {
+		for _, name := range post.Images {
+			if !galleryExtensions[strings.ToLower(filepath.Ext(name))] {
+				continue
+			}
+			path := filepath.Join(post.ImagesDir, name)
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("error reading image %s: %v", path, err)
+			}
+			link := &PostLink{Title: post.Meta.Title, Link: post.Path}
+			hash := sha256.Sum256(data)
+			if image, ok := byHash[hash]; ok {
+				image.Posts = append(image.Posts, link)
+				continue
+			}
+			image := &GalleryImage{Name: name, Src: post.Path + getImagesPath(post) + name, Posts: []*PostLink{link}}
+			byHash[hash] = image
+			result = append(result, image)
+		}
+	}
+	return result, nil
+}
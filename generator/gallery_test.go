@@ -0,0 +1,47 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestGallery(t *testing.T) {
+	cat, dog := testPNG(t, 4, 4), testPNG(t, 6, 6)
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.Gallery = true
+	}), map[string]string{
+		"cats/post.md":          testPost("Cats", "01.02.2021", "![cat](images/cat.png)"),
+		"cats/images/cat.png":   cat,
+		"pets/post.md":          testPost("Pets", "02.02.2021", "![cat](images/kitty.png) ![dog](images/dog.png)"),
+		"pets/images/kitty.png": cat,
+		"pets/images/dog.png":   dog,
+		"pets/images/notes.txt": "not an image",
+	}).build()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(site.read("gallery/index.html")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	doc.Find("#gallery li").Each(func(i int, li *goquery.Selection) {
+		links := []string{}
+		li.Find("p a").Each(func(i int, a *goquery.Selection) {
+			links = append(links, a.AttrOr("href", "")+" "+a.Text())
+		})
+		got[li.Find("img").AttrOr("src", "")] = strings.Join(links, ", ")
+	})
+	want := map[string]string{
+		"/pets/images/kitty.png": "/pets/ Pets, /cats/ Cats",
+		"/pets/images/dog.png":   "/pets/ Pets",
+	}
+	if len(got) != len(want) {
+		t.Errorf("gallery lists %v, want %v", got, want)
+	}
+	for src, links := range want {
+		if got[src] != links {
+			t.Errorf("gallery image %s links %q, want %q", src, got[src], links)
+		}
+	}
+}
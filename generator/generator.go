@@ -2,9 +2,11 @@ package generator
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"github.com/eleztian/blog-generator/config"
 	"html/template"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"sort"
@@ -15,11 +17,31 @@ import (
 
 // Meta is a data container for Metadata
 type Meta struct {
-	Title      string
-	Short      string
-	Date       string
-	Tags       []string
-	ParsedDate time.Time
+	Title           string
+	Slug            string
+	Short           string
+	Date            string
+	Updated         string
+	Image           string
+	Tags            []string
+	Unlisted        bool
+	NoIndex         bool
+	ExtraCSS        []string `yaml:"extraCSS"`
+	ExtraJS         []string `yaml:"extraJS"`
+	Enclosure       *Enclosure
+	Status          string
+	Aliases         []string
+	Category        string
+	License         string
+	KeepImages      []string
+	NoTrailer       bool
+	ImagesDir       string
+	Stub            bool
+	Comments        string
+	MetaTags        []MetaTag `yaml:"metaTags"`
+	DefaultCodeLang string    `yaml:"defaultCodeLang"`
+	ParsedDate      time.Time
+	ParsedUpdated   time.Time
 }
 
 // IndexData is a data container for the landing page
@@ -35,6 +57,38 @@ type IndexData struct {
 	Github          string
 	Twitter         string
 	GooglePluse     string
+	Tags            []*Tag
+	TagCounts       map[string]int
+	PostCount       int
+	RecentPosts     []*ListingData
+	JSONLD          template.JS
+	OGImage         string
+	PrintCSS        bool
+	License         *License
+	NoIndex         bool
+	MetaTags        []MetaTag
+	AMPLink         string
+	Icons           []IconLink
+	ThemeColor      string
+	ExtraCSS        []string
+	ExtraJS         []string
+	Attachments     []*Attachment
+	TagNav          []*TagNavigation
+	Related         []*PostLink
+	TOC             template.HTML
+	PageDescription string
+	Trailer         template.HTML
+	Comments        *Comments
+	EditLink        template.URL
+	Entry           *Entry
+	Webmention      string
+	Pingback        string
+	Webmentions     []*Webmention
+	Build           *BuildInfo
+	CriticalCSS     template.CSS
+	PrevLink        string
+	NextLink        string
+	Feeds           []FeedLink
 }
 
 // Generator interface
@@ -45,13 +99,24 @@ type Generator interface {
 // SiteGenerator object
 type SiteGenerator struct {
 	Config *SiteConfig
+	// timings of the running generation
+	timings *PhaseTimings
 }
 
 // SiteConfig holds the sources and destination folder
 type SiteConfig struct {
 	Sources     []string
+	SourceRoot  string
 	Destination string
 	Config      *config.Config
+	// PostBuild is called with the stats of every successful generation
+	PostBuild func(stats *BuildStats) error
+	// LockHeld is set by callers holding the lock of the destination from
+	// LockDestination, Generate doesn't take it then
+	LockHeld bool
+	// Transforms are applied to the html of every post after the built-in
+	// ones
+	Transforms []Transform
 }
 
 // New creates a new SiteGenerator
@@ -59,95 +124,516 @@ func New(config *SiteConfig) *SiteGenerator {
 	return &SiteGenerator{Config: config}
 }
 
-// Generate starts the static blog generation
+// RegisterTransform appends a transform to the chain applied to the html of
+// every post of this generator, it must be called before Generate
+func (g *SiteGenerator) RegisterTransform(t Transform) {
+	g.Config.Transforms = append(g.Config.Transforms, t)
+}
+
+// Generate starts the static blog generation and runs the post-build hooks
+// once it succeeded. The destination is locked meanwhile, a concurrent
+// generation waits at most Generator.LockWait seconds for the lock.
 func (g *SiteGenerator) Generate() error {
+	if !g.Config.LockHeld {
+		lock, err := acquireBuildLock(g.Config.Destination, time.Duration(g.Config.Config.Generator.LockWait)*time.Second)
+		if err != nil {
+			return err
+		}
+		defer lock.release()
+	}
+	start := time.Now()
+	g.timings = newPhaseTimings()
+	posts, err := g.generate()
+	if err != nil {
+		return err
+	}
+	stats, err := collectBuildStats(g.Config.Destination, posts, time.Since(start))
+	if err != nil {
+		return err
+	}
+	stats.Phases = g.timings.Durations()
+	fmt.Printf("Generated %d posts in %v (%s)\n", stats.Posts, stats.Duration.Round(time.Millisecond), g.timings)
+	return runPostBuild(g.Config, stats)
+}
+
+// generate builds the site and returns the number of generated posts
+func (g *SiteGenerator) generate() (int, error) {
 	templatePath := filepath.Join("static", "template.html")
 	fmt.Println("Generating Site...")
-	sources := g.Config.Sources
 	destination := g.Config.Destination
-	if err := clearAndCreateDestination(destination); err != nil {
-		return err
+	dirMode := g.Config.Config.Generator.DirMode
+	if err := clearAndCreateDestination(destination, dirMode); err != nil {
+		return 0, err
 	}
-	if err := clearAndCreateDestination(filepath.Join(destination, "archive")); err != nil {
-		return err
+	assets, err := newAssetManifest(g.Config.Config)
+	if err != nil {
+		return 0, err
+	}
+	paths := newSitePaths(g.Config.Config)
+	funcs := newTemplateFuncs(assets, newSVGSprite(g.Config.Config.Blog.Sprite), paths)
+	if g.Config.Config.Blog.Maintenance.Enabled {
+		if err := generateMaintenance(g.Config.Config, destination, assets, funcs); err != nil {
+			return 0, err
+		}
+		fmt.Println("Finished generating Site in maintenance mode...")
+		return 0, nil
+	}
+	if err := clearAndCreateDestination(filepath.Join(destination, paths.archiveDir()), dirMode); err != nil {
+		return 0, err
 	}
-	t, err := getTemplate(templatePath)
+	t, err := getTemplate(templatePath, funcs)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	loading := time.Now()
+	posts, err := g.loadPosts()
+	if err != nil {
+		return 0, err
+	}
+	g.timings.since(phaseLoading, loading)
+	posts = filterDrafts(posts, g.Config.Config.Generator.IncludeDrafts)
+	if review := getReviewPosts(posts); len(review) > 0 {
+		if err := clearAndCreateDestination(g.Config.Config.Generator.Preview, dirMode); err != nil {
+			return 0, err
+		}
+		// previews must not end up in search engines
+		for _, post := range review {
+			post.Meta.NoIndex = true
+		}
+	}
+	if err := reportEmptyPosts(posts, g.Config.Config.Generator.Strict); err != nil {
+		return 0, err
+	}
+	if g.Config.Config.Generator.Lint {
+		if err := reportLintWarnings(posts, g.Config.Config.Generator.Strict); err != nil {
+			return 0, err
+		}
+	}
+	if g.Config.Config.Blog.CleanURLs {
+		for _, post := range posts {
+			post.Path = normalizeLink(post.Path)
+			post.URL = normalizeLink(post.URL)
+			if err := normalizePostLinks(post, g.Config.Config.Blog.URL); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if err := checkDuplicates(posts); err != nil {
+		return 0, err
+	}
+	if g.Config.Config.Generator.DuplicateContent {
+		if err := reportDuplicateContent(posts, g.Config.Config.Generator.Strict); err != nil {
+			return 0, err
+		}
+	}
+	sort.Sort(ByDateDesc(posts))
+	if g.Config.Config.Generator.Dev {
+		setEditLinks(posts, g.Config.Config.Generator.Editor, g.Config.SourceRoot, g.Config.Config.Generator.Repo)
+	}
+	sharedImages, err := getSharedImagesDir(g.Config.SourceRoot)
+	if err != nil {
+		return 0, err
+	}
+	var build *BuildInfo
+	if g.Config.Config.Generator.BuildInfo {
+		build = newBuildInfo(g.Config.SourceRoot, time.Now())
+	}
+	tagInfo, err := readTagInfo(g.Config.SourceRoot)
+	if err != nil {
+		return 0, err
+	}
+	if err := runTasks(posts, t, destination, sharedImages, g.Config.Config, assets, funcs, g.timings, build, tagInfo); err != nil {
+		return 0, err
+	}
+	if err := writeAliases(getPublishedPosts(posts), destination, dirMode, g.Config.Config.Generator.FileMode); err != nil {
+		return 0, err
+	}
+	if g.Config.Config.Blog.RedirectStubs {
+		if err := writeRedirectStubs(destination, g.Config.Config.Blog.URL, g.Config.Config.Generator.FileMode); err != nil {
+			return 0, err
+		}
+	}
+	if g.Config.Config.Generator.Validate {
+		if err := reportInvalidHTML(destination, g.Config.Config.Generator.Strict); err != nil {
+			return 0, err
+		}
+	}
+	if g.Config.Config.Generator.ValidateFeeds {
+		if err := reportInvalidFeeds(destination, g.Config.Config.Generator.Strict); err != nil {
+			return 0, err
+		}
+	}
+	if g.Config.Config.Generator.ETags {
+		if err := writeETags(destination, g.Config.Config.Generator.FileMode); err != nil {
+			return 0, err
+		}
+	}
+	fmt.Println("Finished generating Site...")
+	return len(posts), nil
+}
+
+// loadPosts reads all posts of the sources, posts which can't be read are
+// reported and skipped
+func (g *SiteGenerator) loadPosts() ([]*Post, error) {
+	sources := g.Config.Sources
+	dateFormat := g.Config.Config.Blog.Dateformat
+	if dateFormat == autoDateFormat {
+		var err error
+		if dateFormat, err = detectDateFormat(sources); err != nil {
+			return nil, err
+		}
+	}
+	location, err := time.LoadLocation(g.Config.Config.Blog.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("error loading timezone %s: %v", g.Config.Config.Blog.Timezone, err)
+	}
+	defaults, err := readDefaults(g.Config.SourceRoot)
+	if err != nil {
+		return nil, err
+	}
+	sharedImages, err := getSharedImagesDir(g.Config.SourceRoot)
+	if err != nil {
+		return nil, err
+	}
+	render, err := newRenderOptions(g.Config.Config)
+	if err != nil {
+		return nil, err
+	}
+	render.Timings = g.timings
+	render.Transforms = g.Config.Transforms
+	var history *slugHistory
+	if g.Config.Config.Generator.SlugHistory {
+		if history, err = readSlugHistory(g.Config.Config.Generator.SlugHistoryFile); err != nil {
+			return nil, err
+		}
+	}
+	loader := &postLoader{
+		BlogURL:        g.Config.Config.Blog.URL,
+		Root:           g.Config.SourceRoot,
+		DateFormat:     dateFormat,
+		Location:       location,
+		Defaults:       defaults,
+		Includes:       g.newIncludeResolver(),
+		License:        g.Config.Config.Blog.License,
+		SharedImages:   sharedImages,
+		Render:         render,
+		ExcerptLength:  g.Config.Config.Blog.Excerpt.Length,
+		Ellipsis:       g.Config.Config.Blog.Excerpt.Ellipsis,
+		ExcerptSources: g.Config.Config.Blog.Excerpt.Sources,
+		FilesDir:       g.Config.Config.Generator.Files,
+		ImagesDir:      g.Config.Config.Generator.Images.Dir,
+		SlugHistory:    history,
+		Base:           getGlobBase(g.Config.Config.Generator.Glob),
+		CJK:            g.Config.Config.Blog.Slugs.CJK,
+		Variables:      newBuildVariables(g.Config.Config, time.Now().In(location)),
 	}
 	var posts []*Post
 	for _, path := range sources {
-		post, err := newPost(path, g.Config.Config.Blog.Dateformat)
+		post, err := loader.newPost(path)
 		if err != nil {
 			fmt.Println("error read: ", path, err)
 			continue
 		}
 		posts = append(posts, post)
 	}
-	sort.Sort(ByDateDesc(posts))
-	if err := runTasks(posts, t, destination, g.Config.Config); err != nil {
-		return err
+	if history != nil {
+		if err := history.save(g.Config.Config.Generator.FileMode); err != nil {
+			return nil, err
+		}
+	}
+	return posts, nil
+}
+
+// getGlobBase returns the leading folders of glob without wildcards, e.g.
+// content for content/**/*.md
+func getGlobBase(glob string) string {
+	parts := strings.Split(strings.Trim(glob, "/"), "/")
+	base := []string{}
+	for _, part := range parts[:len(parts)-1] {
+		if strings.ContainsAny(part, "*?[\\") {
+			break
+		}
+		base = append(base, part)
+	}
+	return filepath.FromSlash(strings.Join(base, "/"))
+}
+
+// newIncludeResolver resolves includes relative to the posts and the shared
+// includes folder below the source root
+func (g *SiteGenerator) newIncludeResolver() *includeResolver {
+	includes := g.Config.Config.Generator.Includes
+	dir := ""
+	if g.Config.SourceRoot != "" && includes.Dir != "" {
+		dir = filepath.Join(g.Config.SourceRoot, includes.Dir)
+	}
+	return &includeResolver{Dir: dir, MaxDepth: includes.Depth}
+}
+
+// reportLintWarnings prints the lint warnings of all posts, in strict mode
+// warnings fail the generation
+func reportLintWarnings(posts []*Post, strict bool) error {
+	count := 0
+	for _, post := range posts {
+		for _, warning := range lintPost(post) {
+			fmt.Println("lint:", warning)
+			count++
+		}
+	}
+	if strict && count > 0 {
+		return fmt.Errorf("found %d lint warnings", count)
 	}
-	fmt.Println("Finished generating Site...")
 	return nil
 }
 
-func runTasks(posts []*Post, t *template.Template, destination string, cfg *config.Config) error {
+func runTasks(posts []*Post, t *template.Template, destination, sharedImages string, cfg *config.Config, assets AssetManifest, funcs template.FuncMap, timings *PhaseTimings, build *BuildInfo, tagInfo map[string]*TagInfo) error {
 	var wg sync.WaitGroup
-	finished := make(chan bool, 1)
-	errors := make(chan error, 1)
 	pool := make(chan struct{}, 50)
 	npg := cfg.Generator.NPG
 	generators := []Generator{}
+	listed := getListedPosts(posts)
+	tagPostsMap := createTagPostsMap(listed)
+	reportUnknownTagInfo(tagInfo, tagPostsMap)
+	tagNames := make([]string, 0, len(tagPostsMap))
+	for tag := range tagPostsMap {
+		tagNames = append(tagNames, tag)
+	}
+	paths := newSitePaths(cfg).withTags(tagNames)
+	tags := createTagList(tagPostsMap, paths)
+	createTagNavigation(listed, tagPostsMap, paths)
+	createRelatedPosts(listed, cfg.Blog.Related.Strategy, cfg.Blog.Related.Posts)
+	setReadingTimes(posts, ReadingTimeOptions{
+		Rounding:  cfg.Blog.ReadingTime.Rounding,
+		Threshold: cfg.Blog.ReadingTime.Threshold,
+		Below:     cfg.Blog.ReadingTime.Below,
+		Code:      cfg.Blog.ReadingTime.Code,
+	})
 
 	indexWriter := &IndexWriter{
-		BlogURL:         cfg.Blog.URL,
-		BlogTitle:       cfg.Blog.Title,
-		BlogDescription: cfg.Blog.Description,
-		BlogAuthor:      cfg.Blog.Author,
-		Github:          cfg.Blog.Github,
-		Twitter:         cfg.Blog.Twitter,
-		GooglePluse:     cfg.Blog.GooglePluse,
+		BlogURL:           cfg.Blog.URL,
+		BlogTitle:         cfg.Blog.Title,
+		BlogDescription:   cfg.Blog.Description,
+		BlogAuthor:        cfg.Blog.Author,
+		Github:            cfg.Blog.Github,
+		Twitter:           cfg.Blog.Twitter,
+		GooglePluse:       cfg.Blog.GooglePluse,
+		Paths:             paths,
+		DirMode:           cfg.Generator.DirMode,
+		FileMode:          cfg.Generator.FileMode,
+		Funcs:             funcs,
+		Icons:             getIconLinks(cfg.Blog.Manifest.Icon),
+		ThemeColor:        cfg.Blog.Manifest.ThemeColor,
+		Tags:              tags,
+		TagCounts:         createTagCounts(tags),
+		PostCount:         len(listed),
+		RecentPosts:       getRecentPosts(listed, cfg.Blog.Recentposts, cfg.Blog.Excerpt.Length, cfg.Blog.Excerpt.Ellipsis, paths),
+		CleanURLs:         cfg.Blog.CleanURLs,
+		Destination:       destination,
+		DescriptionLength: cfg.Blog.Excerpt.Description,
+		PrintCSS:          cfg.Blog.Print.Enabled,
+		RelativeURLs:      cfg.Blog.RelativeURLs,
+		Feeds:             getFeedLinks(cfg.Blog.URL, cfg.Blog.Title, cfg.Blog.Feeds.Updated),
+		CSP:               newContentSecurityPolicy(cfg.Blog.CSP.Policy),
+		Timings:           timings,
+		Webmention:        cfg.Blog.Webmention.Endpoint,
+		Pingback:          cfg.Blog.Webmention.Pingback,
+		Build:             build,
+		DefaultImage:      getDefaultImageURL(cfg.Blog.DefaultImage, cfg.Blog.URL),
+	}
+	if cfg.Blog.CriticalCSS.Enabled {
+		css, err := ioutil.ReadFile(cfg.Blog.CriticalCSS.File)
+		if err != nil {
+			return fmt.Errorf("error reading critical css %s: %v", cfg.Blog.CriticalCSS.File, err)
+		}
+		indexWriter.CriticalCSS = template.CSS(css)
+	}
+
+	var ampTemplate *template.Template
+	if cfg.Blog.AMP {
+		var err error
+		ampTemplate, err = getTemplate(filepath.Join("static", "amp.html"), funcs)
+		if err != nil {
+			return err
+		}
+	}
+
+	imagesCfg := cfg.Generator.Images
+	images := NewImageProcessor(imagesCfg.Workers, imagesCfg.MaxWidth, imagesCfg.MaxPixels, cfg.Generator.FileMode)
+	images.Strict = cfg.Generator.Strict
+	images.Timings = timings
+	if imagesCfg.OpenFiles > 0 {
+		images.SetOpenFiles(imagesCfg.OpenFiles)
+	}
+	if imagesCfg.JPEGQuality > 0 {
+		images.JPEGQuality = imagesCfg.JPEGQuality
+	}
+	pngCompression, err := getPNGCompression(imagesCfg.PNGCompression)
+	if err != nil {
+		return err
+	}
+	images.PNGCompression = pngCompression
+	if err := checkImageGlobs(imagesCfg.Keep); err != nil {
+		return err
+	}
+	images.Keep = imagesCfg.Keep
+
+	// syntax highlighting
+	syntaxThemes := map[string]SyntaxTheme{}
+	for name, theme := range cfg.Blog.Syntax.Themes {
+		syntaxThemes[name] = theme
+	}
+	syng := SyntaxCSSGenerator{&SyntaxCSSConfig{
+		Theme:       cfg.Blog.Syntax.Theme,
+		DarkTheme:   cfg.Blog.Syntax.Dark,
+		DarkMode:    cfg.Blog.Syntax.DarkMode,
+		Themes:      syntaxThemes,
+		Destination: destination,
+		DirMode:     cfg.Generator.DirMode,
+		FileMode:    cfg.Generator.FileMode,
+	}}
+
+	var export *ExportConfig
+	if cfg.Generator.Export.Enabled {
+		exportTemplate, err := getTemplate(filepath.Join("static", "export.html"), funcs)
+		if err != nil {
+			return err
+		}
+		siteCSS, err := ioutil.ReadFile(filepath.Join("static", "css", "vec.css"))
+		if err != nil {
+			return fmt.Errorf("error reading the css of the exports: %v", err)
+		}
+		syntaxCSS, err := syng.getCSS()
+		if err != nil {
+			return err
+		}
+		export = &ExportConfig{
+			Destination: filepath.Join(destination, "export"),
+			WarnSize:    cfg.Generator.Export.WarnSize,
+			Template:    exportTemplate,
+			CSS:         newExportCSS(siteCSS, syntaxCSS),
+		}
+	}
+
+	var ogImage *OGImageConfig
+	if cfg.Blog.OGImage.Enabled {
+		var err error
+		ogImage, err = newOGImageConfig(cfg.Blog.OGImage.Font, cfg.Blog.OGImage.Background, cfg.Blog.OGImage.Color, cfg.Blog.Title, cfg.Blog.Author)
+		if err != nil {
+			return err
+		}
+	}
+
+	var pdf *PDFConfig
+	if cfg.Blog.Print.PDF {
+		pdf = newPDFConfig(cfg.Blog.Print.Command)
+	}
+
+	var webmentions map[string][]*Webmention
+	if cfg.Blog.Webmention.Data != "" {
+		var err error
+		if webmentions, err = readWebmentions(cfg.Blog.Webmention.Data); err != nil {
+			return err
+		}
+	}
+
+	var trailer *template.Template
+	if cfg.Blog.Trailer != "" {
+		var err error
+		if trailer, err = getTemplate(cfg.Blog.Trailer, funcs); err != nil {
+			return err
+		}
+	}
+
+	comments := &CommentsConfig{
+		Default:             cfg.Blog.Comments.Default,
+		DisqusShortname:     cfg.Blog.Comments.Disqus.Shortname,
+		UtterancesRepo:      cfg.Blog.Comments.Utterances.Repo,
+		UtterancesIssueTerm: cfg.Blog.Comments.Utterances.IssueTerm,
+		UtterancesTheme:     cfg.Blog.Comments.Utterances.Theme,
 	}
 
 	//posts
 	for _, post := range posts {
+		postDestination := destination
+		if isReview(post) {
+			postDestination = cfg.Generator.Preview
+		}
 		pg := PostGenerator{&PostConfig{
 			Post:        post,
-			Destination: destination,
+			Destination: postDestination,
 			Template:    t,
+			AMPTemplate: ampTemplate,
+			Images:      images,
+			Export:      export,
+			PDF:         pdf,
+			OGImage:     ogImage,
 			Writer:      indexWriter,
+			Trailer:     trailer,
+			Comments:    comments,
+			Webmentions: webmentions,
 		}}
 		generators = append(generators, &pg)
 	}
-	tagPostsMap := createTagPostsMap(posts)
 	// frontpage
 	fg := ListingGenerator{&ListingConfig{
-		NPG:         npg,
-		Posts:       posts[:getNumOfPagesOnFrontpage(posts, cfg.Blog.Frontpageposts)],
-		Template:    t,
-		Destination: filepath.Join(destination, "blog"),
-		PageTitle:   "",
-		IsIndex:     true,
-		Writer:      indexWriter,
+		NPG:           npg,
+		Posts:         listed[:getNumOfPagesOnFrontpage(listed, cfg.Blog.Frontpageposts)],
+		Template:      t,
+		Destination:   filepath.Join(destination, "blog"),
+		PageTitle:     "",
+		IsIndex:       true,
+		Writer:        indexWriter,
+		ExcerptLength: cfg.Blog.Excerpt.Length,
+		Ellipsis:      cfg.Blog.Excerpt.Ellipsis,
 	}}
 	// archive
 	ag := ListingGenerator{&ListingConfig{
-		NPG:         npg,
-		Posts:       posts,
-		Template:    t,
-		Destination: filepath.Join(destination, "archive"),
-		PageTitle:   "Archive",
-		IsIndex:     false,
-		Writer:      indexWriter,
+		NPG:           npg,
+		Posts:         listed,
+		Template:      t,
+		Destination:   filepath.Join(destination, paths.archiveDir()),
+		PageTitle:     "Archive",
+		IsIndex:       false,
+		Writer:        indexWriter,
+		ExcerptLength: cfg.Blog.Excerpt.Length,
+		Ellipsis:      cfg.Blog.Excerpt.Ellipsis,
+	}}
+	// rss
+	location, err := time.LoadLocation(cfg.Blog.Timezone)
+	if err != nil {
+		return fmt.Errorf("error loading timezone %s: %v", cfg.Blog.Timezone, err)
+	}
+	rg := RSSGenerator{&RSSConfig{
+		Posts:           listed,
+		Location:        location,
+		Destination:     destination,
+		Language:        cfg.Blog.Language,
+		BlogURL:         cfg.Blog.URL,
+		BlogDescription: cfg.Blog.Description,
+		BlogTitle:       cfg.Blog.Title,
+		ExcerptLength:   cfg.Blog.Excerpt.Length,
+		Ellipsis:        cfg.Blog.Excerpt.Ellipsis,
+		FileMode:        cfg.Generator.FileMode,
+		DirMode:         cfg.Generator.DirMode,
+		MaxPosts:        cfg.Blog.Feeds.Posts,
+		TTL:             cfg.Blog.Feeds.TTL,
+		MathML:          cfg.Blog.Feeds.MathML,
 	}}
 	// tags
+	var tagFeed *RSSConfig
+	if cfg.Blog.Feeds.Tags {
+		tagFeed = rg.Config
+	}
 	tg := TagsGenerator{&TagsConfig{
-		NPG:         npg,
-		TagPostsMap: tagPostsMap,
-		Template:    t,
-		Destination: destination,
-		Writer:      indexWriter,
+		NPG:           npg,
+		TagPostsMap:   tagPostsMap,
+		Template:      t,
+		Destination:   destination,
+		Writer:        indexWriter,
+		ExcerptLength: cfg.Blog.Excerpt.Length,
+		Ellipsis:      cfg.Blog.Excerpt.Ellipsis,
+		Feed:          tagFeed,
+		FeedPosts:     cfg.Blog.Feeds.TagPosts,
+		Info:          tagInfo,
 	}}
 
 	staticURLs := []string{}
@@ -156,26 +642,56 @@ func runTasks(posts []*Post, t *template.Template, destination string, cfg *conf
 	}
 	// sitemap
 	sg := SitemapGenerator{&SitemapConfig{
-		Posts:       posts,
+		Posts:       getIndexablePosts(listed),
 		TagPostsMap: tagPostsMap,
 		Destination: destination,
 		BlogURL:     cfg.Blog.URL,
 		Statics:     staticURLs,
+		CleanURLs:   cfg.Blog.CleanURLs,
+		FileMode:    cfg.Generator.FileMode,
+		Paths:       paths,
 	}}
-	// rss
-	rg := RSSGenerator{&RSSConfig{
-		Posts:           posts,
+	// manifest
+	mg := ManifestGenerator{&ManifestConfig{
+		Icon:            cfg.Blog.Manifest.Icon,
+		Name:            cfg.Blog.Manifest.Name,
+		ShortName:       cfg.Blog.Manifest.ShortName,
+		Description:     cfg.Blog.Description,
+		ThemeColor:      cfg.Blog.Manifest.ThemeColor,
+		BackgroundColor: cfg.Blog.Manifest.BackgroundColor,
+		Images:          images,
 		Destination:     destination,
-		DateFormat:      cfg.Blog.Dateformat,
-		Language:        cfg.Blog.Language,
-		BlogURL:         cfg.Blog.URL,
-		BlogDescription: cfg.Blog.Description,
-		BlogTitle:       cfg.Blog.Title,
+		DirMode:         cfg.Generator.DirMode,
+		FileMode:        cfg.Generator.FileMode,
+	}}
+	// humans.txt
+	team := []HumansMember{}
+	for _, member := range cfg.Blog.Humans.Team {
+		team = append(team, HumansMember{Name: member.Name, Role: member.Role, Contact: member.Contact, Location: member.Location})
+	}
+	hg := HumansGenerator{&HumansConfig{
+		Team:        team,
+		Thanks:      cfg.Blog.Humans.Thanks,
+		Language:    cfg.Blog.Language,
+		Destination: destination,
+		FileMode:    cfg.Generator.FileMode,
+	}}
+	// security.txt
+	secg := SecurityGenerator{&SecurityConfig{
+		Contact:     cfg.Blog.Security.Contact,
+		Policy:      cfg.Blog.Security.Policy,
+		Encryption:  cfg.Blog.Security.Encryption,
+		Languages:   cfg.Blog.Security.Languages,
+		ExpiresDays: cfg.Blog.Security.Expires,
+		BlogURL:     cfg.Blog.URL,
+		Destination: destination,
+		DirMode:     cfg.Generator.DirMode,
+		FileMode:    cfg.Generator.FileMode,
 	}}
 	// statics
 	fileToDestination := map[string]string{}
 	for _, static := range cfg.Blog.Statics.Files {
-		fileToDestination[static.Src] = filepath.Join(destination, static.Dest)
+		fileToDestination[static.Src] = filepath.Join(destination, assets.Destination(static.Dest))
 	}
 	templateToFile := map[string]string{}
 	for _, static := range cfg.Blog.Statics.Templates {
@@ -187,8 +703,86 @@ func runTasks(posts []*Post, t *template.Template, destination string, cfg *conf
 		Template:          t,
 		Writer:            indexWriter,
 	}}
-	generators = append(generators, &fg, &ag, &tg, &sg, &rg, &statg)
+	generators = append(generators, &fg, &ag, &tg, &sg, &rg, &syng, &mg, &hg, &secg, &statg)
+	if len(getReviewPosts(posts)) > 0 {
+		// posts in review link the same css, js and images as the live site
+		previewFiles := map[string]string{}
+		for _, static := range cfg.Blog.Statics.Files {
+			previewFiles[static.Src] = filepath.Join(cfg.Generator.Preview, assets.Destination(static.Dest))
+		}
+		previewSyntax := *syng.Config
+		previewSyntax.Destination = cfg.Generator.Preview
+		generators = append(generators,
+			&StaticsGenerator{&StaticsConfig{FileToDestination: previewFiles, Writer: indexWriter}},
+			&SyntaxCSSGenerator{&previewSyntax})
+	}
+	if cfg.Blog.JSON.Enabled {
+		generators = append(generators, &PostsJSONGenerator{&PostsJSONConfig{
+			Posts:       posts,
+			BlogURL:     cfg.Blog.URL,
+			Content:     cfg.Blog.JSON.Content,
+			Destination: destination,
+			FileMode:    cfg.Generator.FileMode,
+		}})
+	}
+	if cfg.Blog.Gallery {
+		generators = append(generators, &GalleryGenerator{&GalleryConfig{
+			Posts:       listed,
+			Template:    t,
+			Destination: destination,
+			Writer:      indexWriter,
+		}})
+	}
+	if sharedImages != "" {
+		generators = append(generators, &SharedImagesGenerator{&SharedImagesConfig{
+			Source:      sharedImages,
+			Destination: destination,
+			Images:      images,
+			DirMode:     cfg.Generator.DirMode,
+		}})
+	}
+	if cfg.Blog.Feeds.Updated {
+		generators = append(generators, newUpdatedFeed(rg.Config, destination))
+	}
+	if cfg.Blog.Feeds.OPML {
+		var feedTags []string
+		if cfg.Blog.Feeds.Tags {
+			feedTags = make([]string, 0, len(tagPostsMap))
+			for tag := range tagPostsMap {
+				feedTags = append(feedTags, tag)
+			}
+		}
+		generators = append(generators, &OPMLGenerator{&OPMLConfig{
+			BlogURL:     cfg.Blog.URL,
+			BlogTitle:   cfg.Blog.Title,
+			Tags:        feedTags,
+			Updated:     cfg.Blog.Feeds.Updated,
+			Destination: destination,
+			FileMode:    cfg.Generator.FileMode,
+			Location:    location,
+			Paths:       paths,
+		}})
+	}
+	if cfg.Blog.HostRedirect.Format != "" {
+		generators = append(generators, &HostRedirectGenerator{&HostRedirectConfig{
+			Format:      cfg.Blog.HostRedirect.Format,
+			Hosts:       cfg.Blog.HostRedirect.Hosts,
+			BlogURL:     cfg.Blog.URL,
+			Destination: destination,
+			FileMode:    cfg.Generator.FileMode,
+		}})
+	}
+	if cfg.Blog.Print.Enabled {
+		generators = append(generators, &PrintCSSGenerator{&PrintCSSConfig{
+			Destination: destination,
+			DirMode:     cfg.Generator.DirMode,
+			FileMode:    cfg.Generator.FileMode,
+		}})
+	}
 
+	// every generator can fail without blocking, the first error is returned
+	// once all of them are done writing
+	errors := make(chan error, len(generators))
 	for _, generator := range generators {
 		wg.Add(1)
 		go func(g Generator) {
@@ -200,30 +794,30 @@ func runTasks(posts []*Post, t *template.Template, destination string, cfg *conf
 			}
 		}(generator)
 	}
-
-	go func() {
-		wg.Wait()
-		close(finished)
-	}()
-
-	select {
-	case <-finished:
-		return nil
-	case err := <-errors:
-		if err != nil {
-			return err
-		}
+	wg.Wait()
+	close(errors)
+	if err := <-errors; err != nil {
+		return err
+	}
+	if indexWriter.CSP != nil && cfg.Blog.CSP.Headers {
+		return indexWriter.CSP.writeHeaders(destination, cfg.Generator.FileMode)
 	}
 	return nil
 }
 
-func clearAndCreateDestination(path string) error {
+func clearAndCreateDestination(path string, mode os.FileMode) error {
 	if err := os.RemoveAll(path); err != nil {
 		if !os.IsNotExist(err) {
 			return fmt.Errorf("error removing folder at destination %s: %v ", path, err)
 		}
 	}
-	return os.Mkdir(path, os.ModePerm)
+	return os.Mkdir(path, mode)
+}
+
+// createFile creates or truncates the file at path, new files get the given
+// mode (before umask)
+func createFile(path string, mode os.FileMode) (*os.File, error) {
+	return os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, mode)
 }
 
 // IndexWriter writer index.html files
@@ -235,35 +829,117 @@ type IndexWriter struct {
 	Github          string
 	Twitter         string
 	GooglePluse     string
+	DirMode         os.FileMode
+	FileMode        os.FileMode
+	Funcs           template.FuncMap
+	// Paths are the folders of the tag and archive pages
+	Paths             SitePaths
+	Icons             []IconLink
+	ThemeColor        string
+	Tags              []*Tag
+	TagCounts         map[string]int
+	PostCount         int
+	RecentPosts       []*ListingData
+	CleanURLs         bool
+	Destination       string
+	CriticalCSS       template.CSS
+	DescriptionLength int
+	PrintCSS          bool
+	RelativeURLs      bool
+	// Feeds are linked from every page
+	Feeds []FeedLink
+	// CSP is added to every page if set
+	CSP *ContentSecurityPolicy
+	// Timings measure the writing of the pages
+	Timings *PhaseTimings
+	// Webmention and Pingback are the endpoints linked from every page
+	Webmention string
+	Pingback   string
+	// Build is added to every page if set
+	Build *BuildInfo
+	// DefaultImage is the absolute URL of the image of pages without one
+	DefaultImage string
 }
 
 // WriteIndexHTML writes an index.html file
 func (i *IndexWriter) WriteIndexHTML(path, pageTitle, metaDescription string, content template.HTML, t *template.Template) error {
-	filePath := filepath.Join(path, "index.html")
-	createFolderIfNotExist(path)
-	f, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("error creating file %s: %v", filePath, err)
-	}
-	defer f.Close()
+	return i.WriteIndexData(path, i.NewIndexData(path, pageTitle, metaDescription, content), t)
+}
+
+// NewIndexData creates the template data of a page, page specific fields can
+// be set on the result before passing it to WriteIndexData
+func (i *IndexWriter) NewIndexData(path, pageTitle, metaDescription string, content template.HTML) *IndexData {
 	metaDesc := metaDescription
 	if metaDescription == "" {
 		metaDesc = i.BlogDescription
 	}
-	w := bufio.NewWriter(f)
-	td := IndexData{
+	return &IndexData{
 		Name:            i.BlogAuthor,
 		Year:            time.Now().Year(),
 		HTMLTitle:       getHTMLTitle(pageTitle, i.BlogTitle),
 		PageTitle:       pageTitle,
 		Content:         content,
-		CanonicalLink:   buildCanonicalLink(path, i.BlogURL),
-		MetaDescription: metaDesc,
+		CanonicalLink:   i.getCanonicalLink(path),
+		MetaDescription: seoDescription(metaDesc, i.DescriptionLength),
 		BlogDescription: template.HTML(i.BlogDescription),
 		Github:          i.Github,
 		Twitter:         i.Twitter,
 		GooglePluse:     i.GooglePluse,
+		Icons:           i.Icons,
+		ThemeColor:      i.ThemeColor,
+		Webmention:      i.Webmention,
+		Pingback:        i.Pingback,
+		Build:           i.Build,
+		OGImage:         i.DefaultImage,
+		Tags:            i.Tags,
+		TagCounts:       i.TagCounts,
+		PostCount:       i.PostCount,
+		RecentPosts:     i.RecentPosts,
+		CriticalCSS:     i.CriticalCSS,
+		PrintCSS:        i.PrintCSS,
+		Feeds:           append([]FeedLink{}, i.Feeds...),
+	}
+}
+
+// WriteIndexData writes an index.html file using the given template data
+func (i *IndexWriter) WriteIndexData(path string, td *IndexData, t *template.Template) error {
+	return i.WriteHTMLFile(filepath.Join(path, "index.html"), td, t)
+}
+
+// WriteHTMLFile writes the file at filePath using the given template data
+func (i *IndexWriter) WriteHTMLFile(filePath string, td *IndexData, t *template.Template) error {
+	defer i.Timings.since(phaseWriting, time.Now())
+	createFolderIfNotExist(filepath.Dir(filePath), i.DirMode)
+	f, err := createFile(filePath, i.FileMode)
+	if err != nil {
+		return fmt.Errorf("error creating file %s: %v", filePath, err)
 	}
+	defer f.Close()
+	if i.CleanURLs || i.RelativeURLs || i.CSP != nil {
+		var page bytes.Buffer
+		if err := t.Execute(&page, td); err != nil {
+			return fmt.Errorf("error executing template %s: %v", filePath, err)
+		}
+		result := page.Bytes()
+		if i.CleanURLs {
+			if result, err = normalizePageLinks(result, i.BlogURL, filePath); err != nil {
+				return err
+			}
+		}
+		if i.RelativeURLs {
+			if result, err = makeLinksRelative(result, i.BlogURL, i.Destination, filePath); err != nil {
+				return err
+			}
+		}
+		if i.CSP != nil {
+			result = i.CSP.apply(result, i.Destination, filePath)
+		}
+		if _, err := f.Write(result); err != nil {
+			return fmt.Errorf("error writing file %s: %v", filePath, err)
+		}
+		return nil
+	}
+	w := bufio.NewWriter(f)
 	if err := t.Execute(w, td); err != nil {
 		return fmt.Errorf("error executing template %s: %v", filePath, err)
 	}
@@ -280,6 +956,29 @@ func getHTMLTitle(pageTitle, blogTitle string) string {
 	return fmt.Sprintf("%s - %s", pageTitle, blogTitle)
 }
 
+// getListedPosts filters out unlisted posts and posts in review, which only
+// get their own page
+func getListedPosts(posts []*Post) []*Post {
+	result := []*Post{}
+	for _, post := range posts {
+		if !post.Meta.Unlisted && !isReview(post) {
+			result = append(result, post)
+		}
+	}
+	return result
+}
+
+// getIndexablePosts filters out posts which search engines shouldn't index
+func getIndexablePosts(posts []*Post) []*Post {
+	result := []*Post{}
+	for _, post := range posts {
+		if !post.Meta.NoIndex {
+			result = append(result, post)
+		}
+	}
+	return result
+}
+
 func createTagPostsMap(posts []*Post) map[string][]*Post {
 	result := make(map[string][]*Post)
 	for _, post := range posts {
@@ -295,8 +994,18 @@ func createTagPostsMap(posts []*Post) map[string][]*Post {
 	return result
 }
 
-func getTemplate(path string) (*template.Template, error) {
-	t, err := template.ParseFiles(path)
+// newTemplateFuncs returns the functions available in all templates
+func newTemplateFuncs(assets AssetManifest, sprite *svgSprite, paths SitePaths) template.FuncMap {
+	return template.FuncMap{
+		"asset":       assets.Asset,
+		"svg":         sprite.Symbol,
+		"tagsLink":    paths.TagsLink,
+		"archiveLink": paths.ArchiveLink,
+	}
+}
+
+func getTemplate(path string, funcs template.FuncMap) (*template.Template, error) {
+	t, err := template.New(filepath.Base(path)).Funcs(funcs).ParseFiles(path)
 	if err != nil {
 		return nil, fmt.Errorf("error reading template %s: %v", path, err)
 	}
@@ -310,6 +1019,14 @@ func getNumOfPagesOnFrontpage(posts []*Post, numPosts int) int {
 	return numPosts
 }
 
+// getCanonicalLink returns the canonical link of the page written to path
+func (i *IndexWriter) getCanonicalLink(path string) string {
+	if i.CleanURLs {
+		return getPageLink(i.BlogURL, i.Destination, path)
+	}
+	return buildCanonicalLink(path, i.BlogURL)
+}
+
 func buildCanonicalLink(path, baseURL string) string {
 	parts := strings.Split(path, "/")
 	if len(parts) > 2 {
@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BuildStats summarize a successful generation
+type BuildStats struct {
+	Destination string
+	Posts       int
+	Files       int
+	Bytes       int64
+	Duration    time.Duration
+	// Phases are the durations of the phases of the generation by name
+	Phases map[string]time.Duration
+}
+
+// collectBuildStats counts the files and bytes written to destination
+func collectBuildStats(destination string, posts int, duration time.Duration) (*BuildStats, error) {
+	stats := &BuildStats{Destination: destination, Posts: posts, Duration: duration}
+	err := filepath.Walk(destination, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		stats.Files++
+		stats.Bytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error collecting build stats of %s: %v", destination, err)
+	}
+	return stats, nil
+}
+
+// env returns the stats as environment variables for hook commands
+func (s *BuildStats) env() []string {
+	env := []string{
+		"BLOG_DESTINATION=" + s.Destination,
+		"BLOG_POSTS=" + strconv.Itoa(s.Posts),
+		"BLOG_FILES=" + strconv.Itoa(s.Files),
+		"BLOG_BYTES=" + strconv.FormatInt(s.Bytes, 10),
+		"BLOG_DURATION_MS=" + strconv.FormatInt(int64(s.Duration/time.Millisecond), 10),
+	}
+	for _, name := range phaseNames {
+		if d, ok := s.Phases[name]; ok {
+			env = append(env, "BLOG_"+strings.ToUpper(name)+"_MS="+strconv.FormatInt(int64(d/time.Millisecond), 10))
+		}
+	}
+	return env
+}
+
+// runPostBuild calls the PostBuild callback of the site and runs the
+// post-build commands in the destination. Failures are reported, with
+// hooks.fatal set they fail the generation.
+func runPostBuild(site *SiteConfig, stats *BuildStats) error {
+	hooks := site.Config.Generator.Hooks
+	var failures []error
+	if site.PostBuild != nil {
+		if err := site.PostBuild(stats); err != nil {
+			failures = append(failures, fmt.Errorf("post-build callback failed: %v", err))
+		}
+	}
+	for _, command := range hooks.Commands {
+		fmt.Printf("Running post-build hook %s...\n", command)
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Dir = stats.Destination
+		cmd.Env = append(os.Environ(), stats.env()...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			failures = append(failures, fmt.Errorf("post-build hook %s failed: %v", command, err))
+		}
+	}
+	if hooks.Fatal && len(failures) > 0 {
+		return fmt.Errorf("%d post-build hooks failed, first: %v", len(failures), failures[0])
+	}
+	for _, err := range failures {
+		fmt.Println("Warning:", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestPostBuildCallback(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"first/post.md":  testPost("First Post", "01.02.2021", "The first post."),
+		"second/post.md": testPost("Second Post", "02.02.2021", "The second post."),
+	})
+	var stats *BuildStats
+	g := New(&SiteConfig{Sources: site.sources(), SourceRoot: site.Root, Destination: site.Dest, Config: site.Config})
+	g.Config.PostBuild = func(s *BuildStats) error {
+		stats = s
+		return nil
+	}
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	if stats == nil {
+		t.Fatal("PostBuild wasn't called")
+	}
+	if stats.Destination != site.Dest || stats.Posts != 2 || stats.Files == 0 || stats.Bytes == 0 || stats.Duration <= 0 {
+		t.Errorf("PostBuild got stats %+v", stats)
+	}
+}
+
+func TestPostBuildCallbackFailure(t *testing.T) {
+	for _, fatal := range []bool{false, true} {
+		site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+			cfg.Generator.Hooks.Fatal = fatal
+		}), map[string]string{
+			"first/post.md": testPost("First Post", "01.02.2021", "The first post."),
+		})
+		g := New(&SiteConfig{Sources: site.sources(), SourceRoot: site.Root, Destination: site.Dest, Config: site.Config})
+		g.Config.PostBuild = func(s *BuildStats) error {
+			return fmt.Errorf("upload failed")
+		}
+		var err error
+		output := captureStdout(t, func() {
+			err = g.Generate()
+		})
+		if fatal && (err == nil || !strings.Contains(err.Error(), "upload failed")) {
+			t.Errorf("fatal callback failure returned %v", err)
+		}
+		if !fatal && (err != nil || !strings.Contains(output, "upload failed")) {
+			t.Errorf("callback failure returned %v and wasn't reported", err)
+		}
+	}
+}
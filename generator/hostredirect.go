@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// hostRedirectFiles are the files of the supported server formats
+var hostRedirectFiles = map[string]string{
+	"netlify": "_redirects",
+	"apache":  ".htaccess",
+	"nginx":   "nginx-redirects.conf",
+}
+
+// HostRedirectGenerator object
+type HostRedirectGenerator struct {
+	Config *HostRedirectConfig
+}
+
+// HostRedirectConfig holds the configuration of the redirects of other hosts
+// to the host of the blog URL. Without Hosts the host with or without www. is
+// redirected, whichever isn't the canonical one.
+type HostRedirectConfig struct {
+	Format      string
+	Hosts       []string
+	BlogURL     string
+	Destination string
+	FileMode    os.FileMode
+}
+
+// Generate writes the redirect configuration of the server format
+func (g *HostRedirectGenerator) Generate() error {
+	fmt.Println("\tGenerating host redirects...")
+	file, ok := hostRedirectFiles[g.Config.Format]
+	if !ok {
+		return fmt.Errorf("unknown host redirect format %s", g.Config.Format)
+	}
+	canonical, err := url.Parse(g.Config.BlogURL)
+	if err != nil || canonical.Host == "" {
+		return fmt.Errorf("error parsing blog url %s: %v", g.Config.BlogURL, err)
+	}
+	hosts := getRedirectedHosts(canonical.Host, g.Config.Hosts)
+	// only the host changes, the path already includes the one of the blog URL
+	target := canonical.Scheme + "://" + canonical.Host
+	var b bytes.Buffer
+	for _, host := range hosts {
+		switch g.Config.Format {
+		case "netlify":
+			fmt.Fprintf(&b, "http://%s/* %s/:splat 301!\n", host, target)
+			fmt.Fprintf(&b, "https://%s/* %s/:splat 301!\n", host, target)
+		case "apache":
+			if b.Len() == 0 {
+				b.WriteString("RewriteEngine On\n")
+			}
+			fmt.Fprintf(&b, "RewriteCond %%{HTTP_HOST} ^%s$ [NC]\n", regexp.QuoteMeta(host))
+			fmt.Fprintf(&b, "RewriteRule ^(.*)$ %s/$1 [R=301,L]\n", target)
+		case "nginx":
+			fmt.Fprintf(&b, "server {\n\tlisten 80;\n\tserver_name %s;\n\treturn 301 %s$request_uri;\n}\n", host, target)
+		}
+	}
+	filePath := filepath.Join(g.Config.Destination, file)
+	if err := writeTextFile(filePath, b.Bytes(), g.Config.FileMode); err != nil {
+		return err
+	}
+	fmt.Println("\tFinished generating host redirects...")
+	return nil
+}
+
+// getRedirectedHosts returns the hosts redirected to the canonical host,
+// without configured hosts it is the canonical host with or without www.
+func getRedirectedHosts(canonical string, hosts []string) []string {
+	if len(hosts) == 0 {
+		if strings.HasPrefix(canonical, "www.") {
+			return []string{strings.TrimPrefix(canonical, "www.")}
+		}
+		return []string{"www." + canonical}
+	}
+	result := []string{}
+	for _, host := range hosts {
+		if host != canonical {
+			result = append(result, host)
+		}
+	}
+	return result
+}
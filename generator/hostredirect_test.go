@@ -0,0 +1,50 @@
+package generator
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestHostRedirect(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"netlify", "http://www.example.com/* https://example.com/:splat 301!\nhttps://www.example.com/* https://example.com/:splat 301!\n"},
+		{"apache", "RewriteEngine On\nRewriteCond %{HTTP_HOST} ^www\\.example\\.com$ [NC]\nRewriteRule ^(.*)$ https://example.com/$1 [R=301,L]\n"},
+		{"nginx", "server {\n\tlisten 80;\n\tserver_name www.example.com;\n\treturn 301 https://example.com$request_uri;\n}\n"},
+	}
+	for _, test := range tests {
+		dir := t.TempDir()
+		g := &HostRedirectGenerator{&HostRedirectConfig{Format: test.format, BlogURL: "https://example.com/blog", Destination: dir, FileMode: 0644}}
+		if err := g.Generate(); err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, hostRedirectFiles[test.format]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != test.want {
+			t.Errorf("%s redirects are\n%s\nwant\n%s", test.format, data, test.want)
+		}
+	}
+}
+
+func TestGetRedirectedHosts(t *testing.T) {
+	tests := []struct {
+		canonical string
+		hosts     []string
+		want      []string
+	}{
+		{"example.com", nil, []string{"www.example.com"}},
+		{"www.example.com", nil, []string{"example.com"}},
+		{"example.com", []string{"example.org", "example.com", "blog.example.net"}, []string{"example.org", "blog.example.net"}},
+	}
+	for _, test := range tests {
+		if got := getRedirectedHosts(test.canonical, test.hosts); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("getRedirectedHosts(%s, %v) = %v, want %v", test.canonical, test.hosts, got, test.want)
+		}
+	}
+}
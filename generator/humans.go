@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HumansMember is a member of the team listed in humans.txt
+type HumansMember struct {
+	Name     string
+	Role     string
+	Contact  string
+	Location string
+}
+
+// HumansGenerator object
+type HumansGenerator struct {
+	Config *HumansConfig
+}
+
+// HumansConfig holds the configuration of humans.txt
+type HumansConfig struct {
+	Team        []HumansMember
+	Thanks      []string
+	Language    string
+	Destination string
+	FileMode    os.FileMode
+}
+
+// Generate writes humans.txt, nothing is written without team members
+func (g *HumansGenerator) Generate() error {
+	if len(g.Config.Team) == 0 {
+		return nil
+	}
+	fmt.Println("\tGenerating humans.txt...")
+	var b bytes.Buffer
+	b.WriteString("/* TEAM */\n")
+	for _, member := range g.Config.Team {
+		role := member.Role
+		if role == "" {
+			role = "Author"
+		}
+		fmt.Fprintf(&b, "\t%s: %s\n", role, member.Name)
+		if member.Contact != "" {
+			fmt.Fprintf(&b, "\tContact: %s\n", member.Contact)
+		}
+		if member.Location != "" {
+			fmt.Fprintf(&b, "\tFrom: %s\n", member.Location)
+		}
+		b.WriteString("\n")
+	}
+	if len(g.Config.Thanks) > 0 {
+		b.WriteString("/* THANKS */\n")
+		for _, name := range g.Config.Thanks {
+			fmt.Fprintf(&b, "\t%s\n", name)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("/* SITE */\n")
+	fmt.Fprintf(&b, "\tLast update: %s\n", time.Now().Format("2006/01/02"))
+	if g.Config.Language != "" {
+		fmt.Fprintf(&b, "\tLanguage: %s\n", g.Config.Language)
+	}
+	fmt.Fprintf(&b, "\tSoftware: blog-generator\n")
+	return writeTextFile(filepath.Join(g.Config.Destination, "humans.txt"), b.Bytes(), g.Config.FileMode)
+}
+
+// writeTextFile writes data to the file at filePath
+func writeTextFile(filePath string, data []byte, mode os.FileMode) error {
+	f, err := createFile(filePath, mode)
+	if err != nil {
+		return fmt.Errorf("error creating file %s: %v", filePath, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("error writing file %s: %v", filePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+package generator
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/image/draw"
+)
+
+// ImageProcessor downscales post images, image decoding and encoding is
+// limited to a number of concurrent operations separate from the generators
+type ImageProcessor struct {
+	MaxWidth  int
+	MaxPixels int
+	FileMode  os.FileMode
+	// Strict fails on empty or corrupt images instead of copying them
+	// unprocessed
+	Strict bool
+	// JPEGQuality is the quality of encoded jpeg images from 1 to 100
+	JPEGQuality int
+	// PNGCompression is the compression level of encoded png images
+	PNGCompression png.CompressionLevel
+	// Keep are glob patterns of image file names which are copied bit for
+	// bit instead of being processed
+	Keep []string
+	// Timings measure the processing of images
+	Timings *PhaseTimings
+	sem     chan struct{}
+	// files limits the copies at once, each of them opens up to two files
+	files chan struct{}
+}
+
+// pngCompressionLevels are the png compression levels by their config names
+var pngCompressionLevels = map[string]png.CompressionLevel{
+	"default": png.DefaultCompression,
+	"none":    png.NoCompression,
+	"speed":   png.BestSpeed,
+	"best":    png.BestCompression,
+}
+
+// getPNGCompression returns the png compression level called name, the
+// default level if it is empty
+func getPNGCompression(name string) (png.CompressionLevel, error) {
+	if name == "" {
+		return png.DefaultCompression, nil
+	}
+	level, ok := pngCompressionLevels[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown png compression %s, known are default, none, speed and best", name)
+	}
+	return level, nil
+}
+
+// corruptImageError is returned for empty images and images which can't be
+// decoded
+type corruptImageError struct {
+	path string
+	err  error
+}
+
+func (e *corruptImageError) Error() string {
+	return fmt.Sprintf("image %s is corrupt: %v", e.path, e.err)
+}
+
+// NewImageProcessor creates an ImageProcessor running at most workers image
+// operations at once. The files it opens at once are capped by the
+// descriptor limit of the process until SetOpenFiles is called.
+func NewImageProcessor(workers, maxWidth, maxPixels int, fileMode os.FileMode) *ImageProcessor {
+	if workers < 1 {
+		workers = 1
+	}
+	p := &ImageProcessor{
+		MaxWidth:       maxWidth,
+		MaxPixels:      maxPixels,
+		FileMode:       fileMode,
+		JPEGQuality:    jpeg.DefaultQuality,
+		PNGCompression: png.DefaultCompression,
+		sem:            make(chan struct{}, workers),
+	}
+	p.SetOpenFiles(getDefaultOpenFiles())
+	return p
+}
+
+// SetOpenFiles caps the files opened at once by copies and processing to n,
+// at least two. It must be called before the processor is used.
+func (p *ImageProcessor) SetOpenFiles(n int) {
+	copies := n / 2
+	if copies < 1 {
+		copies = 1
+	}
+	p.files = make(chan struct{}, copies)
+}
+
+func (p *ImageProcessor) acquire() {
+	p.sem <- struct{}{}
+}
+
+func (p *ImageProcessor) release() {
+	<-p.sem
+}
+
+// openFiles waits until the files of a copy can be opened, the slot is
+// taken before a worker so workers never wait for files
+func (p *ImageProcessor) openFiles() {
+	p.files <- struct{}{}
+}
+
+func (p *ImageProcessor) closeFiles() {
+	<-p.files
+}
+
+// Copy copies the file at src to dst as it is
+func (p *ImageProcessor) Copy(src, dst string) error {
+	p.openFiles()
+	defer p.closeFiles()
+	return copyFile(src, dst, p.FileMode)
+}
+
+// checkImageGlobs makes sure the glob patterns of kept images are valid
+func checkImageGlobs(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid image pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// matchesImageGlob reports if the file name of path matches one of patterns
+func matchesImageGlob(patterns []string, path string) bool {
+	name := filepath.Base(path)
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isProcessable reports if the image format can be downscaled
+func isProcessable(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jpg", ".jpeg", ".png":
+		return true
+	}
+	return false
+}
+
+// Process copies the image at src to dst, images wider than MaxWidth are
+// downscaled. Images with more than MaxPixels pixels are copied unprocessed
+// with a warning instead of being decoded, as are empty images and images
+// which can't be decoded unless Strict is set. Images matching Keep are
+// copied as they are.
+func (p *ImageProcessor) Process(src, dst string) error {
+	defer p.Timings.since(phaseImages, time.Now())
+	p.openFiles()
+	defer p.closeFiles()
+	if matchesImageGlob(p.Keep, src) {
+		return copyFile(src, dst, p.FileMode)
+	}
+	info, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("error reading file %s: %v", src, err)
+	}
+	if info.Size() == 0 {
+		return p.copyCorrupt(src, dst, &corruptImageError{path: src, err: fmt.Errorf("file is empty")})
+	}
+	if p.MaxWidth <= 0 || !isProcessable(src) {
+		return copyFile(src, dst, p.FileMode)
+	}
+	p.acquire()
+	defer p.release()
+	width, height, err := readImageSize(src)
+	if err != nil {
+		return p.copyCorrupt(src, dst, err)
+	}
+	if p.MaxPixels > 0 && width*height > p.MaxPixels {
+		fmt.Printf("\tWarning: image %s has %dx%d pixels, more than %d, it is copied unprocessed\n", src, width, height, p.MaxPixels)
+		return copyFile(src, dst, p.FileMode)
+	}
+	if width <= p.MaxWidth {
+		return copyFile(src, dst, p.FileMode)
+	}
+	img, err := decodeImage(src)
+	if err != nil {
+		return p.copyCorrupt(src, dst, err)
+	}
+	scaled := image.NewRGBA(image.Rect(0, 0, p.MaxWidth, height*p.MaxWidth/width))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), img, img.Bounds(), draw.Over, nil)
+	return p.writeImage(scaled, dst)
+}
+
+// copyCorrupt copies an image which failed with err unprocessed, other errors
+// than corrupt images and strict mode fail
+func (p *ImageProcessor) copyCorrupt(src, dst string, err error) error {
+	if _, ok := err.(*corruptImageError); !ok || p.Strict {
+		return err
+	}
+	fmt.Printf("\tWarning: %v, it is copied unprocessed\n", err)
+	return copyFile(src, dst, p.FileMode)
+}
+
+// ReadImage decodes the image at path, images with more than MaxPixels
+// pixels are rejected
+func (p *ImageProcessor) ReadImage(path string) (image.Image, error) {
+	p.openFiles()
+	defer p.closeFiles()
+	p.acquire()
+	defer p.release()
+	width, height, err := readImageSize(path)
+	if err != nil {
+		return nil, err
+	}
+	if p.MaxPixels > 0 && width*height > p.MaxPixels {
+		return nil, fmt.Errorf("image %s has %dx%d pixels, more than %d", path, width, height, p.MaxPixels)
+	}
+	return decodeImage(path)
+}
+
+func (p *ImageProcessor) writeImage(img image.Image, dst string) error {
+	f, err := createFile(dst, p.FileMode)
+	if err != nil {
+		return fmt.Errorf("error creating file %s: %v", dst, err)
+	}
+	defer f.Close()
+	switch strings.ToLower(filepath.Ext(dst)) {
+	case ".jpg", ".jpeg":
+		err = jpeg.Encode(f, img, &jpeg.Options{Quality: p.JPEGQuality})
+	default:
+		encoder := png.Encoder{CompressionLevel: p.PNGCompression}
+		err = encoder.Encode(f, img)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing file %s: %v", dst, err)
+	}
+	return nil
+}
+
+func readImageSize(path string) (int, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error reading file %s: %v", path, err)
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return 0, 0, &corruptImageError{path: path, err: err}
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+func decodeImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %s: %v", path, err)
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, &corruptImageError{path: path, err: err}
+	}
+	return img, nil
+}
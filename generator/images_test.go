@@ -0,0 +1,182 @@
+package generator
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestWriteImageJPEGQuality(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 64; y++ {
+			img.Set(x, y, color.RGBA{uint8(x * y), uint8(x * 7), uint8(y * 13), 255})
+		}
+	}
+	dir := t.TempDir()
+	sizes := map[int]int64{}
+	for _, quality := range []int{30, 90} {
+		p := NewImageProcessor(1, 0, 0, 0644)
+		p.JPEGQuality = quality
+		dst := filepath.Join(dir, "image.jpg")
+		if err := p.writeImage(img, dst); err != nil {
+			t.Fatal(err)
+		}
+		info, err := os.Stat(dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sizes[quality] = info.Size()
+	}
+	if sizes[30] >= sizes[90] {
+		t.Errorf("quality 30 is %d bytes, not smaller than the %d bytes of quality 90", sizes[30], sizes[90])
+	}
+}
+
+func TestImageProcessorBoundsConcurrency(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"wide.png": testPNG(t, 40, 10)})
+	p := NewImageProcessor(2, 20, 0, 0644)
+	// take all slots, processing has to wait for one of them
+	p.acquire()
+	p.acquire()
+	done := make(chan error)
+	go func() {
+		done <- p.Process(filepath.Join(dir, "wide.png"), filepath.Join(dir, "out.png"))
+	}()
+	select {
+	case <-done:
+		t.Fatal("image was processed while all slots were taken")
+	case <-time.After(50 * time.Millisecond):
+	}
+	p.release()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("image wasn't processed after a slot was released")
+	}
+	p.release()
+}
+
+func TestImageProcessorMaxPixels(t *testing.T) {
+	dir := t.TempDir()
+	src := testPNG(t, 40, 30)
+	writeTestFiles(t, dir, map[string]string{"huge.png": src})
+	p := NewImageProcessor(1, 20, 1000, 0644)
+	output := captureStdout(t, func() {
+		if err := p.Process(filepath.Join(dir, "huge.png"), filepath.Join(dir, "out.png")); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(output, "has 40x30 pixels, more than 1000") {
+		t.Errorf("oversized image gave no warning:\n%s", output)
+	}
+	out, err := ioutil.ReadFile(filepath.Join(dir, "out.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != src {
+		t.Error("oversized image wasn't copied unprocessed")
+	}
+	if _, err := p.ReadImage(filepath.Join(dir, "huge.png")); err == nil {
+		t.Error("oversized image was decoded")
+	}
+}
+
+func TestImageProcessorCorruptImages(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"empty.png":   "",
+		"corrupt.png": "not a png",
+	})
+	for _, name := range []string{"empty.png", "corrupt.png"} {
+		src, dst := filepath.Join(dir, name), filepath.Join(dir, "out-"+name)
+		p := NewImageProcessor(1, 20, 0, 0644)
+		output := captureStdout(t, func() {
+			if err := p.Process(src, dst); err != nil {
+				t.Errorf("%s failed outside of strict mode: %v", name, err)
+			}
+		})
+		if !strings.Contains(output, "is corrupt") {
+			t.Errorf("%s gave no warning:\n%s", name, output)
+		}
+		if _, err := os.Stat(dst); err != nil {
+			t.Errorf("%s wasn't copied: %v", name, err)
+		}
+		p.Strict = true
+		if err := p.Process(src, filepath.Join(dir, "strict-"+name)); err == nil || !strings.Contains(err.Error(), "is corrupt") {
+			t.Errorf("%s in strict mode returned %v, want a corrupt image error", name, err)
+		}
+	}
+}
+
+func TestKeptImageIsCopiedAsIs(t *testing.T) {
+	kept := testPNG(t, 40, 10)
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Generator.Images.MaxWidth = 20
+		cfg.Generator.Images.Keep = []string{"keep-*.png"}
+	}), map[string]string{
+		"first/post.md":              testPost("First Post", "01.02.2021", "![Kept](images/keep-wide.png) ![Scaled](images/wide.png)"),
+		"first/images/keep-wide.png": kept,
+		"first/images/wide.png":      testPNG(t, 40, 10),
+	}).build()
+	if site.read("first/images/keep-wide.png") != kept {
+		t.Error("kept image isn't byte-identical to its source")
+	}
+	img, err := png.Decode(strings.NewReader(site.read("first/images/wide.png")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if width := img.Bounds().Dx(); width != 20 {
+		t.Errorf("other image is %d pixels wide, want 20", width)
+	}
+}
+
+func TestInvalidKeepPattern(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Generator.Images.Keep = []string{"[keep"}
+	}), map[string]string{
+		"first/post.md": testPost("First Post", "01.02.2021", "The first post."),
+	})
+	if err := site.generate(); err == nil || !strings.Contains(err.Error(), `invalid image pattern "[keep"`) {
+		t.Errorf("invalid keep pattern returned %v", err)
+	}
+}
+
+func TestCustomImagesDir(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Generator.Images.Dir = "img"
+	}), map[string]string{
+		"first/post.md":           testPost("First Post", "01.02.2021", "![Photo](img/photo.png)"),
+		"first/img/photo.png":     testPNG(t, 4, 4),
+		"second/post.md":          testPost("Second Post", "02.02.2021", "![Photo](assets/photo.png)", "imagesdir: assets"),
+		"second/assets/photo.png": testPNG(t, 4, 4),
+		"second/img/unused.png":   testPNG(t, 4, 4),
+	}).build()
+	for _, post := range []struct{ name, dir string }{{"first", "img"}, {"second", "assets"}} {
+		if !site.exists(post.name + "/" + post.dir + "/photo.png") {
+			t.Errorf("images of %s weren't copied from %s", post.name, post.dir)
+		}
+		page := site.read(post.name + "/index.html")
+		if !strings.Contains(page, `src="`+post.dir+`/photo.png"`) {
+			t.Errorf("%s doesn't reference its image in %s", post.name, post.dir)
+		}
+		if want := `<meta property="og:image" content="https://example.com/` + post.name + "/" + post.dir + `/photo.png">`; !strings.Contains(page, want) {
+			t.Errorf("%s doesn't use its image from %s as og:image", post.name, post.dir)
+		}
+	}
+	if site.exists("first/images") || site.exists("second/img") {
+		t.Error("other images folders were copied")
+	}
+}
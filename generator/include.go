@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var includePattern = regexp.MustCompile(`\{\{\s*include\s+"([^"]+)"\s*\}\}`)
+
+// includeResolver inlines the files referenced by {{ include "file.md" }}
+// directives, paths are relative to the including file or the shared
+// includes folder
+type includeResolver struct {
+	Dir      string
+	MaxDepth int
+}
+
+// expand resolves the include directives of the markdown read from file
+func (r *includeResolver) expand(markdown []byte, file string) ([]byte, error) {
+	if !includePattern.Match(markdown) {
+		return markdown, nil
+	}
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	return r.expandFile(markdown, []string{abs})
+}
+
+// expandFile resolves the includes of the last file of stack, stack holds
+// the chain of files including each other
+func (r *includeResolver) expandFile(markdown []byte, stack []string) ([]byte, error) {
+	file := stack[len(stack)-1]
+	var expandErr error
+	result := mapOutsideCode(string(markdown), func(text string) string {
+		return includePattern.ReplaceAllStringFunc(text, func(token string) string {
+			if expandErr != nil {
+				return token
+			}
+			name := includePattern.FindStringSubmatch(token)[1]
+			content, err := r.include(name, stack)
+			if err != nil {
+				expandErr = fmt.Errorf("error including %q in %s: %v", name, file, err)
+				return token
+			}
+			return string(content)
+		})
+	})
+	if expandErr != nil {
+		return nil, expandErr
+	}
+	return []byte(result), nil
+}
+
+func (r *includeResolver) include(name string, stack []string) ([]byte, error) {
+	path, err := r.find(name, filepath.Dir(stack[len(stack)-1]))
+	if err != nil {
+		return nil, err
+	}
+	// cycles are reported as such even if they are deeper than MaxDepth
+	for _, parent := range stack {
+		if parent == path {
+			return nil, fmt.Errorf("include cycle %s -> %s", strings.Join(stack, " -> "), path)
+		}
+	}
+	if len(stack) > r.MaxDepth {
+		return nil, fmt.Errorf("includes are nested deeper than %d levels", r.MaxDepth)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading file %s: %v", path, err)
+	}
+	content = bytes.TrimRight(normalizeNewlines(content), "\n")
+	return r.expandFile(content, append(append([]string{}, stack...), path))
+}
+
+// find returns the absolute path of an included file
+func (r *includeResolver) find(name, dir string) (string, error) {
+	candidates := []string{filepath.Join(dir, filepath.FromSlash(name))}
+	if r.Dir != "" {
+		candidates = append(candidates, filepath.Join(r.Dir, filepath.FromSlash(name)))
+	}
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return filepath.Abs(candidate)
+		}
+	}
+	return "", fmt.Errorf("file not found, looked at %s", strings.Join(candidates, ", "))
+}
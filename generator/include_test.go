@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// expandTestIncludes expands the includes of the post.md of dir
+func expandTestIncludes(t *testing.T, r *includeResolver, dir, markdown string) (string, error) {
+	writeTestFiles(t, dir, map[string]string{"post.md": markdown})
+	result, err := r.expand([]byte(markdown), filepath.Join(dir, "post.md"))
+	return string(result), err
+}
+
+func TestIncludes(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"post/intro.md":    "Intro with {{ include \"nested.md\" }}.\n",
+		"post/nested.md":   "a nested part",
+		"shared/footer.md": "The shared footer.\r\n",
+		"cycle/a.md":       "A {{ include \"b.md\" }}",
+		"cycle/b.md":       "B {{ include \"a.md\" }}",
+		"deep/1.md":        "1 {{ include \"2.md\" }}",
+		"deep/2.md":        "2 {{ include \"3.md\" }}",
+		"deep/3.md":        "3",
+	})
+	r := &includeResolver{Dir: filepath.Join(root, "shared"), MaxDepth: 2}
+
+	got, err := expandTestIncludes(t, r, filepath.Join(root, "post"), "{{ include \"intro.md\" }}\n\n`{{ include \"intro.md\" }}`\n\n{{include \"footer.md\"}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Intro with a nested part.\n\n`{{ include \"intro.md\" }}`\n\nThe shared footer."; got != want {
+		t.Errorf("expanded to %q, want %q", got, want)
+	}
+
+	if _, err := expandTestIncludes(t, r, filepath.Join(root, "post"), "{{ include \"missing.md\" }}"); err == nil || !strings.Contains(err.Error(), "missing.md") || !strings.Contains(err.Error(), "file not found") {
+		t.Errorf("missing include gives error %v", err)
+	}
+
+	if _, err := expandTestIncludes(t, r, filepath.Join(root, "cycle"), "{{ include \"a.md\" }}"); err == nil || !strings.Contains(err.Error(), "include cycle") {
+		t.Errorf("include cycle gives error %v", err)
+	}
+
+	if _, err := expandTestIncludes(t, r, filepath.Join(root, "deep"), "{{ include \"1.md\" }}"); err == nil || !strings.Contains(err.Error(), "deeper than 2 levels") {
+		t.Errorf("too deep includes give error %v", err)
+	}
+	r.MaxDepth = 3
+	if got, err := expandTestIncludes(t, r, filepath.Join(root, "deep"), "{{ include \"1.md\" }}"); err != nil || got != "1 2 3" {
+		t.Errorf("includes within the depth expanded to %q, %v", got, err)
+	}
+}
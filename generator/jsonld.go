@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// articleJSONLD is the schema.org BlogPosting of a post
+type articleJSONLD struct {
+	Context          string       `json:"@context"`
+	Type             string       `json:"@type"`
+	Headline         string       `json:"headline"`
+	Description      string       `json:"description,omitempty"`
+	DatePublished    string       `json:"datePublished,omitempty"`
+	DateModified     string       `json:"dateModified,omitempty"`
+	Author           personJSONLD `json:"author"`
+	Image            string       `json:"image,omitempty"`
+	MainEntityOfPage string       `json:"mainEntityOfPage"`
+	License          string       `json:"license,omitempty"`
+	WordCount        int          `json:"wordCount,omitempty"`
+}
+
+type personJSONLD struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+// buildArticleJSONLD returns the JSON-LD of a post with the absolute URL of
+// its image, ready to be placed in a <script type="application/ld+json"> tag
+func buildArticleJSONLD(post *Post, image, author string) (template.JS, error) {
+	meta := post.Meta
+	ld := articleJSONLD{
+		Context:          "https://schema.org",
+		Type:             "BlogPosting",
+		Headline:         meta.Title,
+		Description:      meta.Short,
+		DatePublished:    formatJSONLDDate(meta.ParsedDate),
+		DateModified:     formatJSONLDDate(meta.ParsedUpdated),
+		Author:           personJSONLD{Type: "Person", Name: author},
+		Image:            image,
+		MainEntityOfPage: post.URL,
+		WordCount:        post.ReadingTime.Words,
+	}
+	if license := post.License; license != nil {
+		ld.License = license.URL
+		if ld.License == "" {
+			ld.License = license.Name
+		}
+	}
+	if ld.DateModified == "" {
+		ld.DateModified = ld.DatePublished
+	}
+	// json.Marshal escapes <, > and &, so the result can't close the script tag
+	data, err := json.Marshal(ld)
+	if err != nil {
+		return "", fmt.Errorf("error creating JSON-LD for %s: %v", post.Name, err)
+	}
+	return template.JS(data), nil
+}
+
+func formatJSONLDDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(time.RFC3339)
+}
+
+// getPostImageURL returns the absolute URL of the post's image, which is the
+// image set in the meta data or the first image of the post
+func getPostImageURL(post *Post, blogURL string) string {
+	image := post.Meta.Image
+	if image == "" {
+		if len(post.Images) == 0 {
+			return ""
+		}
+		image = getImagesPath(post) + post.Images[0]
+	}
+	if strings.HasPrefix(image, "http://") || strings.HasPrefix(image, "https://") {
+		return image
+	}
+	if strings.HasPrefix(image, "/") {
+		return blogURL + image
+	}
+	return post.URL + image
+}
+
+// getDefaultImageURL returns the absolute URL of the default image of pages,
+// paths are relative to the root of the blog
+func getDefaultImageURL(image, blogURL string) string {
+	if image == "" || strings.HasPrefix(image, "http://") || strings.HasPrefix(image, "https://") {
+		return image
+	}
+	return blogURL + "/" + strings.TrimPrefix(image, "/")
+}
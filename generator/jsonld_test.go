@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// getTestJSONLD returns the JSON-LD of a page
+func getTestJSONLD(t *testing.T, page string) articleJSONLD {
+	t.Helper()
+	match := regexp.MustCompile(`<script type="application/ld\+json">(.*?)</script>`).FindStringSubmatch(page)
+	if match == nil {
+		t.Fatal("page has no JSON-LD")
+	}
+	var ld articleJSONLD
+	if err := json.Unmarshal([]byte(match[1]), &ld); err != nil {
+		t.Fatalf("error parsing JSON-LD: %v\n%s", err, match[1])
+	}
+	return ld
+}
+
+func TestArticleJSONLD(t *testing.T) {
+	title := "Closing </script> & more"
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"post/post.md": testPost(`"`+title+`"`, "01.02.2021", "Some words here.", "updated: 03.02.2021", "short: A post", "image: /img/cover.png"),
+	}).build()
+	page := site.read("post/index.html")
+	if strings.Contains(page, "</script> &") {
+		t.Error("title closes the script tag")
+	}
+	ld := getTestJSONLD(t, page)
+	want := articleJSONLD{
+		Context:          "https://schema.org",
+		Type:             "BlogPosting",
+		Headline:         title,
+		Description:      "A post",
+		DatePublished:    "2021-02-01T00:00:00Z",
+		DateModified:     "2021-02-03T00:00:00Z",
+		Author:           personJSONLD{Type: "Person", Name: "Tester"},
+		Image:            "https://example.com/img/cover.png",
+		MainEntityOfPage: "https://example.com/post/",
+		WordCount:        3,
+	}
+	if ld != want {
+		t.Errorf("JSON-LD is %+v, want %+v", ld, want)
+	}
+}
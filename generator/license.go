@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// License is the resolved license of a post
+type License struct {
+	ID   string
+	Name string
+	URL  string
+}
+
+// knownLicenses maps lower case license identifiers to their label and URL
+var knownLicenses = map[string]License{
+	"cc-by-4.0":           {ID: "CC-BY-4.0", Name: "CC BY 4.0", URL: "https://creativecommons.org/licenses/by/4.0/"},
+	"cc-by-sa-4.0":        {ID: "CC-BY-SA-4.0", Name: "CC BY-SA 4.0", URL: "https://creativecommons.org/licenses/by-sa/4.0/"},
+	"cc-by-nd-4.0":        {ID: "CC-BY-ND-4.0", Name: "CC BY-ND 4.0", URL: "https://creativecommons.org/licenses/by-nd/4.0/"},
+	"cc-by-nc-4.0":        {ID: "CC-BY-NC-4.0", Name: "CC BY-NC 4.0", URL: "https://creativecommons.org/licenses/by-nc/4.0/"},
+	"cc-by-nc-sa-4.0":     {ID: "CC-BY-NC-SA-4.0", Name: "CC BY-NC-SA 4.0", URL: "https://creativecommons.org/licenses/by-nc-sa/4.0/"},
+	"cc-by-nc-nd-4.0":     {ID: "CC-BY-NC-ND-4.0", Name: "CC BY-NC-ND 4.0", URL: "https://creativecommons.org/licenses/by-nc-nd/4.0/"},
+	"cc0-1.0":             {ID: "CC0-1.0", Name: "CC0 1.0", URL: "https://creativecommons.org/publicdomain/zero/1.0/"},
+	"mit":                 {ID: "MIT", Name: "MIT License", URL: "https://opensource.org/licenses/MIT"},
+	"apache-2.0":          {ID: "Apache-2.0", Name: "Apache License 2.0", URL: "https://www.apache.org/licenses/LICENSE-2.0"},
+	"all-rights-reserved": {ID: "all-rights-reserved", Name: "All rights reserved"},
+}
+
+// resolveLicense returns the label and URL of a license identifier, unknown
+// identifiers are returned as they are and reported by ok
+func resolveLicense(id string) (license *License, ok bool) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, true
+	}
+	if known, ok := knownLicenses[strings.ToLower(id)]; ok {
+		return &known, true
+	}
+	return &License{ID: id, Name: id}, false
+}
+
+// getPostLicense resolves the license of a post, falling back to the default
+// license of the blog
+func getPostLicense(meta *Meta, defaultLicense, filePath string) *License {
+	id := meta.License
+	if id == "" {
+		id = defaultLicense
+	}
+	license, ok := resolveLicense(id)
+	if !ok {
+		fmt.Printf("\tWarning: unknown license %q in %s\n", id, filePath)
+	}
+	return license
+}
@@ -0,0 +1,33 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetPostLicense(t *testing.T) {
+	var license *License
+	output := captureStdout(t, func() {
+		license = getPostLicense(&Meta{License: "cc-by-sa-4.0"}, "MIT", "known/post.md")
+	})
+	want := &License{ID: "CC-BY-SA-4.0", Name: "CC BY-SA 4.0", URL: "https://creativecommons.org/licenses/by-sa/4.0/"}
+	if !reflect.DeepEqual(license, want) {
+		t.Errorf("known license resolved to %+v, want %+v", license, want)
+	}
+	if output != "" {
+		t.Errorf("known license was reported: %q", output)
+	}
+	if license = getPostLicense(&Meta{}, "mit", "default/post.md"); license == nil || license.ID != "MIT" {
+		t.Errorf("default license resolved to %+v, want MIT", license)
+	}
+	output = captureStdout(t, func() {
+		license = getPostLicense(&Meta{License: "WTFPL"}, "MIT", "unknown/post.md")
+	})
+	if want := (&License{ID: "WTFPL", Name: "WTFPL"}); !reflect.DeepEqual(license, want) {
+		t.Errorf("unknown license resolved to %+v, want %+v", license, want)
+	}
+	if !strings.Contains(output, `unknown license "WTFPL" in unknown/post.md`) {
+		t.Errorf("unknown license wasn't reported: %q", output)
+	}
+}
@@ -0,0 +1,210 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// LintWarning is a problem found in the markdown of a post
+type LintWarning struct {
+	File    string
+	Line    int
+	Rule    string
+	Message string
+}
+
+func (w LintWarning) String() string {
+	return fmt.Sprintf("%s:%d: %s (%s)", w.File, w.Line, w.Message, w.Rule)
+}
+
+var (
+	lintCodeSpan   = regexp.MustCompile("`[^`]*`")
+	lintLinkedURL  = regexp.MustCompile(`(\]\(|<|["'=(])$`)
+	lintCodeFences = []string{"```", "~~~"}
+	// lintSetextUnderline matches the line below a setext heading
+	lintSetextUnderline = regexp.MustCompile(`^\s*(=+|-+)\s*$`)
+)
+
+// lintSource are the lines of the markdown of a post, warnings found in the
+// rendered html are mapped back to the first line after the previous element
+// of the same rule which contains their text
+type lintSource struct {
+	lines []string
+	// code marks the lines of code blocks
+	code []bool
+}
+
+func newLintSource(markdown []byte) *lintSource {
+	source := &lintSource{lines: strings.Split(string(markdown), "\n")}
+	inFence := false
+	for _, line := range source.lines {
+		fence := isCodeFence(strings.TrimSpace(line))
+		source.code = append(source.code, fence || inFence || strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t"))
+		if fence {
+			inFence = !inFence
+		}
+	}
+	return source
+}
+
+// find returns the first line from from on outside of code matching, the
+// lines before from are searched if none does and 0 is returned if no line
+// matches, e.g. for text of includes
+func (s *lintSource) find(from int, match func(i int) bool) int {
+	for _, start := range []int{from, 0} {
+		for i := start; i < len(s.lines); i++ {
+			if !s.code[i] && match(i) {
+				return i
+			}
+		}
+	}
+	return 0
+}
+
+// isHeading returns whether line i is an atx, setext or html heading
+func (s *lintSource) isHeading(i int) bool {
+	line := strings.TrimSpace(s.lines[i])
+	if strings.HasPrefix(line, "#") || strings.HasPrefix(strings.ToLower(line), "<h") {
+		return true
+	}
+	return line != "" && i+1 < len(s.lines) && lintSetextUnderline.MatchString(s.lines[i+1])
+}
+
+// lintPost checks a post for common problems. Images without alt text or
+// missing images, bare URLs and skipped heading levels are found in the
+// rendered html and reported at their line of the markdown, trailing
+// whitespace is found in the markdown.
+func lintPost(post *Post) []LintWarning {
+	var warnings []LintWarning
+	file := post.File
+	warn := func(line int, rule, format string, args ...interface{}) {
+		warnings = append(warnings, LintWarning{File: file, Line: post.BodyLine + line, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+	source := newLintSource(post.Markdown)
+	for i, line := range source.lines {
+		if source.code[i] {
+			continue
+		}
+		// two trailing spaces are a hard line break
+		if trailing := len(line) - len(strings.TrimRight(line, " \t")); trailing > 0 && line[len(line)-trailing:] != "  " {
+			warn(i, "trailing-whitespace", "line has trailing whitespace")
+		}
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(post.HTML))
+	if err != nil {
+		warn(0, "html", "error while parsing html: %v", err)
+		return warnings
+	}
+	lintHeadings(doc, source, warn)
+	lintImages(post, doc, source, warn)
+	lintBareURLs(doc, source, warn)
+	sort.SliceStable(warnings, func(i, j int) bool { return warnings[i].Line < warnings[j].Line })
+	return warnings
+}
+
+type lintWarn func(line int, rule, format string, args ...interface{})
+
+// lintHeadings reports headings which skip a level
+func lintHeadings(doc *goquery.Document, source *lintSource, warn lintWarn) {
+	line, lastLevel := 0, 0
+	doc.Find("h1, h2, h3, h4, h5, h6").Each(func(i int, s *goquery.Selection) {
+		level := int(goquery.NodeName(s)[1] - '0')
+		text := strings.TrimSpace(s.Clone().Find(".anchor").Remove().End().Text())
+		line = source.find(line, func(i int) bool { return source.isHeading(i) && strings.Contains(source.lines[i], text) })
+		if lastLevel > 0 && level > lastLevel+1 {
+			warn(line, "heading-level", "heading level skips from h%d to h%d", lastLevel, level)
+		}
+		lastLevel = level
+	})
+}
+
+// lintImages reports images without alt text and images whose file doesn't
+// exist
+func lintImages(post *Post, doc *goquery.Document, source *lintSource, warn lintWarn) {
+	line := 0
+	doc.Find("img").Each(func(i int, s *goquery.Selection) {
+		src := s.AttrOr("src", "")
+		line = source.find(line, func(i int) bool { return strings.Contains(source.lines[i], src) })
+		if strings.TrimSpace(s.AttrOr("alt", "")) == "" {
+			warn(line, "image-alt", "image has no alt text")
+		}
+		if path := getImageFile(post, src); path != "" {
+			if _, err := os.Stat(path); err != nil {
+				warn(line, "image-missing", "image %s does not exist", src)
+			}
+		}
+	})
+}
+
+// lintBareURLs reports URLs which are the text of their link or not linked
+// at all, unless the markdown links them explicitly like <url> or [url](url)
+func lintBareURLs(doc *goquery.Document, source *lintSource, warn lintWarn) {
+	var urls []string
+	doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
+		text := strings.TrimSpace(s.Clone().Find("." + externalIndicatorClass).Remove().End().Text())
+		if href := s.AttrOr("href", ""); text == href && bareURL.MatchString(href) {
+			urls = append(urls, href)
+		}
+	})
+	for _, node := range doc.Nodes {
+		urls = append(urls, getUnlinkedURLs(node)...)
+	}
+	line := 0
+	for _, url := range urls {
+		found := false
+		line = source.find(line, func(i int) bool {
+			found = isBareURL(source.lines[i], url)
+			return found
+		})
+		if found {
+			warn(line, "bare-url", "bare URL %s should be a link", url)
+		}
+	}
+}
+
+// getUnlinkedURLs returns the URLs in the text outside of links and code
+func getUnlinkedURLs(n *html.Node) []string {
+	if n.Type == html.ElementNode && autolinkSkipped[n.DataAtom] {
+		return nil
+	}
+	if n.Type == html.TextNode {
+		return bareURL.FindAllString(n.Data, -1)
+	}
+	var urls []string
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		urls = append(urls, getUnlinkedURLs(c)...)
+	}
+	return urls
+}
+
+// isBareURL returns whether url is in line outside of code spans without
+// being linked explicitly
+func isBareURL(line, url string) bool {
+	prose := lintCodeSpan.ReplaceAllString(line, "")
+	for offset := 0; ; {
+		i := strings.Index(prose[offset:], url)
+		if i < 0 {
+			return false
+		}
+		if !lintLinkedURL.MatchString(prose[:offset+i]) {
+			return true
+		}
+		offset += i + len(url)
+	}
+}
+
+func isCodeFence(line string) bool {
+	for _, fence := range lintCodeFences {
+		if strings.HasPrefix(line, fence) {
+			return true
+		}
+	}
+	return false
+}
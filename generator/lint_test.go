@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// lintMarkdown renders markdown like a post starting at line 5 of its file
+// and returns its lint warnings
+func lintMarkdown(t *testing.T, markdown string) []string {
+	opts, err := newRenderOptions(newTestConfig(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := &Post{File: "post.md", BodyLine: 5, Markdown: []byte(markdown), Meta: &Meta{}}
+	if post.HTML, err = getHTML(post, post.Markdown, opts); err != nil {
+		t.Fatal(err)
+	}
+	var result []string
+	for _, warning := range lintPost(post) {
+		result = append(result, fmt.Sprintf("%d %s", warning.Line, warning.Rule))
+	}
+	return result
+}
+
+func TestLintPost(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     []string
+	}{
+		{"clean", "# Title\n\nSome text with [a link](https://example.com).\n\n## Section\n\n![A cat](https://example.com/cat.png)\n\n<https://example.com>\n", nil},
+		{"missing alt", "Text\n\n![](https://example.com/cat.png)\n\n<img src=\"https://example.com/dog.png\">\n", []string{"7 image-alt", "9 image-alt"}},
+		{"skipped level", "# Title\n\nText\n\n### Deep\n", []string{"9 heading-level"}},
+		{"skipped setext level", "Title\n=====\n\nText\n\n### Deep\n", []string{"10 heading-level"}},
+		{"heading text in prose", "# Title\n\nDeep down\n\n### Deep\n", []string{"9 heading-level"}},
+		{"bare url", "Text\n\nSee https://example.com/page for more.\n", []string{"7 bare-url"}},
+		{"url in code", "Run `curl https://example.com` now.\n\n    https://example.com\n", nil},
+		{"trailing whitespace", "Text \nHard break  \nmore\n", []string{"5 trailing-whitespace"}},
+		{"code lines", "```\ncode \n# not a heading\n```\n", nil},
+	}
+	for _, test := range tests {
+		got := lintMarkdown(t, test.markdown)
+		if strings.Join(got, ", ") != strings.Join(test.want, ", ") {
+			t.Errorf("%s: got warnings %v, want %v", test.name, got, test.want)
+		}
+	}
+}
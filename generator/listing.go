@@ -15,7 +15,10 @@ type ListingData struct {
 	Short      string
 	Link       string
 	TimeToRead string
-	Tags       []*Tag
+	// ReadingMinutes are the rounded minutes to read, 0 below the threshold
+	ReadingMinutes int
+	Words          int
+	Tags           []*Tag
 }
 
 // ListingGenerator Object
@@ -31,6 +34,14 @@ type ListingConfig struct {
 	Destination, PageTitle string
 	IsIndex                bool
 	Writer                 *IndexWriter
+	ExcerptLength          int
+	Ellipsis               string
+	// Feed of the listed posts linked in addition to the feeds of the writer
+	Feed *FeedLink
+	// Description is shown below the title and used as meta description,
+	// Image replaces the default image of the writer
+	Description string
+	Image       string
 }
 
 // Generate starts the listing generation
@@ -44,22 +55,13 @@ func (g *ListingGenerator) Generate() error {
 	t := g.Config.Template
 	destination := g.Config.Destination
 	pageTitle := g.Config.PageTitle
-	short, err := getTemplate(shortTemplatePath)
+	short, err := getTemplate(shortTemplatePath, g.Config.Writer.Funcs)
 	if err != nil {
 		return err
 	}
 	var postBlocks []string
 	for _, post := range posts {
-		meta := post.Meta
-		link := fmt.Sprintf("/%s/", post.Name)
-		ld := ListingData{
-			Title:      meta.Title,
-			Date:       meta.Date,
-			Short:      meta.Short,
-			Link:       link,
-			Tags:       createTags(meta.Tags),
-			TimeToRead: calculateTimeToRead(string(post.HTML)),
-		}
+		ld := newListingData(post, g.Config.ExcerptLength, g.Config.Ellipsis, g.Config.Writer.Paths)
 		block := bytes.Buffer{}
 		if err := short.Execute(&block, ld); err != nil {
 			return fmt.Errorf("error executing template %s: %v", shortTemplatePath, err)
@@ -69,7 +71,7 @@ func (g *ListingGenerator) Generate() error {
 
 	if g.Config.IsIndex {
 		htmlBlocks := template.HTML(strings.Join(postBlocks, "\n"))
-		archiveLink, err := getTemplate(archiveLinkTemplatePath)
+		archiveLink, err := getTemplate(archiveLinkTemplatePath, g.Config.Writer.Funcs)
 		if err != nil {
 			return err
 		}
@@ -78,17 +80,22 @@ func (g *ListingGenerator) Generate() error {
 			return fmt.Errorf("error executing template %s: %v", archiveLinkTemplatePath, err)
 		}
 		htmlBlocks = template.HTML(fmt.Sprintf("%s%s", htmlBlocks, template.HTML(lastBlock.String())))
-		if err := g.Config.Writer.WriteIndexHTML(destination, pageTitle, pageTitle, htmlBlocks, t); err != nil {
-			return err
+		td := g.Config.Writer.NewIndexData(destination, pageTitle, pageTitle, htmlBlocks)
+		if g.Config.Feed != nil {
+			td.Feeds = append(td.Feeds, *g.Config.Feed)
 		}
-		return nil
+		return g.Config.Writer.WriteIndexData(destination, td, t)
 	}
 	nPage := len(postBlocks) / npg
 	if npg*nPage < len(postBlocks) {
 		nPage += 1
 	}
 	fmt.Println("Page num: ", nPage)
-	archiveLink, err := getTemplate(archiveLinkTemplatePath)
+	archiveLink, err := getTemplate(archiveLinkTemplatePath, g.Config.Writer.Funcs)
+	if err != nil {
+		return err
+	}
+	pageLink := getPageLink(g.Config.Writer.BlogURL, g.Config.Writer.Destination, g.Config.Destination)
 	for i := 0; i < nPage; i++ {
 		s := i * npg
 		e := (i + 1) * npg
@@ -109,7 +116,21 @@ func (g *ListingGenerator) Generate() error {
 			htmlBlocks = template.HTML(fmt.Sprintf("%s%s", htmlBlocks, template.HTML(lastBlock.String())))
 		}
 
-		if err := g.Config.Writer.WriteIndexHTML(destination, pageTitle, pageTitle, htmlBlocks, t); err != nil {
+		td := g.Config.Writer.NewIndexData(destination, pageTitle, g.getMetaDescription(), htmlBlocks)
+		td.PageDescription = g.Config.Description
+		if g.Config.Image != "" {
+			td.OGImage = g.Config.Image
+		}
+		if i > 0 {
+			td.PrevLink = getListingPageLink(pageLink, i-1)
+		}
+		if i+1 < nPage {
+			td.NextLink = getListingPageLink(pageLink, i+1)
+		}
+		if g.Config.Feed != nil {
+			td.Feeds = append(td.Feeds, *g.Config.Feed)
+		}
+		if err := g.Config.Writer.WriteIndexData(destination, td, t); err != nil {
 			return err
 		}
 	}
@@ -117,16 +138,43 @@ func (g *ListingGenerator) Generate() error {
 	return nil
 }
 
-func calculateTimeToRead(input string) string {
-	// an average human reads about 200 wpm
-	var secondsPerWord = 60.0 / 200.0
-	// multiply with the amount of words
-	words := secondsPerWord * float64(len(strings.Split(input, " ")))
-	// add 12 seconds for each image
-	images := 12.0 * strings.Count(input, "<img")
-	result := (words + float64(images)) / 60.0
-	if result < 1.0 {
-		result = 1.0
+// getMetaDescription returns the description of the listing, its title if it
+// has none
+func (g *ListingGenerator) getMetaDescription() string {
+	if g.Config.Description != "" {
+		return g.Config.Description
+	}
+	return g.Config.PageTitle
+}
+
+// getListingPageLink returns the absolute URL of the page with index i of a
+// listing starting at pageLink
+func getListingPageLink(pageLink string, i int) string {
+	if i == 0 {
+		return pageLink
+	}
+	return fmt.Sprintf("%spage/%d/", pageLink, i+1)
+}
+
+func newListingData(post *Post, excerptLength int, ellipsis string, paths SitePaths) *ListingData {
+	meta := post.Meta
+	return &ListingData{
+		Title:          meta.Title,
+		Date:           meta.Date,
+		Short:          truncate(post.Summary, excerptLength, ellipsis),
+		Link:           post.Path,
+		Tags:           createTags(meta.Tags, paths),
+		TimeToRead:     post.ReadingTime.Label,
+		ReadingMinutes: post.ReadingTime.Minutes,
+		Words:          post.ReadingTime.Words,
+	}
+}
+
+// getRecentPosts returns the listing data of the newest posts
+func getRecentPosts(posts []*Post, n, excerptLength int, ellipsis string, paths SitePaths) []*ListingData {
+	result := []*ListingData{}
+	for i := 0; i < n && i < len(posts); i++ {
+		result = append(result, newListingData(posts[i], excerptLength, ellipsis, paths))
 	}
-	return fmt.Sprintf("%.0fm", result)
+	return result
 }
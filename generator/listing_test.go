@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestRecentPostsOnPage(t *testing.T) {
+	widget := filepath.Join(t.TempDir(), "recent.html")
+	writeTestFiles(t, filepath.Dir(widget), map[string]string{
+		"recent.html": `<ul id="recent">{{ range .RecentPosts }}<li>{{ .Title }}</li>{{ end }}</ul>`,
+	})
+	cfg := newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.Recentposts = 3
+		cfg.Blog.Statics.Templates = append(cfg.Blog.Statics.Templates, struct {
+			Src  string
+			Dest string
+		}{widget, "recent"})
+	})
+	site := newTestSite(t, cfg, map[string]string{
+		"a/post.md":        testPost("Oldest", "01.02.2021", "A."),
+		"b/post.md":        testPost("Newest", "05.02.2021", "B."),
+		"c/post.md":        testPost("Middle", "03.02.2021", "C."),
+		"d/post.md":        testPost("Second", "04.02.2021", "D."),
+		"unlisted/post.md": testPost("Unlisted", "06.02.2021", "U.", "unlisted: true"),
+		"draft/post.md":    testPost("Draft", "07.02.2021", "D.", "status: draft"),
+	}).build()
+	want := `<ul id="recent"><li>Newest</li><li>Second</li><li>Middle</li></ul>`
+	if page := site.read("recent/index.html"); !strings.Contains(page, want) {
+		t.Errorf("page doesn't list the recent posts in order, want %s", want)
+	}
+}
+
+func TestListingRelLinks(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Generator.NPG = 1
+	}), map[string]string{
+		"a/post.md": testPost("A", "01.02.2021", "A."),
+		"b/post.md": testPost("B", "02.02.2021", "B."),
+		"c/post.md": testPost("C", "03.02.2021", "C."),
+	}).build()
+	tests := []struct {
+		page       string
+		prev, next string
+	}{
+		{"archive/index.html", "", "https://example.com/archive/page/2/"},
+		{"archive/page/2/index.html", "https://example.com/archive/", "https://example.com/archive/page/3/"},
+		{"archive/page/3/index.html", "https://example.com/archive/page/2/", ""},
+	}
+	for _, test := range tests {
+		page := site.read(test.page)
+		for rel, want := range map[string]string{"prev": test.prev, "next": test.next} {
+			tag := `<link rel="` + rel + `"`
+			if want == "" {
+				if strings.Contains(page, tag) {
+					t.Errorf("%s has a %s link", test.page, rel)
+				}
+			} else if !strings.Contains(page, tag+` href="`+want+`">`) {
+				t.Errorf("%s doesn't link %s to %s", test.page, rel, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// lockPollInterval is the interval a generation waiting for the lock checks
+// it again
+const lockPollInterval = 100 * time.Millisecond
+
+// buildLock is the lock file next to the destination held by a generation,
+// it isn't inside of the destination as that is removed on every build
+type buildLock struct {
+	path string
+}
+
+// getLockFile returns the lock file of a destination folder
+func getLockFile(destination string) string {
+	return filepath.Clean(destination) + ".lock"
+}
+
+// acquireBuildLock takes the lock of destination, waiting at most wait for a
+// running generation to finish. Locks of processes which ended without
+// releasing them are taken over.
+func acquireBuildLock(destination string, wait time.Duration) (*buildLock, error) {
+	path := getLockFile(destination)
+	deadline := time.Now().Add(wait)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, err = f.WriteString(strconv.Itoa(os.Getpid()))
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(path)
+				return nil, fmt.Errorf("error writing lock %s: %v", path, err)
+			}
+			return &buildLock{path: path}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("error creating lock %s: %v", path, err)
+		}
+		pid := readLockPID(path)
+		if pid > 0 && !isProcessRunning(pid) {
+			if err := takeOverStaleLock(path, pid); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("destination %s is locked by another generation (process %d), remove %s if none is running", destination, pid, path)
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// lockTakeovers numbers the takeovers of stale locks of this process
+var lockTakeovers int32
+
+// takeOverStaleLock removes the lock at path of the ended process pid. The
+// lock is renamed to a name of its own first, so only one of concurrent
+// takeovers gets it, and put back if it was replaced by the lock of another
+// generation before the rename.
+func takeOverStaleLock(path string, pid int) error {
+	stale := fmt.Sprintf("%s.%d-%d", path, os.Getpid(), atomic.AddInt32(&lockTakeovers, 1))
+	if err := os.Rename(path, stale); err != nil {
+		if os.IsNotExist(err) {
+			// another generation took it over
+			return nil
+		}
+		return fmt.Errorf("error taking over lock %s: %v", path, err)
+	}
+	if readLockPID(stale) != pid {
+		if err := os.Link(stale, path); err != nil && !os.IsExist(err) {
+			return fmt.Errorf("error restoring lock %s: %v", path, err)
+		}
+		if err := os.Remove(stale); err != nil {
+			return fmt.Errorf("error removing lock %s: %v", stale, err)
+		}
+		return nil
+	}
+	fmt.Printf("Warning: removing lock %s of the ended process %d\n", path, pid)
+	if err := os.Remove(stale); err != nil {
+		return fmt.Errorf("error removing lock %s: %v", stale, err)
+	}
+	return nil
+}
+
+// LockDestination takes the lock of a destination like Generate does, for
+// callers which also fetch the sources or deploy the destination. It returns
+// the function releasing the lock.
+func LockDestination(destination string, wait time.Duration) (func(), error) {
+	lock, err := acquireBuildLock(destination, wait)
+	if err != nil {
+		return nil, err
+	}
+	return lock.release, nil
+}
+
+// release removes the lock file
+func (l *buildLock) release() {
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		fmt.Printf("Warning: error removing lock %s: %v\n", l.path, err)
+	}
+}
+
+// readLockPID returns the process id written to a lock file, 0 if it can't
+// be read, e.g. while the lock is being written
+func readLockPID(path string) int {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0
+	}
+	return pid
+}
+
+// isProcessRunning reports if the process with pid exists, processes of other
+// users can't be signalled but exist. On Windows the process is assumed to be
+// running.
+func isProcessRunning(pid int) bool {
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || err == syscall.EPERM
+}
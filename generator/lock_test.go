@@ -0,0 +1,110 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestConcurrentGenerateIsRejected(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"first/post.md": testPost("First Post", "01.02.2021", "The first post."),
+	})
+	release, err := LockDestination(site.Dest, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	err = site.generate()
+	if err == nil || !strings.Contains(err.Error(), "is locked by another generation") {
+		t.Errorf("generation of a locked destination returned %v", err)
+	}
+	if site.exists("first/index.html") {
+		t.Error("generation of a locked destination wrote the post")
+	}
+}
+
+func TestConcurrentGenerateIsSerialized(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Generator.LockWait = 5
+	}), map[string]string{
+		"first/post.md": testPost("First Post", "01.02.2021", "The first post."),
+	})
+	release, err := LockDestination(site.Dest, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	released := make(chan time.Time, 1)
+	go func() {
+		time.Sleep(3 * lockPollInterval)
+		released <- time.Now()
+		release()
+	}()
+	site.build()
+	if !site.exists("first/index.html") {
+		t.Error("waiting generation didn't write the post")
+	}
+	select {
+	case <-released:
+	default:
+		t.Error("generation didn't wait for the lock")
+	}
+	release, err = LockDestination(site.Dest, 0)
+	if err != nil {
+		t.Fatalf("generation didn't release the lock: %v", err)
+	}
+	release()
+}
+
+func TestConcurrentStaleLockTakeover(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("processes are assumed to be running on windows")
+	}
+	ended := exec.Command("true")
+	if err := ended.Run(); err != nil {
+		t.Skip(err)
+	}
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "www")
+	writeTestFiles(t, dir, map[string]string{"www.lock": strconv.Itoa(ended.Process.Pid)})
+	var wg sync.WaitGroup
+	locks := make(chan *buildLock, 8)
+	for i := 0; i < cap(locks); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if lock, err := acquireBuildLock(dest, 0); err == nil {
+				locks <- lock
+			} else if !strings.Contains(err.Error(), "is locked by another generation") {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(locks)
+	if len(locks) != 1 {
+		t.Errorf("%d takeovers got the stale lock, want 1", len(locks))
+	}
+	if pid := readLockPID(getLockFile(dest)); pid != os.Getpid() {
+		t.Errorf("lock is held by process %d", pid)
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("takeovers left %d files, want only the lock", len(files))
+	}
+	for lock := range locks {
+		lock.release()
+	}
+}
@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/eleztian/blog-generator/config"
+)
+
+// generateMaintenance writes only the maintenance page to the root of the
+// destination and copies the statics it references
+func generateMaintenance(cfg *config.Config, destination string, assets AssetManifest, funcs template.FuncMap) error {
+	fmt.Println("\tGenerating Maintenance Page...")
+	t, err := getTemplate(cfg.Blog.Maintenance.Template, funcs)
+	if err != nil {
+		return err
+	}
+	writer := &IndexWriter{
+		BlogTitle:         cfg.Blog.Title,
+		BlogDescription:   cfg.Blog.Description,
+		BlogAuthor:        cfg.Blog.Author,
+		BlogURL:           cfg.Blog.URL,
+		DirMode:           cfg.Generator.DirMode,
+		FileMode:          cfg.Generator.FileMode,
+		Funcs:             funcs,
+		Paths:             newSitePaths(cfg),
+		Destination:       destination,
+		DescriptionLength: cfg.Blog.Excerpt.Description,
+		RelativeURLs:      cfg.Blog.RelativeURLs,
+	}
+	td := writer.NewIndexData(destination, cfg.Blog.Maintenance.Title, "", "")
+	if err := writer.WriteIndexData(destination, td, t); err != nil {
+		return err
+	}
+	referenced, err := getReferencedPaths(filepath.Join(destination, "index.html"))
+	if err != nil {
+		return err
+	}
+	for _, static := range cfg.Blog.Statics.Files {
+		dest := filepath.ToSlash(filepath.Clean(assets.Destination(static.Dest)))
+		if !referenced[dest] {
+			continue
+		}
+		filePath := filepath.Join(destination, filepath.FromSlash(dest))
+		if err := createFolderIfNotExist(filepath.Dir(filePath), cfg.Generator.DirMode); err != nil {
+			return err
+		}
+		if err := copyFile(static.Src, filePath, cfg.Generator.FileMode); err != nil {
+			return err
+		}
+	}
+	fmt.Println("\tFinished generating Maintenance Page...")
+	return nil
+}
+
+// getReferencedPaths returns the local paths referenced by the src and href
+// attributes of an html file, relative to the root
+func getReferencedPaths(filePath string) (map[string]bool, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %s: %v", filePath, err)
+	}
+	defer f.Close()
+	doc, err := goquery.NewDocumentFromReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing html %s: %v", filePath, err)
+	}
+	result := map[string]bool{}
+	doc.Find("[src], [href]").Each(func(i int, s *goquery.Selection) {
+		for _, attr := range []string{"src", "href"} {
+			value, ok := s.Attr(attr)
+			if !ok {
+				continue
+			}
+			u, err := url.Parse(value)
+			if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" {
+				continue
+			}
+			result[strings.TrimPrefix(u.Path, "/")] = true
+		}
+	})
+	return result, nil
+}
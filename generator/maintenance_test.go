@@ -0,0 +1,43 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestMaintenanceOnlyWritesPageAndAssets(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.Maintenance.Enabled = true
+		for src, dest := range map[string]string{
+			"static/css/vec.css":         "css/vec.css",
+			"static/favicon.ico":         "favicon.ico",
+			"static/js/highlight.min.js": "js/highlight.min.js",
+			"static/robots.txt":          "robots.txt",
+		} {
+			cfg.Blog.Statics.Files = append(cfg.Blog.Statics.Files, struct {
+				Src  string
+				Dest string
+			}{src, dest})
+		}
+	}), map[string]string{
+		"first/post.md": testPost("First Post", "01.02.2021", "The first post."),
+	}).build()
+	var files []string
+	filepath.Walk(site.Dest, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			rel, _ := filepath.Rel(site.Dest, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	sort.Strings(files)
+	want := []string{"css/vec.css", "favicon.ico", "index.html"}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("maintenance build wrote %v, want %v", files, want)
+	}
+}
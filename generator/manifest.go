@@ -0,0 +1,161 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/image/draw"
+)
+
+// manifestIconSizes are the sizes of the generated icons
+var manifestIconSizes = []int{32, 180, 192, 512}
+
+// IconLink is a <link> to a generated icon
+type IconLink struct {
+	Rel   string
+	Sizes string
+	Href  string
+}
+
+// ManifestGenerator object
+type ManifestGenerator struct {
+	Config *ManifestConfig
+}
+
+// ManifestConfig holds the configuration of the web manifest
+type ManifestConfig struct {
+	Icon            string
+	Name            string
+	ShortName       string
+	Description     string
+	ThemeColor      string
+	BackgroundColor string
+	Destination     string
+	Images          *ImageProcessor
+	DirMode         os.FileMode
+	FileMode        os.FileMode
+}
+
+type webManifest struct {
+	Name            string         `json:"name"`
+	ShortName       string         `json:"short_name,omitempty"`
+	Description     string         `json:"description,omitempty"`
+	StartURL        string         `json:"start_url"`
+	Display         string         `json:"display"`
+	ThemeColor      string         `json:"theme_color,omitempty"`
+	BackgroundColor string         `json:"background_color,omitempty"`
+	Icons           []manifestIcon `json:"icons"`
+}
+
+type manifestIcon struct {
+	Src   string `json:"src"`
+	Sizes string `json:"sizes"`
+	Type  string `json:"type"`
+}
+
+// getIconPath returns the URL of the generated icon of the given size
+func getIconPath(size int) string {
+	return fmt.Sprintf("/icons/icon-%d.png", size)
+}
+
+// getIconLinks returns the links to the manifest icons, nil if disabled
+func getIconLinks(icon string) []IconLink {
+	if icon == "" {
+		return nil
+	}
+	return []IconLink{
+		{Rel: "icon", Sizes: "32x32", Href: getIconPath(32)},
+		{Rel: "apple-touch-icon", Sizes: "180x180", Href: getIconPath(180)},
+		{Rel: "manifest", Href: "/manifest.json"},
+	}
+}
+
+// Generate writes the manifest.json and the icons
+func (g *ManifestGenerator) Generate() error {
+	if g.Config.Icon == "" {
+		return nil
+	}
+	fmt.Println("\tGenerating Manifest...")
+	src, err := g.Config.Images.ReadImage(g.Config.Icon)
+	if err != nil {
+		return err
+	}
+	iconsPath := filepath.Join(g.Config.Destination, "icons")
+	if err := createFolderIfNotExist(iconsPath, g.Config.DirMode); err != nil {
+		return err
+	}
+	manifest := webManifest{
+		Name:            g.Config.Name,
+		ShortName:       g.Config.ShortName,
+		Description:     g.Config.Description,
+		StartURL:        "/",
+		Display:         "standalone",
+		ThemeColor:      g.Config.ThemeColor,
+		BackgroundColor: g.Config.BackgroundColor,
+	}
+	for _, size := range manifestIconSizes {
+		iconPath := getIconPath(size)
+		if err := g.writeIcon(src, size, filepath.Join(g.Config.Destination, filepath.FromSlash(iconPath))); err != nil {
+			return err
+		}
+		manifest.Icons = append(manifest.Icons, manifestIcon{Src: iconPath, Sizes: fmt.Sprintf("%dx%d", size, size), Type: "image/png"})
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error creating manifest: %v", err)
+	}
+	filePath := filepath.Join(g.Config.Destination, "manifest.json")
+	f, err := createFile(filePath, g.Config.FileMode)
+	if err != nil {
+		return fmt.Errorf("error creating file %s: %v", filePath, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("error writing file %s: %v", filePath, err)
+	}
+	fmt.Println("\tFinished generating Manifest...")
+	return nil
+}
+
+// writeIcon writes src scaled to fit a square icon of size, icons of images
+// which aren't square are centered with transparent padding
+func (g *ManifestGenerator) writeIcon(src image.Image, size int, filePath string) error {
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, getIconRect(src.Bounds(), size), src, src.Bounds(), draw.Over, nil)
+	f, err := createFile(filePath, g.Config.FileMode)
+	if err != nil {
+		return fmt.Errorf("error creating file %s: %v", filePath, err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, dst); err != nil {
+		return fmt.Errorf("error writing file %s: %v", filePath, err)
+	}
+	return nil
+}
+
+// getIconRect returns the centered area of a square icon of size an image
+// of bounds is scaled to, keeping its aspect ratio
+func getIconRect(bounds image.Rectangle, size int) image.Rectangle {
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= 0 || h <= 0 || w == h {
+		return image.Rect(0, 0, size, size)
+	}
+	if w > h {
+		scaled := (h*size + w/2) / w
+		if scaled < 1 {
+			scaled = 1
+		}
+		top := (size - scaled) / 2
+		return image.Rect(0, top, size, top+scaled)
+	}
+	scaled := (w*size + h/2) / h
+	if scaled < 1 {
+		scaled = 1
+	}
+	left := (size - scaled) / 2
+	return image.Rect(left, 0, left+scaled, size)
+}
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"encoding/json"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetIconRect(t *testing.T) {
+	tests := []struct {
+		bounds image.Rectangle
+		want   image.Rectangle
+	}{
+		{image.Rect(0, 0, 64, 64), image.Rect(0, 0, 32, 32)},
+		{image.Rect(0, 0, 200, 100), image.Rect(0, 8, 32, 24)},
+		{image.Rect(0, 0, 100, 200), image.Rect(8, 0, 24, 32)},
+		{image.Rect(0, 0, 1000, 1), image.Rect(0, 15, 32, 16)},
+	}
+	for _, test := range tests {
+		if got := getIconRect(test.bounds, 32); got != test.want {
+			t.Errorf("icon of %v is %v, want %v", test.bounds, got, test.want)
+		}
+	}
+}
+
+func TestManifestIconsArePadded(t *testing.T) {
+	dir := t.TempDir()
+	icon := filepath.Join(dir, "icon.png")
+	writeTestFiles(t, dir, map[string]string{"icon.png": testPNG(t, 200, 100)})
+	g := ManifestGenerator{&ManifestConfig{
+		Icon:        icon,
+		Name:        "Test Blog",
+		Destination: filepath.Join(dir, "www"),
+		Images:      NewImageProcessor(1, 0, 0, 0644),
+		DirMode:     0755,
+		FileMode:    0644,
+	}}
+	if err := os.Mkdir(g.Config.Destination, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(filepath.Join(g.Config.Destination, "icons", "icon-192.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 192 || img.Bounds().Dy() != 192 {
+		t.Fatalf("icon is %v, want 192x192", img.Bounds())
+	}
+	if _, _, _, a := img.At(96, 10).RGBA(); a != 0 {
+		t.Errorf("padding of the icon isn't transparent")
+	}
+	if _, _, _, a := img.At(96, 96).RGBA(); a == 0 {
+		t.Errorf("center of the icon is transparent")
+	}
+	data, err := ioutil.ReadFile(filepath.Join(g.Config.Destination, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := webManifest{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Name != "Test Blog" || len(manifest.Icons) != len(manifestIconSizes) {
+		t.Errorf("got manifest %+v", manifest)
+	}
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/russross/blackfriday"
+)
+
+// markdownExtension is a renderer feature which can be toggled in the config
+type markdownExtension struct {
+	extensions int
+	htmlFlags  int
+	enabled    bool
+}
+
+// markdownExtensions are the toggleable features by config name, enabled
+// ones are the features of blackfriday.MarkdownCommon
+var markdownExtensions = map[string]markdownExtension{
+	"nointraemphasis":    {extensions: blackfriday.EXTENSION_NO_INTRA_EMPHASIS, enabled: true},
+	"tables":             {extensions: blackfriday.EXTENSION_TABLES, enabled: true},
+	"fencedcode":         {extensions: blackfriday.EXTENSION_FENCED_CODE, enabled: true},
+	"autolink":           {extensions: blackfriday.EXTENSION_AUTOLINK, enabled: true},
+	"strikethrough":      {extensions: blackfriday.EXTENSION_STRIKETHROUGH, enabled: true},
+	"spaceheaders":       {extensions: blackfriday.EXTENSION_SPACE_HEADERS, enabled: true},
+	"headerids":          {extensions: blackfriday.EXTENSION_HEADER_IDS, enabled: true},
+	"backslashlinebreak": {extensions: blackfriday.EXTENSION_BACKSLASH_LINE_BREAK, enabled: true},
+	"definitionlists":    {extensions: blackfriday.EXTENSION_DEFINITION_LISTS, enabled: true},
+	"laxhtmlblocks":      {extensions: blackfriday.EXTENSION_LAX_HTML_BLOCKS},
+	"hardlinebreak":      {extensions: blackfriday.EXTENSION_HARD_LINE_BREAK},
+	"footnotes":          {extensions: blackfriday.EXTENSION_FOOTNOTES},
+	"titleblock":         {extensions: blackfriday.EXTENSION_TITLEBLOCK},
+	"smartypants": {htmlFlags: blackfriday.HTML_USE_SMARTYPANTS | blackfriday.HTML_SMARTYPANTS_FRACTIONS |
+		blackfriday.HTML_SMARTYPANTS_DASHES | blackfriday.HTML_SMARTYPANTS_LATEX_DASHES, enabled: true},
+}
+
+// getMarkdownFlags returns the blackfriday extensions and html flags with the
+// toggles of the config applied to the defaults
+func getMarkdownFlags(toggles map[string]bool) (int, int, error) {
+	for name := range toggles {
+		if _, ok := markdownExtensions[name]; !ok {
+			return 0, 0, fmt.Errorf("unknown markdown extension %s, known are %v", name, sortedMarkdownExtensions())
+		}
+	}
+	extensions, htmlFlags := 0, blackfriday.HTML_USE_XHTML
+	for name, ext := range markdownExtensions {
+		if enabled, ok := toggles[name]; ok {
+			ext.enabled = enabled
+		}
+		if ext.enabled {
+			extensions |= ext.extensions
+			htmlFlags |= ext.htmlFlags
+		}
+	}
+	return extensions, htmlFlags, nil
+}
+
+func sortedMarkdownExtensions() []string {
+	names := make([]string, 0, len(markdownExtensions))
+	for name := range markdownExtensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,43 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestMarkdownToggles(t *testing.T) {
+	source := "---\ntitle: Toggles\ndate: 03.04.2021\n---\nfirst line\nsecond line\n\n| a | b |\n|---|---|\n| 1 | 2 |\n"
+	tests := []struct {
+		toggles map[string]bool
+		br      bool
+		table   bool
+	}{
+		{nil, false, true},
+		{map[string]bool{"hardlinebreak": true}, true, true},
+		{map[string]bool{"tables": false}, false, false},
+	}
+	for _, test := range tests {
+		cfg := &config.Config{}
+		cfg.Blog.URL = "https://example.com"
+		cfg.Blog.Markdown = test.toggles
+		post, err := RenderPost([]byte(source), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		html := string(post.HTML)
+		if got := strings.Contains(html, "first line<br"); got != test.br {
+			t.Errorf("toggles %v: hard line break %v, want %v:\n%s", test.toggles, got, test.br, html)
+		}
+		if got := strings.Contains(html, "<table"); got != test.table {
+			t.Errorf("toggles %v: table %v, want %v:\n%s", test.toggles, got, test.table, html)
+		}
+	}
+}
+
+func TestMarkdownUnknownToggle(t *testing.T) {
+	if _, _, err := getMarkdownFlags(map[string]bool{"emoji": true}); err == nil {
+		t.Error("expected an error for an unknown markdown extension")
+	}
+}
@@ -0,0 +1,268 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// mathSpan matches $$display$$ and $inline$ math, inline math must not start
+// or end with whitespace so prices like $5 and $10 are left alone
+var mathSpan = regexp.MustCompile(`\$\$([^$]+)\$\$|\$([^$\s](?:[^$]*[^$\s])?)\$`)
+
+// mathSkipped are the elements whose text is never treated as math
+var mathSkipped = map[string]bool{"pre": true, "code": true, "script": true, "style": true, "math": true}
+
+var mathIdentifiers = map[string]string{
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ε", "varepsilon": "ε",
+	"zeta": "ζ", "eta": "η", "theta": "θ", "vartheta": "ϑ", "iota": "ι", "kappa": "κ",
+	"lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ", "pi": "π", "rho": "ρ", "sigma": "σ",
+	"tau": "τ", "upsilon": "υ", "phi": "φ", "varphi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω",
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ", "Pi": "Π",
+	"Sigma": "Σ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω", "infty": "∞", "partial": "∂",
+	"nabla": "∇", "ell": "ℓ", "hbar": "ℏ",
+}
+
+var mathOperators = map[string]string{
+	"times": "×", "cdot": "⋅", "div": "÷", "pm": "±", "mp": "∓", "leq": "≤", "le": "≤",
+	"geq": "≥", "ge": "≥", "neq": "≠", "ne": "≠", "approx": "≈", "equiv": "≡", "sim": "∼",
+	"propto": "∝", "in": "∈", "notin": "∉", "subset": "⊂", "subseteq": "⊆", "cup": "∪",
+	"cap": "∩", "forall": "∀", "exists": "∃", "to": "→", "rightarrow": "→", "leftarrow": "←",
+	"Rightarrow": "⇒", "Leftrightarrow": "⇔", "mapsto": "↦", "sum": "∑", "prod": "∏",
+	"int": "∫", "oint": "∮", "cdots": "⋯", "ldots": "…", "dots": "…", "lbrace": "{",
+	"rbrace": "}", "{": "{", "}": "}", "langle": "⟨", "rangle": "⟩", "|": "‖", "mid": "|",
+}
+
+var mathFunctions = map[string]bool{
+	"sin": true, "cos": true, "tan": true, "cot": true, "sec": true, "csc": true, "arcsin": true,
+	"arccos": true, "arctan": true, "sinh": true, "cosh": true, "tanh": true, "log": true,
+	"ln": true, "exp": true, "lim": true, "max": true, "min": true, "sup": true, "inf": true,
+	"det": true, "gcd": true, "deg": true,
+}
+
+// convertFeedMath replaces the $inline$ and $$display$$ math of the html of a
+// post by MathML, as feed readers don't run the scripts rendering math
+func convertFeedMath(content []byte) ([]byte, error) {
+	if !bytes.Contains(content, []byte("$")) {
+		return content, nil
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing html: %v", err)
+	}
+	body := doc.Find("body")
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; {
+			next := c.NextSibling
+			switch {
+			case c.Type == html.ElementNode && !mathSkipped[c.Data]:
+				walk(c)
+			case c.Type == html.TextNode && mathSpan.MatchString(c.Data):
+				replaceMathText(c)
+			}
+			c = next
+		}
+	}
+	for _, n := range body.Nodes {
+		walk(n)
+	}
+	result, err := body.Html()
+	if err != nil {
+		return nil, fmt.Errorf("error while rendering html: %v", err)
+	}
+	return []byte(result), nil
+}
+
+// replaceMathText splits the text node n into text and MathML nodes
+func replaceMathText(n *html.Node) {
+	text := n.Data
+	var markup strings.Builder
+	last := 0
+	for _, m := range mathSpan.FindAllStringSubmatchIndex(text, -1) {
+		markup.WriteString(html.EscapeString(text[last:m[0]]))
+		if m[2] >= 0 {
+			markup.WriteString(texToMathML(text[m[2]:m[3]], true))
+		} else {
+			markup.WriteString(texToMathML(text[m[4]:m[5]], false))
+		}
+		last = m[1]
+	}
+	markup.WriteString(html.EscapeString(text[last:]))
+	context := &html.Node{Type: html.ElementNode, Data: "span", DataAtom: atom.Span}
+	nodes, err := html.ParseFragment(strings.NewReader(markup.String()), context)
+	if err != nil {
+		return
+	}
+	for _, node := range nodes {
+		n.Parent.InsertBefore(node, n)
+	}
+	n.Parent.RemoveChild(n)
+}
+
+// texToMathML converts a subset of TeX to MathML: identifiers, numbers,
+// operators, groups, sub- and superscripts, \frac, \sqrt, greek letters and
+// common symbols and functions. Unknown commands are kept as text.
+func texToMathML(tex string, display bool) string {
+	p := &texParser{src: []rune(strings.TrimSpace(tex))}
+	row := p.parseRow(false)
+	attrs := ` xmlns="http://www.w3.org/1998/Math/MathML"`
+	if display {
+		attrs += ` display="block"`
+	}
+	return fmt.Sprintf(`<math%s><semantics>%s<annotation encoding="application/x-tex">%s</annotation></semantics></math>`,
+		attrs, mrow(row), html.EscapeString(strings.TrimSpace(tex)))
+}
+
+type texParser struct {
+	src []rune
+	pos int
+}
+
+func (p *texParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+// parseRow parses elements until the end or, in a group, the closing brace
+func (p *texParser) parseRow(group bool) []string {
+	var row []string
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return row
+		}
+		if p.src[p.pos] == '}' {
+			if group {
+				p.pos++
+				return row
+			}
+			// unbalanced braces are shown as they are
+			p.pos++
+			row = append(row, "<mo>}</mo>")
+			continue
+		}
+		if el := p.parseAtom(); el != "" {
+			row = append(row, p.parseScripts(el))
+		}
+	}
+}
+
+// parseScripts attaches the sub- and superscripts following base
+func (p *texParser) parseScripts(base string) string {
+	var sub, sup string
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) || (p.src[p.pos] != '_' && p.src[p.pos] != '^') {
+			break
+		}
+		c := p.src[p.pos]
+		p.pos++
+		arg := p.parseArgument()
+		if c == '_' {
+			sub = arg
+		} else {
+			sup = arg
+		}
+	}
+	switch {
+	case sub != "" && sup != "":
+		return "<msubsup>" + base + sub + sup + "</msubsup>"
+	case sub != "":
+		return "<msub>" + base + sub + "</msub>"
+	case sup != "":
+		return "<msup>" + base + sup + "</msup>"
+	}
+	return base
+}
+
+// parseArgument parses a group or a single element
+func (p *texParser) parseArgument() string {
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == '{' {
+		p.pos++
+		return mrow(p.parseRow(true))
+	}
+	if p.pos >= len(p.src) {
+		return "<mrow></mrow>"
+	}
+	if unicode.IsDigit(p.src[p.pos]) {
+		// a single digit, x^23 is x squared followed by 3
+		p.pos++
+		return "<mn>" + string(p.src[p.pos-1]) + "</mn>"
+	}
+	return p.parseAtom()
+}
+
+func (p *texParser) parseAtom() string {
+	c := p.src[p.pos]
+	switch {
+	case c == '{':
+		p.pos++
+		return mrow(p.parseRow(true))
+	case c == '\\':
+		return p.parseCommand()
+	case unicode.IsDigit(c) || c == '.' && p.pos+1 < len(p.src) && unicode.IsDigit(p.src[p.pos+1]):
+		start := p.pos
+		for p.pos < len(p.src) && (unicode.IsDigit(p.src[p.pos]) || p.src[p.pos] == '.') {
+			p.pos++
+		}
+		return "<mn>" + string(p.src[start:p.pos]) + "</mn>"
+	case unicode.IsLetter(c):
+		p.pos++
+		return "<mi>" + html.EscapeString(string(c)) + "</mi>"
+	case c == '\'':
+		p.pos++
+		return "<mo>′</mo>"
+	}
+	p.pos++
+	return "<mo>" + html.EscapeString(string(c)) + "</mo>"
+}
+
+func (p *texParser) parseCommand() string {
+	p.pos++
+	if p.pos >= len(p.src) {
+		return "<mo>\\</mo>"
+	}
+	start := p.pos
+	if unicode.IsLetter(p.src[p.pos]) {
+		for p.pos < len(p.src) && unicode.IsLetter(p.src[p.pos]) {
+			p.pos++
+		}
+	} else {
+		p.pos++
+	}
+	name := string(p.src[start:p.pos])
+	switch name {
+	case "frac":
+		return "<mfrac>" + p.parseArgument() + p.parseArgument() + "</mfrac>"
+	case "sqrt":
+		return "<msqrt>" + p.parseArgument() + "</msqrt>"
+	case "left", "right", ",", ";", ":", "!", " ", "quad", "qquad":
+		return ""
+	}
+	if s, ok := mathIdentifiers[name]; ok {
+		return "<mi>" + s + "</mi>"
+	}
+	if s, ok := mathOperators[name]; ok {
+		return "<mo>" + html.EscapeString(s) + "</mo>"
+	}
+	if mathFunctions[name] {
+		return "<mi>" + name + "</mi>"
+	}
+	return "<mtext>\\" + html.EscapeString(name) + "</mtext>"
+}
+
+func mrow(row []string) string {
+	if len(row) == 1 {
+		return row[0]
+	}
+	return "<mrow>" + strings.Join(row, "") + "</mrow>"
+}
@@ -0,0 +1,22 @@
+package generator
+
+import "fmt"
+
+// MetaTag is a custom <meta> tag of a post, named by either Name or Property
+// (of Open Graph tags)
+type MetaTag struct {
+	Name     string
+	Property string
+	Content  string
+}
+
+// checkMetaTags makes sure every custom meta tag has either a name or a
+// property
+func checkMetaTags(tags []MetaTag) error {
+	for i, tag := range tags {
+		if (tag.Name == "") == (tag.Property == "") {
+			return fmt.Errorf("meta tag %d has to have either a name or a property", i+1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMetaTagsOnTheirPostOnly(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"tagged/post.md": testPost("Tagged", "01.02.2021", "Tagged.",
+			"metaTags:",
+			"  - name: fediverse:creator",
+			"    content: '@tester@example.social'",
+			"  - property: og:video",
+			"    content: https://example.com/video.mp4"),
+		"plain/post.md": testPost("Plain", "02.02.2021", "Plain."),
+	}).build()
+	tags := []string{
+		`<meta name="fediverse:creator" content="@tester@example.social">`,
+		`<meta property="og:video" content="https://example.com/video.mp4">`,
+	}
+	tagged := site.read("tagged/index.html")
+	for _, tag := range tags {
+		if !strings.Contains(tagged, tag) {
+			t.Errorf("post doesn't have %s", tag)
+		}
+	}
+	for _, page := range []string{"plain/index.html", "blog/index.html"} {
+		html := site.read(page)
+		if strings.Contains(html, "fediverse:creator") || strings.Contains(html, "og:video") {
+			t.Errorf("%s has the meta tags of another post", page)
+		}
+	}
+}
+
+func TestInvalidMetaTag(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"both/post.md": testPost("Both", "01.02.2021", "Both.", "metaTags:", "  - name: a", "    property: b", "    content: c"),
+	})
+	_, err := newTestLoader(t, root, newTestConfig(t, nil)).newPost(filepath.Join(root, "both"))
+	if err == nil || !strings.Contains(err.Error(), "either a name or a property") {
+		t.Errorf("meta tag with a name and a property returned %v", err)
+	}
+}
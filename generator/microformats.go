@@ -0,0 +1,25 @@
+package generator
+
+// Entry is the h-entry microformat data of a post page, the URLs are absolute
+type Entry struct {
+	URL       string
+	Published string
+	Updated   string
+	// Date is the date of the post as written in its meta data
+	Date      string
+	Author    string
+	AuthorURL string
+}
+
+// newEntry returns the h-entry data of a post, its author is the blog author
+// with the blog as home page
+func newEntry(post *Post, blogURL, author string) *Entry {
+	return &Entry{
+		URL:       post.URL,
+		Published: formatJSONLDDate(post.Meta.ParsedDate),
+		Updated:   formatJSONLDDate(post.Meta.ParsedUpdated),
+		Date:      post.Meta.Date,
+		Author:    author,
+		AuthorURL: blogURL + "/",
+	}
+}
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestHEntryClasses(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"first/post.md": testPost("First Post", "01.02.2021", "The first post.", "updated: 05.02.2021"),
+	}).build()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(site.read("first/index.html")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := doc.Find(".h-entry")
+	if entry.Length() != 1 {
+		t.Fatalf("post has %d h-entries, want 1", entry.Length())
+	}
+	checks := []struct {
+		selector string
+		attr     string
+		want     string
+	}{
+		{".p-name", "", "First Post"},
+		{".p-name a.u-url", "href", "https://example.com/first/"},
+		{"time.dt-published", "datetime", "2021-02-01T00:00:00Z"},
+		{"time.dt-published", "", "01.02.2021"},
+		{"time.dt-updated", "datetime", "2021-02-05T00:00:00Z"},
+		{".p-author.h-card", "", "Tester"},
+		{".p-author.h-card", "href", "https://example.com/"},
+		{".e-content", "", "The first post."},
+	}
+	for _, check := range checks {
+		s := entry.Find(check.selector)
+		got := strings.TrimSpace(s.Text())
+		if check.attr != "" {
+			got = s.AttrOr(check.attr, "")
+		}
+		if s.Length() != 1 || got != check.want {
+			t.Errorf("%s %s is %q (%d matches), want %q", check.selector, check.attr, got, s.Length(), check.want)
+		}
+	}
+	if strings.Contains(site.read("blog/index.html"), "h-entry") {
+		t.Error("index page is marked as h-entry")
+	}
+}
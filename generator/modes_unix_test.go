@@ -0,0 +1,44 @@
+//go:build unix
+
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestOutputModes(t *testing.T) {
+	umask := syscall.Umask(027)
+	defer syscall.Umask(umask)
+	cfg := newTestConfig(t, func(cfg *config.Config) {
+		cfg.Generator.DirMode = 0775
+		cfg.Generator.FileMode = 0664
+	})
+	site := newTestSite(t, cfg, map[string]string{
+		"post/post.md":        testPost("Post", "01.02.2021", "A cat: ![cat](images/cat.png)"),
+		"post/images/cat.png": testPNG(t, 4, 4),
+	}).build()
+	if !site.exists("post/images/cat.png") {
+		t.Fatal("image wasn't copied")
+	}
+	err := filepath.Walk(site.Dest, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		want := os.FileMode(0640)
+		if info.IsDir() {
+			want = 0750
+		}
+		if mode := info.Mode().Perm(); mode != want {
+			t.Errorf("%s has mode %o, want %o", path, mode, want)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// ogImageFile is the name of the generated OpenGraph image of a post
+const ogImageFile = "og.png"
+
+const (
+	ogImageWidth    = 1200
+	ogImageHeight   = 630
+	ogImagePadding  = 80
+	ogTitleSize     = 64
+	ogTitleMaxLines = 4
+	ogTextSize      = 32
+)
+
+// OGImageConfig holds the configuration for the OpenGraph cards of posts
+type OGImageConfig struct {
+	Font       *opentype.Font
+	Background color.Color
+	Color      color.Color
+	SiteName   string
+	Author     string
+}
+
+// newOGImageConfig loads the font of the cards, the Go Bold font is used if
+// fontPath is empty
+func newOGImageConfig(fontPath, background, foreground, siteName, author string) (*OGImageConfig, error) {
+	data := gobold.TTF
+	if fontPath != "" {
+		var err error
+		if data, err = ioutil.ReadFile(fontPath); err != nil {
+			return nil, fmt.Errorf("error reading font %s: %v", fontPath, err)
+		}
+	}
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing font %s: %v", fontPath, err)
+	}
+	bg, err := parseHexColor(background)
+	if err != nil {
+		return nil, err
+	}
+	fg, err := parseHexColor(foreground)
+	if err != nil {
+		return nil, err
+	}
+	return &OGImageConfig{Font: f, Background: bg, Color: fg, SiteName: siteName, Author: author}, nil
+}
+
+// parseHexColor parses colors like #336699
+func parseHexColor(s string) (color.Color, error) {
+	hex := strings.TrimPrefix(s, "#")
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 6 || err != nil {
+		return nil, fmt.Errorf("invalid color %q, expected e.g. #336699", s)
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// generateOGImage renders the card of the post with its title, date, the
+// author and the site name to og.png and returns its URL
+func (g *PostGenerator) generateOGImage(staticPath string) (string, error) {
+	post := g.Config.Post
+	cfg := g.Config.OGImage
+	img := image.NewRGBA(image.Rect(0, 0, ogImageWidth, ogImageHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(cfg.Background), image.Point{}, draw.Src)
+	titleFace, err := opentype.NewFace(cfg.Font, &opentype.FaceOptions{Size: ogTitleSize, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return "", fmt.Errorf("error creating font face: %v", err)
+	}
+	defer titleFace.Close()
+	textFace, err := opentype.NewFace(cfg.Font, &opentype.FaceOptions{Size: ogTextSize, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return "", fmt.Errorf("error creating font face: %v", err)
+	}
+	defer textFace.Close()
+	d := &font.Drawer{Dst: img, Src: image.NewUniform(cfg.Color), Face: textFace}
+	d.Dot = fixed.P(ogImagePadding, ogImagePadding+ogTextSize)
+	d.DrawString(cfg.SiteName)
+
+	d.Face = titleFace
+	y := ogImagePadding + 2*ogTextSize + ogTitleSize
+	for _, line := range wrapText(titleFace, post.Meta.Title, ogImageWidth-2*ogImagePadding, ogTitleMaxLines) {
+		d.Dot = fixed.P(ogImagePadding, y)
+		d.DrawString(line)
+		y += ogTitleSize * 5 / 4
+	}
+
+	footer := []string{}
+	for _, part := range []string{post.Meta.Date, cfg.Author} {
+		if part != "" {
+			footer = append(footer, part)
+		}
+	}
+	d.Face = textFace
+	d.Dot = fixed.P(ogImagePadding, ogImageHeight-ogImagePadding)
+	d.DrawString(strings.Join(footer, " · "))
+
+	images := g.Config.Images
+	images.acquire()
+	defer images.release()
+	if err := images.writeImage(img, filepath.Join(staticPath, ogImageFile)); err != nil {
+		return "", err
+	}
+	return post.URL + ogImageFile, nil
+}
+
+// wrapText breaks text into lines at most width wide, text which doesn't fit
+// into maxLines is cut with an ellipsis
+func wrapText(face font.Face, text string, width, maxLines int) []string {
+	max := fixed.I(width)
+	lines := []string{}
+	line := ""
+	for _, word := range strings.Fields(text) {
+		candidate := strings.TrimSpace(line + " " + word)
+		if line == "" || font.MeasureString(face, candidate) <= max {
+			line = candidate
+			continue
+		}
+		lines = append(lines, line)
+		line = word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		last := lines[maxLines-1] + seoEllipsis
+		for font.MeasureString(face, last) > max && strings.Contains(last, " ") {
+			last = last[:strings.LastIndex(last, " ")] + seoEllipsis
+		}
+		lines[maxLines-1] = last
+	}
+	return lines
+}
@@ -0,0 +1,76 @@
+package generator
+
+import (
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestOGImage(t *testing.T) {
+	cfg := newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.OGImage.Enabled = true
+	})
+	site := newTestSite(t, cfg, map[string]string{
+		"card/post.md":  testPost("Card Post", "01.02.2021", "Without an image."),
+		"cover/post.md": testPost("Cover Post", "02.02.2021", "With an image.", "image: https://example.org/cover.jpg"),
+	}).build()
+
+	file, err := os.Open(filepath.Join(site.Dest, "card", "og.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	card, err := png.DecodeConfig(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.Width != 1200 || card.Height != 630 {
+		t.Errorf("card is %dx%d, want 1200x630", card.Width, card.Height)
+	}
+	page := site.read("card/index.html")
+	if !strings.Contains(page, `<meta property="og:image" content="https://example.com/card/og.png">`) {
+		t.Error("og:image isn't the generated card")
+	}
+	if image := getTestJSONLD(t, page).Image; image != "https://example.com/card/og.png" {
+		t.Errorf("JSON-LD image is %q, want the generated card", image)
+	}
+
+	if site.exists("cover/og.png") {
+		t.Error("card generated for a post with an image")
+	}
+	if image := getTestJSONLD(t, site.read("cover/index.html")).Image; image != "https://example.org/cover.jpg" {
+		t.Errorf("JSON-LD image is %q, want the image of the post", image)
+	}
+}
+
+func TestDefaultImage(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.DefaultImage = "img/cover.png"
+	}), map[string]string{
+		"plain/post.md":          testPost("Plain Post", "01.02.2021", "Without an image."),
+		"meta/post.md":           testPost("Meta Post", "02.02.2021", "With an image.", "image: https://example.org/cover.jpg"),
+		"first/post.md":          testPost("First Post", "03.02.2021", "![Photo](images/photo.png)"),
+		"first/images/photo.png": testPNG(t, 4, 4),
+	}).build()
+	ogImage := func(url string) string {
+		return `<meta property="og:image" content="` + url + `">`
+	}
+	tests := map[string]string{
+		"plain/index.html": "https://example.com/img/cover.png",
+		"meta/index.html":  "https://example.org/cover.jpg",
+		"first/index.html": "https://example.com/first/images/photo.png",
+	}
+	for page, want := range tests {
+		html := site.read(page)
+		if !strings.Contains(html, ogImage(want)) || strings.Count(html, `property="og:image"`) != 1 {
+			t.Errorf("%s doesn't use %s as its only og:image", page, want)
+		}
+	}
+	if !strings.Contains(site.read("plain/index.html"), `<meta name="twitter:image" content="https://example.com/img/cover.png">`) {
+		t.Error("imageless post doesn't use the default twitter:image")
+	}
+}
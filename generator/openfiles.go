@@ -0,0 +1,30 @@
+package generator
+
+const (
+	// defaultOpenFiles is the cap on the files opened by image copies at
+	// once if the descriptor limit of the process isn't known
+	defaultOpenFiles = 64
+	// maxDefaultOpenFiles keeps the derived cap reasonable for processes
+	// without a real limit
+	maxDefaultOpenFiles = 1024
+	// openFilesShare is the share of the descriptor limit image copies get
+	// by default, the rest is left to the pages, feeds and git
+	openFilesShare = 4
+)
+
+// getDefaultOpenFiles returns the cap on the files opened by image copies at
+// once derived from the descriptor limit of the process
+func getDefaultOpenFiles() int {
+	limit := getOpenFilesLimit()
+	if limit <= 0 {
+		return defaultOpenFiles
+	}
+	n := limit / openFilesShare
+	if n < 2 {
+		n = 2
+	}
+	if n > maxDefaultOpenFiles {
+		n = maxDefaultOpenFiles
+	}
+	return n
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package generator
+
+// getOpenFilesLimit returns 0 as the descriptor limit isn't known
+func getOpenFilesLimit() int {
+	return 0
+}
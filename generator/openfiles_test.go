@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOpenFilesCapBlocksCopies(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"image.png": testPNG(t, 4, 4)})
+	p := NewImageProcessor(8, 0, 0, 0644)
+	// two descriptors per copy, a cap of 4 allows two copies at once
+	p.SetOpenFiles(4)
+	p.openFiles()
+	p.openFiles()
+	done := make(chan error, 2)
+	go func() {
+		done <- p.Copy(filepath.Join(dir, "image.png"), filepath.Join(dir, "copy.png"))
+	}()
+	go func() {
+		done <- p.Process(filepath.Join(dir, "image.png"), filepath.Join(dir, "processed.png"))
+	}()
+	select {
+	case <-done:
+		t.Fatal("file was copied while all descriptors were taken")
+	case <-time.After(50 * time.Millisecond):
+	}
+	p.closeFiles()
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("files weren't copied after descriptors were released")
+		}
+	}
+	p.closeFiles()
+}
+
+func TestGetDefaultOpenFiles(t *testing.T) {
+	n := getDefaultOpenFiles()
+	if n < 2 || n > maxDefaultOpenFiles {
+		t.Errorf("default cap is %d, want between 2 and %d", n, maxDefaultOpenFiles)
+	}
+	if limit := getOpenFilesLimit(); limit > 0 && n > limit {
+		t.Errorf("default cap %d is above the descriptor limit %d", n, limit)
+	}
+}
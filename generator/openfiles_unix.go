@@ -0,0 +1,18 @@
+//go:build unix
+
+package generator
+
+import "syscall"
+
+// getOpenFilesLimit returns the soft limit of open descriptors of the
+// process, 0 if it isn't known
+func getOpenFilesLimit() int {
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		return 0
+	}
+	if limit.Cur > 1<<30 {
+		return 1 << 30
+	}
+	return int(limit.Cur)
+}
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+)
+
+// OPMLGenerator object
+type OPMLGenerator struct {
+	Config *OPMLConfig
+}
+
+// OPMLConfig holds the configuration of the OPML list of the feeds
+type OPMLConfig struct {
+	BlogURL   string
+	BlogTitle string
+	// Tags are the tags with a feed, nil if tag feeds are disabled
+	Tags []string
+	// Updated adds the feed of updated posts
+	Updated     bool
+	Destination string
+	FileMode    os.FileMode
+	Location    *time.Location
+	Paths       SitePaths
+}
+
+// Generate writes feeds.opml listing the main feed and the feeds of the tags
+func (g *OPMLGenerator) Generate() error {
+	fmt.Println("\tGenerating OPML...")
+	blogURL := strings.TrimSuffix(g.Config.BlogURL, "/")
+	doc := etree.NewDocument()
+	doc.CreateProcInst("xml", `version="1.0" encoding="UTF-8"`)
+	opml := doc.CreateElement("opml")
+	opml.CreateAttr("version", "2.0")
+	head := opml.CreateElement("head")
+	head.CreateElement("title").SetText(g.Config.BlogTitle)
+	head.CreateElement("dateCreated").SetText(time.Now().In(g.Config.Location).Format(rssDateFormat))
+	body := opml.CreateElement("body")
+	addOPMLOutline(body, g.Config.BlogTitle, blogURL+"/index.xml", blogURL+"/")
+	if g.Config.Updated {
+		addOPMLOutline(body, getUpdatedFeedTitle(g.Config.BlogTitle), blogURL+updatedPath+"index.xml", blogURL+"/")
+	}
+	tags := append([]string{}, g.Config.Tags...)
+	sort.Strings(tags)
+	for _, tag := range tags {
+		link := blogURL + g.Config.Paths.tagLink(tag)
+		addOPMLOutline(body, getTagFeedTitle(tag, g.Config.BlogTitle), link+"index.xml", link)
+	}
+	filePath := filepath.Join(g.Config.Destination, "feeds.opml")
+	f, err := createFile(filePath, g.Config.FileMode)
+	if err != nil {
+		return fmt.Errorf("error creating file %s: %v", filePath, err)
+	}
+	defer f.Close()
+	if _, err := doc.WriteTo(f); err != nil {
+		return fmt.Errorf("error writing to file %s: %v", filePath, err)
+	}
+	fmt.Println("\tFinished generating OPML...")
+	return nil
+}
+
+func addOPMLOutline(body *etree.Element, title, feedURL, htmlURL string) {
+	outline := body.CreateElement("outline")
+	outline.CreateAttr("type", "rss")
+	outline.CreateAttr("text", title)
+	outline.CreateAttr("title", title)
+	outline.CreateAttr("xmlUrl", feedURL)
+	outline.CreateAttr("htmlUrl", htmlURL)
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestOPML(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.Feeds.OPML = true
+		cfg.Blog.Feeds.Tags = true
+	}), map[string]string{
+		"first/post.md":  testPost("First Post", "01.02.2021", "The first post.", "tags: [web, go]"),
+		"second/post.md": testPost("Second Post", "02.02.2021", "The second post.", "tags: [go]"),
+	}).build()
+	var opml struct {
+		XMLName xml.Name `xml:"opml"`
+		Version string   `xml:"version,attr"`
+		Title   string   `xml:"head>title"`
+		Outline []struct {
+			Type    string `xml:"type,attr"`
+			Title   string `xml:"title,attr"`
+			XMLURL  string `xml:"xmlUrl,attr"`
+			HTMLURL string `xml:"htmlUrl,attr"`
+		} `xml:"body>outline"`
+	}
+	if err := xml.Unmarshal([]byte(site.read("feeds.opml")), &opml); err != nil {
+		t.Fatalf("feeds.opml isn't valid xml: %v", err)
+	}
+	if opml.Version != "2.0" || opml.Title != "Test Blog" {
+		t.Errorf("got opml version %q and title %q", opml.Version, opml.Title)
+	}
+	var feeds [][3]string
+	for _, outline := range opml.Outline {
+		if outline.Type != "rss" {
+			t.Errorf("outline %s has type %q", outline.Title, outline.Type)
+		}
+		feeds = append(feeds, [3]string{outline.Title, outline.XMLURL, outline.HTMLURL})
+	}
+	want := [][3]string{
+		{"Test Blog", "https://example.com/index.xml", "https://example.com/"},
+		{"go - Test Blog", "https://example.com/tags/go/index.xml", "https://example.com/tags/go/"},
+		{"web - Test Blog", "https://example.com/tags/web/index.xml", "https://example.com/tags/web/"},
+	}
+	if !reflect.DeepEqual(feeds, want) {
+		t.Errorf("opml lists %v, want %v", feeds, want)
+	}
+	for _, feed := range want[1:] {
+		if !site.exists(feed[1][len("https://example.com/"):]) {
+			t.Errorf("listed feed %s wasn't generated", feed[1])
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+// SitePaths are the folders of the tag and archive pages below the root of
+// the blog, empty folders are the defaults tags and archive. CJK is how the
+// tag slugs handle CJK characters.
+type SitePaths struct {
+	Tags    string
+	Archive string
+	CJK     string
+	// slugs are the folders of the tags given to withTags, by lowercased tag
+	slugs map[string]string
+}
+
+// newSitePaths returns the paths of the tag and archive pages of cfg
+func newSitePaths(cfg *config.Config) SitePaths {
+	return SitePaths{Tags: cfg.Blog.Paths.Tags, Archive: cfg.Blog.Paths.Archive, CJK: cfg.Blog.Slugs.CJK}
+}
+
+func (p SitePaths) tagsDir() string {
+	if p.Tags == "" {
+		return "tags"
+	}
+	return p.Tags
+}
+
+func (p SitePaths) archiveDir() string {
+	if p.Archive == "" {
+		return "archive"
+	}
+	return p.Archive
+}
+
+// TagsLink returns the path of the tag index page
+func (p SitePaths) TagsLink() string {
+	return "/" + p.tagsDir() + "/"
+}
+
+// ArchiveLink returns the path of the archive page
+func (p SitePaths) ArchiveLink() string {
+	return "/" + p.archiveDir() + "/"
+}
+
+// withTags returns p with a folder for each of tags. Tags that slugify to the
+// same folder get a numbered suffix in sorted order, so "C" keeps tags/c/ and
+// "C#" gets tags/c-1/.
+func (p SitePaths) withTags(tags []string) SitePaths {
+	sorted := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		sorted = append(sorted, strings.ToLower(tag))
+	}
+	sort.Strings(sorted)
+	p.slugs = make(map[string]string, len(sorted))
+	used := map[string]bool{}
+	for _, tag := range sorted {
+		if _, ok := p.slugs[tag]; ok {
+			continue
+		}
+		p.slugs[tag] = uniqueID(slugify(tag, p.CJK), used)
+	}
+	return p
+}
+
+// tagSlug returns the folder of the page of tag
+func (p SitePaths) tagSlug(tag string) string {
+	if slug, ok := p.slugs[strings.ToLower(tag)]; ok {
+		return slug
+	}
+	return slugify(tag, p.CJK)
+}
+
+// tagLink returns the path of the page of tag
+func (p SitePaths) tagLink(tag string) string {
+	return fmt.Sprintf("%s%s/", p.TagsLink(), p.tagSlug(tag))
+}
@@ -0,0 +1,38 @@
+package generator
+
+import "testing"
+
+func TestSitePathsLinks(t *testing.T) {
+	tests := []struct {
+		paths   SitePaths
+		tags    string
+		archive string
+		tag     string
+	}{
+		{SitePaths{}, "/tags/", "/archive/", "/tags/go-lang/"},
+		{SitePaths{Tags: "topics", Archive: "all"}, "/topics/", "/all/", "/topics/go-lang/"},
+	}
+	for _, test := range tests {
+		if got := test.paths.TagsLink(); got != test.tags {
+			t.Errorf("TagsLink of %+v = %s, want %s", test.paths, got, test.tags)
+		}
+		if got := test.paths.ArchiveLink(); got != test.archive {
+			t.Errorf("ArchiveLink of %+v = %s, want %s", test.paths, got, test.archive)
+		}
+		if got := test.paths.tagLink("Go Lang"); got != test.tag {
+			t.Errorf("tagLink of %+v = %s, want %s", test.paths, got, test.tag)
+		}
+	}
+}
+
+func TestCreateTagListUsesPaths(t *testing.T) {
+	tagPostsMap := map[string][]*Post{"go": {{}}}
+	topics := createTagList(tagPostsMap, SitePaths{Tags: "topics"})
+	tags := createTagList(tagPostsMap, SitePaths{})
+	if topics[0].Link != "/topics/go/" {
+		t.Errorf("got link %s, want /topics/go/", topics[0].Link)
+	}
+	if tags[0].Link != "/tags/go/" {
+		t.Errorf("got link %s, want /tags/go/", tags[0].Link)
+	}
+}
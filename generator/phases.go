@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// the phases of a generation whose time is measured
+const (
+	phaseLoading = iota
+	phaseRendering
+	phaseHighlighting
+	phaseImages
+	phaseWriting
+	phaseCount
+)
+
+var phaseNames = [phaseCount]string{"loading", "rendering", "highlighting", "images", "writing"}
+
+// PhaseTimings sums the time spent in the phases of a generation, it is safe
+// for concurrent use. The time of parallel workers adds up, so phases can take
+// longer than the build. Rendering and highlighting are part of loading, as
+// the posts are rendered while they are read.
+type PhaseTimings struct {
+	durations [phaseCount]int64
+}
+
+func newPhaseTimings() *PhaseTimings {
+	return &PhaseTimings{}
+}
+
+// since adds the time passed since start to phase, it is meant to be deferred
+// at the start of the phase. A nil PhaseTimings measures nothing.
+func (p *PhaseTimings) since(phase int, start time.Time) {
+	if p == nil {
+		return
+	}
+	atomic.AddInt64(&p.durations[phase], int64(time.Since(start)))
+}
+
+// Durations returns the time spent in each phase by its name
+func (p *PhaseTimings) Durations() map[string]time.Duration {
+	result := make(map[string]time.Duration, phaseCount)
+	for phase, name := range phaseNames {
+		var d int64
+		if p != nil {
+			d = atomic.LoadInt64(&p.durations[phase])
+		}
+		result[name] = time.Duration(d)
+	}
+	return result
+}
+
+// String returns the phases with their durations in the order they run
+func (p *PhaseTimings) String() string {
+	durations := p.Durations()
+	parts := make([]string, 0, phaseCount)
+	for _, name := range phaseNames {
+		parts = append(parts, fmt.Sprintf("%s %v", name, durations[name].Round(time.Microsecond)))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,27 @@
+package generator
+
+import "testing"
+
+func TestPhaseTimingsArePopulated(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"first/post.md":          testPost("First Post", "01.02.2021", "![Image](images/image.png)\n\n```go\nfunc main() {}\n```\n"),
+		"first/images/image.png": testPNG(t, 8, 8),
+	})
+	var stats *BuildStats
+	g := New(&SiteConfig{Sources: site.sources(), SourceRoot: site.Root, Destination: site.Dest, Config: site.Config})
+	g.Config.PostBuild = func(s *BuildStats) error {
+		stats = s
+		return nil
+	}
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range phaseNames {
+		if d, ok := stats.Phases[name]; !ok || d <= 0 {
+			t.Errorf("phase %s took %v", name, d)
+		}
+	}
+	if len(stats.Phases) != len(phaseNames) {
+		t.Errorf("got phases %v", stats.Phases)
+	}
+}
@@ -3,17 +3,17 @@ package generator
 import (
 	"bufio"
 	"bytes"
-	"errors"
 	"fmt"
 	"github.com/PuerkitoBio/goquery"
 	"github.com/russross/blackfriday"
-	"github.com/sourcegraph/syntaxhighlight"
 	"gopkg.in/yaml.v2"
 	"html/template"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"time"
 )
@@ -21,10 +21,38 @@ import (
 // Post holds data for a post
 type Post struct {
 	Name      string
+	Path      string
+	URL       string
+	Category  string
+	Dir       string
+	File      string
+	Markdown  []byte
+	BodyLine  int
 	HTML      []byte
 	Meta      *Meta
 	ImagesDir string
 	Images    []string
+	// FilesDir is the folder of the attachments of the post
+	FilesDir    string
+	Attachments []*Attachment
+	// Summary is the description of the post taken from the first
+	// non-empty excerpt source, Excerpt is the truncated summary as shown in
+	// listings and feeds
+	Summary string
+	Excerpt string
+	// SharedImagesDir is the folder of the images shared by all posts
+	SharedImagesDir string
+	License         *License
+	TagNav          []*TagNavigation
+	Related         []*PostLink
+	// TOC is the table of contents of the post, empty if it is disabled
+	TOC         template.HTML
+	ReadingTime ReadingTime
+	// OldSlugs are the previous names of the post from its slug history
+	OldSlugs []string
+	// EditLink opens the source of the post in the editor, only set in dev
+	// mode
+	EditLink template.URL
 }
 
 // ByDateDesc is the sorting object for posts
@@ -40,7 +68,17 @@ type PostConfig struct {
 	Post        *Post
 	Destination string
 	Template    *template.Template
+	AMPTemplate *template.Template
+	Images      *ImageProcessor
+	Export      *ExportConfig
+	PDF         *PDFConfig
+	OGImage     *OGImageConfig
 	Writer      *IndexWriter
+	// Trailer is rendered with the post and shown below its content
+	Trailer  *template.Template
+	Comments *CommentsConfig
+	// Webmentions are the received webmentions by target URL
+	Webmentions map[string][]*Webmention
 }
 
 // Generate generates a post
@@ -49,47 +87,327 @@ func (g *PostGenerator) Generate() error {
 	destination := g.Config.Destination
 	t := g.Config.Template
 	fmt.Printf("\tGenerating Post: %s...\n", post.Meta.Title)
+	writer := g.Config.Writer
 	staticPath := filepath.Join(destination, post.Name)
-	if err := os.Mkdir(staticPath, os.ModePerm); err != nil {
+	if err := os.MkdirAll(staticPath, writer.DirMode); err != nil {
 		return fmt.Errorf("error creating directory at %s: %v", staticPath, err)
 	}
 	if post.ImagesDir != "" {
-		if err := copyImagesDir(post.ImagesDir, staticPath); err != nil {
+		if err := copyImagesDir(post.ImagesDir, staticPath, writer.DirMode, g.Config.Images, post.Meta.KeepImages); err != nil {
+			return err
+		}
+	}
+	if post.FilesDir != "" {
+		if err := copyAttachments(post, staticPath, writer); err != nil {
+			return err
+		}
+	}
+	extraCSS, err := copyExtraFiles(post, post.Meta.ExtraCSS, staticPath, writer)
+	if err != nil {
+		return err
+	}
+	extraJS, err := copyExtraFiles(post, post.Meta.ExtraJS, staticPath, writer)
+	if err != nil {
+		return err
+	}
+	if enclosure := post.Meta.Enclosure; enclosure != nil && enclosure.File != "" {
+		if _, err := copyExtraFiles(post, []string{enclosure.File}, staticPath, writer); err != nil {
 			return err
 		}
 	}
 
-	if err := g.Config.Writer.WriteIndexHTML(staticPath, post.Meta.Title, post.Meta.Short, template.HTML(string(post.HTML)), t); err != nil {
+	td := writer.NewIndexData(staticPath, post.Meta.Title, getPostDescription(post), template.HTML(string(post.HTML)))
+	td.CanonicalLink = post.URL
+	td.Entry = newEntry(post, writer.BlogURL, writer.BlogAuthor)
+	td.Webmentions = getPostWebmentions(post, g.Config.Webmentions)
+	// pages without an image of their own keep the default image of the writer
+	image := getPostImageURL(post, writer.BlogURL)
+	if g.Config.OGImage != nil && post.Meta.Image == "" {
+		if image, err = g.generateOGImage(staticPath); err != nil {
+			return err
+		}
+	}
+	if image != "" {
+		td.OGImage = image
+	}
+	jsonLD, err := buildArticleJSONLD(post, image, writer.BlogAuthor)
+	if err != nil {
+		return err
+	}
+	td.JSONLD = jsonLD
+	td.NoIndex = post.Meta.NoIndex
+	td.MetaTags = post.Meta.MetaTags
+	td.ExtraCSS = extraCSS
+	td.ExtraJS = extraJS
+	td.TagNav = post.TagNav
+	td.Related = post.Related
+	td.TOC = post.TOC
+	td.EditLink = post.EditLink
+	if g.Config.Trailer != nil && !post.Meta.NoTrailer {
+		var b bytes.Buffer
+		if err := g.Config.Trailer.Execute(&b, post); err != nil {
+			return fmt.Errorf("error executing trailer template of %s: %v", post.Name, err)
+		}
+		td.Trailer = template.HTML(b.String())
+	}
+	if td.Comments, err = getPostComments(post, g.Config.Comments); err != nil {
+		return err
+	}
+	td.License = post.License
+	td.Attachments = post.Attachments
+	if g.Config.AMPTemplate != nil {
+		ampLink, err := g.generateAMP(staticPath, jsonLD)
+		if err != nil {
+			return err
+		}
+		td.AMPLink = ampLink
+	}
+	if err := writer.WriteIndexData(staticPath, td, t); err != nil {
 		return err
 	}
+	if g.Config.Export != nil {
+		if err := g.generateExport(td); err != nil {
+			return err
+		}
+	}
+	if g.Config.PDF != nil {
+		if err := g.generatePDF(staticPath); err != nil {
+			return err
+		}
+	}
 	fmt.Printf("\tFinished generating Post: %s...\n", post.Meta.Title)
 	return nil
 }
 
-func newPost(path, dateFormat string) (*Post, error) {
-	filePath := filepath.Join(path, "post.md")
-	file, err := os.Open(filePath)
-	br := bufio.NewReader(file)
-	meta, err := getMeta(br, dateFormat)
+// getPostDescription returns the summary of a post, or its text if it has
+// none
+func getPostDescription(post *Post) string {
+	if post.Summary != "" {
+		return post.Summary
+	}
+	return htmlText(post.HTML)
+}
+
+// generateAMP writes the AMP version of the post and returns its URL, posts
+// which can't be converted are skipped with a warning
+func (g *PostGenerator) generateAMP(staticPath string, jsonLD template.JS) (string, error) {
+	post := g.Config.Post
+	writer := g.Config.Writer
+	content, err := convertToAMP(post)
+	if err != nil {
+		fmt.Printf("\tWarning: skipping AMP version of %s: %v\n", post.Name, err)
+		return "", nil
+	}
+	ampPath := filepath.Join(staticPath, "amp")
+	td := writer.NewIndexData(ampPath, post.Meta.Title, getPostDescription(post), content)
+	td.CanonicalLink = post.URL
+	td.JSONLD = jsonLD
+	if err := writer.WriteIndexData(ampPath, td, g.Config.AMPTemplate); err != nil {
+		return "", err
+	}
+	return post.URL + "amp/", nil
+}
+
+// postLoader reads posts from their source folders
+type postLoader struct {
+	BlogURL       string
+	Root          string
+	DateFormat    string
+	Location      *time.Location
+	Variables     map[string]string
+	Defaults      metaDefaults
+	Includes      *includeResolver
+	License       string
+	Render        RenderOptions
+	ExcerptLength int
+	Ellipsis      string
+	// ExcerptSources are the summary sources in order of preference
+	ExcerptSources []string
+	// SharedImages is the folder of the images shared by all posts
+	SharedImages string
+	// FilesDir is the name of the attachments folder of posts
+	FilesDir string
+	// ImagesDir is the name of the images folder of posts
+	ImagesDir string
+	// SlugHistory records the names of posts, nil if they aren't recorded
+	SlugHistory *slugHistory
+	// Base is the folder below Root the post names are relative to
+	Base string
+	// CJK is how the post names handle CJK characters
+	CJK string
+}
+
+// relPath returns the path of a post folder relative to the root
+func (l *postLoader) relPath(path string) string {
+	if l.Root == "" {
+		return filepath.Base(path)
+	}
+	rel, err := filepath.Rel(l.Root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.Base(path)
+	}
+	return rel
+}
+
+// getPostSource returns the folder and the markdown file of a post, whose
+// source is either a folder with a post.md or a markdown file with the folder
+// of the same name next to it, e.g. my-post.md and my-post/
+func getPostSource(source string) (string, string) {
+	if info, err := os.Stat(source); err == nil && !info.IsDir() {
+		return strings.TrimSuffix(source, filepath.Ext(source)), source
+	}
+	return source, filepath.Join(source, "post.md")
+}
+
+func (l *postLoader) newPost(source string) (*Post, error) {
+	path, filePath := getPostSource(source)
+	raw, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading file %s: %v", filePath, err)
+	}
+	raw = normalizeNewlines(raw)
+	br := bufio.NewReader(bytes.NewReader(raw))
+	meta, err := getMeta(path, br, l.Defaults.valuesFor(l.relPath(path)), l.DateFormat, l.Location)
 	if err != nil {
 		return nil, fmt.Errorf(`error parsing meta in %s:%v`, filePath, err)
 	}
-	html, err := getHTML(br)
+	markdown, _ := ioutil.ReadAll(br)
+	// line of the markdown file the markdown starts at
+	bodyLine := bytes.Count(raw[:len(raw)-len(markdown)], []byte("\n")) + 1
+	imagesDirName, err := getImagesDirName(meta, l.ImagesDir)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("error in %s: %v", filePath, err)
 	}
-	imagesDir, images, err := getImages(path)
+	imagesDir, images, err := getImages(path, imagesDirName)
 	if err != nil {
 		return nil, err
 	}
-	name := filepath.Base(path)
+	if err := checkExtraFiles(path, meta); err != nil {
+		return nil, err
+	}
+	if err := checkImageGlobs(meta.KeepImages); err != nil {
+		return nil, fmt.Errorf("error in keepimages of %s: %v", filePath, err)
+	}
+	if meta.Enclosure != nil {
+		if err := checkEnclosure(path, meta.Enclosure); err != nil {
+			return nil, err
+		}
+	}
+	if meta.DefaultCodeLang != "" && !codeLang.MatchString(meta.DefaultCodeLang) {
+		return nil, fmt.Errorf("error in %s: defaultCodeLang has to be a language name, not %s", filePath, meta.DefaultCodeLang)
+	}
+	if err := checkMetaTags(meta.MetaTags); err != nil {
+		return nil, fmt.Errorf("error in metaTags of %s: %v", filePath, err)
+	}
+	root := l.Root
+	if l.Base != "" {
+		root = filepath.Join(l.Root, l.Base)
+	}
+	unslugged, category := getPostName(path, root, meta.Slug)
+	name := slugifyPostName(unslugged, l.CJK)
+	if meta.Category != "" {
+		category = meta.Category
+	}
+	postPath := fmt.Sprintf("/%s/", name)
+
+	post := &Post{Name: name, Path: postPath, URL: l.BlogURL + postPath, Category: category, Dir: path, File: filePath, Markdown: markdown, BodyLine: bodyLine, Meta: meta, ImagesDir: imagesDir, Images: images}
+	post.License = getPostLicense(meta, l.License, filePath)
+	post.SharedImagesDir = l.SharedImages
+	if post.FilesDir, post.Attachments, err = getAttachments(path, l.FilesDir, postPath); err != nil {
+		return nil, err
+	}
+	if l.SlugHistory != nil {
+		source := filepath.ToSlash(l.relPath(filePath))
+		post.OldSlugs = l.SlugHistory.update(source, getLegacySlugHistoryFile(path, filePath), unslugged, name)
+	} else if unslugged != name {
+		// the unslugified name was the URL of the post before
+		post.OldSlugs = []string{unslugged}
+	}
+	expanded := markdown
+	if l.Includes != nil {
+		if expanded, err = l.Includes.expand(markdown, filePath); err != nil {
+			return nil, err
+		}
+	}
+	if post.HTML, err = getHTML(post, interpolateVariables(expanded, l.Variables), l.Render); err != nil {
+		return nil, err
+	}
+	post.Summary = getSummary(post, l.ExcerptSources)
+	post.Excerpt = truncate(post.Summary, l.ExcerptLength, l.Ellipsis)
+	return post, nil
+}
+
+// getPostName returns the URL path of a post, which is the location of its
+// folder below root unless a slug is set, and the category of the post, which
+// is the path of the folder containing it
+func getPostName(path, root, slug string) (string, string) {
+	rel := filepath.Base(path)
+	if root != "" {
+		if r, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(r, "..") {
+			rel = r
+		}
+	}
+	rel = filepath.ToSlash(rel)
+	category := ""
+	if i := strings.LastIndex(rel, "/"); i >= 0 {
+		category = rel[:i]
+	}
+	if slug != "" {
+		return strings.Trim(slug, "/"), category
+	}
+	return rel, category
+}
 
-	return &Post{Name: name, Meta: meta, HTML: html, ImagesDir: imagesDir, Images: images}, nil
+// slugifyPostName slugifies every folder of a post name, cjk is how CJK
+// characters are handled
+func slugifyPostName(name, cjk string) string {
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		parts[i] = slugify(part, cjk)
+	}
+	return strings.Join(parts, "/")
 }
 
-func copyImagesDir(source, destination string) (err error) {
-	path := filepath.Join(destination, "images")
-	if err := os.Mkdir(path, os.ModePerm); err != nil {
+// checkExtraFiles makes sure the extra css and js files of a post exist
+func checkExtraFiles(path string, meta *Meta) error {
+	files := append(append([]string{}, meta.ExtraCSS...), meta.ExtraJS...)
+	for _, file := range files {
+		clean := filepath.Clean(filepath.FromSlash(file))
+		if filepath.IsAbs(clean) || strings.HasPrefix(clean, "..") {
+			return fmt.Errorf("extra file %s has to be inside of the post folder %s", file, path)
+		}
+		filePath := filepath.Join(path, clean)
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return fmt.Errorf("error reading extra file %s: %v", filePath, err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("extra file %s is a directory", filePath)
+		}
+	}
+	return nil
+}
+
+// copyExtraFiles copies extra files of the post and returns their URLs
+func copyExtraFiles(post *Post, files []string, destination string, writer *IndexWriter) ([]string, error) {
+	var urls []string
+	for _, file := range files {
+		clean := filepath.Clean(filepath.FromSlash(file))
+		dst := filepath.Join(destination, clean)
+		if err := createFolderIfNotExist(filepath.Dir(dst), writer.DirMode); err != nil {
+			return nil, err
+		}
+		if err := copyFile(filepath.Join(post.Dir, clean), dst, writer.FileMode); err != nil {
+			return nil, err
+		}
+		urls = append(urls, post.Path+filepath.ToSlash(clean))
+	}
+	return urls, nil
+}
+
+func copyImagesDir(source, destination string, dirMode os.FileMode, images *ImageProcessor, keep []string) (err error) {
+	path := filepath.Join(destination, filepath.Base(source))
+	if err := os.Mkdir(path, dirMode); err != nil {
 		return fmt.Errorf("error creating images directory at %s: %v", path, err)
 	}
 	files, err := ioutil.ReadDir(source)
@@ -99,89 +417,250 @@ func copyImagesDir(source, destination string) (err error) {
 	for _, file := range files {
 		src := filepath.Join(source, file.Name())
 		dst := filepath.Join(path, file.Name())
-		if err := copyFile(src, dst); err != nil {
+		if matchesImageGlob(keep, src) {
+			err = images.Copy(src, dst)
+		} else {
+			err = images.Process(src, dst)
+		}
+		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-// Unmarshal the file's header.
-func getMeta(br *bufio.Reader, dateFormat string) (*Meta, error) {
+// getMeta reads the post's meta data. If a meta.yml exists next to post.md it
+// is used and post.md may omit its header, otherwise the "---" delimited
+// header at the top of post.md is required.
+func getMeta(path string, br *bufio.Reader, defaults map[string]interface{}, dateFormat string, location *time.Location) (*Meta, error) {
+	raw, err := readRawMeta(path, br)
+	if err != nil {
+		return nil, err
+	}
+	if raw, err = applyDefaults(raw, defaults); err != nil {
+		return nil, err
+	}
+	return parseMeta(raw, dateFormat, location)
+}
+
+// readRawMeta returns the yml of the post's meta data
+func readRawMeta(path string, br *bufio.Reader) ([]byte, error) {
+	external, err := readMetaFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var inline []byte
+	switch {
+	case external == nil:
+		inline, err = readHeader(br)
+		if err != nil {
+			return nil, err
+		}
+	case hasHeader(br):
+		inline, err = readOptionalHeader(br)
+		if err != nil {
+			return nil, err
+		}
+	}
+	raw := inline
+	if external != nil {
+		raw, err = mergeMeta(external, inline)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return raw, nil
+}
+
+func readMetaFile(path string) ([]byte, error) {
+	filePath := filepath.Join(path, "meta.yml")
+	metaraw, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error while reading file %s: %v", filePath, err)
+	}
+	return normalizeNewlines(metaraw), nil
+}
+
+// normalizeNewlines converts CRLF and CR line endings to LF
+func normalizeNewlines(data []byte) []byte {
+	data = bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1)
+	return bytes.Replace(data, []byte("\r"), []byte("\n"), -1)
+}
+
+func hasHeader(br *bufio.Reader) bool {
+	start, _ := br.Peek(3)
+	return string(start) == "---"
+}
+
+// readOptionalHeader reads the header of a post with a meta.yml, "---" lines
+// are thematic breaks in markdown, so the block is only a header if it is a
+// yml mapping. Otherwise nothing is read from br.
+func readOptionalHeader(br *bufio.Reader) ([]byte, error) {
+	rest, err := ioutil.ReadAll(br)
+	if err != nil {
+		return nil, fmt.Errorf("error reading post: %v", err)
+	}
+	header := bufio.NewReader(bytes.NewReader(rest))
+	inline, err := readHeader(header)
+	if err == nil && yaml.Unmarshal(inline, &yaml.MapSlice{}) == nil {
+		br.Reset(header)
+		return inline, nil
+	}
+	br.Reset(bytes.NewReader(rest))
+	return nil, nil
+}
+
+// headerHint shows the expected layout of a post's header
+const headerHint = `expected post.md to start with a header like:
+---
+title: My Post
+short: A short description
+date: 01.02.2006
+tags: [go]
+---`
+
+// Read the file's header.
+func readHeader(br *bufio.Reader) ([]byte, error) {
 	// read first line
 	line, err := br.ReadString('\n')
-	if err != nil {
+	if err != nil && err != io.EOF {
 		return nil, fmt.Errorf("error ReadString: %v", err)
 	}
 	// start with ---
 	if !strings.HasPrefix(line, "---") {
-		err = errors.New(`Can not find "---" `)
-		return nil, fmt.Errorf(`error in can not find "---"`)
+		return nil, fmt.Errorf("no header found, the meta data has to be placed between two \"---\" lines (or in a meta.yml), %s", headerHint)
 	}
 	buf := bytes.NewBuffer(nil)
 	// read header
 	for {
 		line, err = br.ReadString('\n')
-		if err != nil {
-			if err != io.EOF {
-				return nil, fmt.Errorf("error ReadString: %v", err)
-			}
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("error ReadString: %v", err)
 		}
 		// end of header
 		if strings.HasPrefix(line, "---") {
 			break
 		}
+		if err == io.EOF {
+			return nil, fmt.Errorf("header is not terminated, the closing \"---\" line is missing, %s", headerHint)
+		}
 		buf.WriteString(line)
 	}
-	h, err := ioutil.ReadAll(buf)
-	if err != nil || len(h) < 3 {
-		return nil, fmt.Errorf("error ReadAll: %v", err)
+	h := buf.Bytes()
+	if len(bytes.TrimSpace(h)) == 0 {
+		return nil, fmt.Errorf("header is empty, %s", headerHint)
+	}
+	return h, nil
+}
+
+// mergeMeta combines meta.yml with the header of post.md, a field may be set
+// in both places only if the values are the same.
+func mergeMeta(external, inline []byte) ([]byte, error) {
+	merged := map[string]interface{}{}
+	if err := yaml.Unmarshal(external, &merged); err != nil {
+		return nil, fmt.Errorf("error reading meta.yml: %v", err)
+	}
+	header := map[string]interface{}{}
+	if err := yaml.Unmarshal(inline, &header); err != nil {
+		return nil, fmt.Errorf("error reading yml: %v", err)
+	}
+	keys := []string{}
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if v, ok := merged[key]; ok && !reflect.DeepEqual(v, header[key]) {
+			return nil, fmt.Errorf("%q is set to different values in meta.yml (%v) and post.md (%v)", key, v, header[key])
+		}
+		merged[key] = header[key]
 	}
-	//
+	return yaml.Marshal(merged)
+}
+
+// parseMeta unmarshals the file's header, dates without an offset are in
+// location
+func parseMeta(h []byte, dateFormat string, location *time.Location) (*Meta, error) {
 	meta := Meta{}
-	err = yaml.Unmarshal(h, &meta)
+	err := yaml.Unmarshal(h, &meta)
 	if err != nil {
 		return nil, fmt.Errorf("error reading yml: %v", err)
 	}
-	parsedDate, err := time.Parse(dateFormat, meta.Date)
+	parsedDate, err := time.ParseInLocation(dateFormat, meta.Date, location)
 	if err != nil {
 		//return nil, fmt.Errorf("error format date %s: %v", meta.Date, err)
 	}
 	meta.ParsedDate = parsedDate
+	if err := checkStatus(meta.Status); err != nil {
+		return nil, err
+	}
+	if meta.Updated != "" {
+		parsedUpdated, err := time.ParseInLocation(dateFormat, meta.Updated, location)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing updated date %s: %v", meta.Updated, err)
+		}
+		meta.ParsedUpdated = parsedUpdated
+	}
 	return &meta, nil
 }
 
-//func getMeta(path, dateFormat string) (*Meta, error) {
-//	filePath := filepath.Join(path, "meta.yml")
-//	metaraw, err := ioutil.ReadFile(filePath)
-//	if err != nil {
-//		return nil, fmt.Errorf("error while reading file %s: %v", filePath, err)
-//	}
-//	meta := Meta{}
-//	err = yaml.Unmarshal(metaraw, &meta)
-//	if err != nil {
-//		return nil, fmt.Errorf("error reading yml in %s: %v", filePath, err)
-//	}
-//	parsedDate, err := time.Parse(dateFormat, meta.Date)
-//	if err != nil {
-//		return nil, fmt.Errorf("error parsing date in %s: %v", filePath, err)
-//	}
-//	meta.ParsedDate = parsedDate
-//	return &meta, nil
-//}
-
-func getHTML(br *bufio.Reader) ([]byte, error) {
-	input, _ := ioutil.ReadAll(br)
-	html := blackfriday.MarkdownCommon(input)
-	replaced, err := replaceCodeParts(html)
-	if err != nil {
-		return nil, fmt.Errorf("error during syntax highlighting : %v", err)
-	}
-	return []byte(replaced), nil
-}
-
-func getImages(path string) (string, []string, error) {
-	dirPath := filepath.Join(path, "images")
+// getHTML renders the markdown of a post and applies the transform chain to
+// the parsed document
+func getHTML(post *Post, input []byte, opts RenderOptions) ([]byte, error) {
+	defer opts.Timings.since(phaseRendering, time.Now())
+	html := blackfriday.Markdown(input, blackfriday.HtmlRenderer(opts.HTMLFlags, "", ""), opts.Extensions)
+	// parsing is the most expensive part of plain prose posts, it is skipped
+	// when no transform would change the html
+	transforms := getTransforms(html, opts)
+	if len(transforms) == 0 {
+		return html, nil
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing html: %v", err)
+	}
+	if err := applyTransforms(post, doc, transforms); err != nil {
+		return nil, err
+	}
+	result, err := doc.Find("body").Html()
+	if err != nil {
+		return nil, fmt.Errorf("error while generating html: %v", err)
+	}
+	return []byte(result), nil
+}
+
+// defaultImagesDir is the name of the images folder of posts if none is
+// configured
+const defaultImagesDir = "images"
+
+// getImagesDirName returns the images folder name of a post, set in its meta
+// data or for all posts
+func getImagesDirName(meta *Meta, dir string) (string, error) {
+	if meta.ImagesDir != "" {
+		dir = meta.ImagesDir
+	}
+	if dir == "" {
+		return defaultImagesDir, nil
+	}
+	if dir != filepath.Base(dir) || dir == "." || dir == ".." {
+		return "", fmt.Errorf("imagesdir has to be the name of a folder of the post, not %s", dir)
+	}
+	return dir, nil
+}
+
+// getImagesPath returns the path of the images of a post relative to the post
+func getImagesPath(post *Post) string {
+	if post.ImagesDir == "" {
+		return defaultImagesDir + "/"
+	}
+	return filepath.Base(post.ImagesDir) + "/"
+}
+
+func getImages(path, dir string) (string, []string, error) {
+	dirPath := filepath.Join(path, dir)
 	files, err := ioutil.ReadDir(dirPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -196,28 +675,6 @@ func getImages(path string) (string, []string, error) {
 	return dirPath, images, nil
 }
 
-func replaceCodeParts(htmlFile []byte) (string, error) {
-	byteReader := bytes.NewReader(htmlFile)
-	doc, err := goquery.NewDocumentFromReader(byteReader)
-	if err != nil {
-		return "", fmt.Errorf("error while parsing html: %v", err)
-	}
-	// find code-parts via css selector and replace them with highlighted versions
-	doc.Find("code[class*=\"language-\"]").Each(func(i int, s *goquery.Selection) {
-		oldCode := s.Text()
-		formatted, _ := syntaxhighlight.AsHTML([]byte(oldCode))
-		s.SetHtml(string(formatted))
-	})
-	new, err := doc.Html()
-	if err != nil {
-		return "", fmt.Errorf("error while generating html: %v", err)
-	}
-	// replace unnecessarily added html tags
-	new = strings.Replace(new, "<html><head></head><body>", "", 1)
-	new = strings.Replace(new, "</body></html>", "", 1)
-	return new, nil
-}
-
 func (p ByDateDesc) Len() int {
 	return len(p)
 }
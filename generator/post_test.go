@@ -0,0 +1,333 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/eleztian/blog-generator/config"
+	"github.com/russross/blackfriday"
+)
+
+const (
+	benchProse = "Some *prose* with **emphasis**, a [link](/other/) and \"quotes\" -- and dashes.\n\n" +
+		"> A quote\n\n- a list\n- of items\n\nA line with & and < characters.  \nA hard break.\n"
+	benchCode = "## Heading\n\n```go\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n```\n"
+)
+
+// getTransformedHTML renders markdown like getHTML, but always applies the
+// transform chain
+func getTransformedHTML(t testing.TB, post *Post, input []byte, opts RenderOptions) []byte {
+	html := blackfriday.Markdown(input, blackfriday.HtmlRenderer(opts.HTMLFlags, "", ""), opts.Extensions)
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	transforms := []Transform{}
+	for _, t := range getBuiltinTransforms(opts) {
+		transforms = append(transforms, t.transform)
+	}
+	if err := applyTransforms(post, doc, append(transforms, opts.Transforms...)); err != nil {
+		t.Fatal(err)
+	}
+	result, err := doc.Find("body").Html()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []byte(result)
+}
+
+// serializeHTML parses and serializes html like the transform chain does,
+// which writes entities like &ldquo; as characters and <br /> as <br/>
+func serializeHTML(t testing.TB, html []byte) string {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := doc.Find("body").Html()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// the skipped transforms leave the html as blackfriday serialized it, the
+// documents are the same as with the transforms applied
+func TestGetHTMLSkippedTransformsKeepOutput(t *testing.T) {
+	opts, err := newRenderOptions(newTestConfig(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prose := []byte(strings.Repeat(benchProse, 3))
+	if needsTransforms(blackfriday.Markdown(prose, blackfriday.HtmlRenderer(opts.HTMLFlags, "", ""), opts.Extensions), opts) {
+		t.Fatal("prose needs the transforms, the fast path isn't tested")
+	}
+	for _, markdown := range [][]byte{prose, []byte(benchCode)} {
+		fast, err := getHTML(&Post{Meta: &Meta{}}, markdown, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		full := getTransformedHTML(t, &Post{Meta: &Meta{}}, markdown, opts)
+		if serializeHTML(t, fast) != string(full) {
+			t.Errorf("output differs with the transforms applied:\n%s\n%s", fast, full)
+		}
+	}
+}
+
+func BenchmarkGetHTML(b *testing.B) {
+	opts, err := newRenderOptions(newTestConfig(b, nil))
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, bench := range []struct {
+		name     string
+		markdown []byte
+	}{
+		{"prose", []byte(strings.Repeat(benchProse, 20))},
+		{"code", []byte(strings.Repeat(benchProse+benchCode, 10))},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			post := &Post{Meta: &Meta{}}
+			for i := 0; i < b.N; i++ {
+				if _, err := getHTML(post, bench.markdown, opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// readTestMeta reads the meta data of a post folder with the given post.md
+// and meta.yml, an empty meta.yml isn't written
+func readTestMeta(t *testing.T, post, metaYML string) (*Meta, string, error) {
+	dir := t.TempDir()
+	files := map[string]string{"post.md": post}
+	if metaYML != "" {
+		files["meta.yml"] = metaYML
+	}
+	writeTestFiles(t, dir, files)
+	br := bufio.NewReader(strings.NewReader(post))
+	meta, err := getMeta(dir, br, nil, "02.01.2006", time.UTC)
+	if err != nil {
+		return nil, "", err
+	}
+	rest, _ := br.ReadString(0)
+	return meta, rest, nil
+}
+
+func TestGetMetaLayouts(t *testing.T) {
+	t.Run("meta.yml", func(t *testing.T) {
+		body := "Just the body.\n\n---\n\nAfter a rule."
+		meta, rest, err := readTestMeta(t, body, "title: External\ndate: 01.02.2021\ntags: [go]\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if meta.Title != "External" || meta.Tags[0] != "go" || meta.ParsedDate.Month() != time.February {
+			t.Errorf("meta of meta.yml wasn't read: %+v", meta)
+		}
+		if rest != body {
+			t.Errorf("body is %q, want all of post.md", rest)
+		}
+	})
+	t.Run("meta.yml and leading rule", func(t *testing.T) {
+		body := "---\n\nAfter a rule.\n\n---\n\nAfter another rule."
+		meta, rest, err := readTestMeta(t, body, "title: External\ndate: 01.02.2021\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if meta.Title != "External" {
+			t.Errorf("meta of meta.yml wasn't read: %+v", meta)
+		}
+		if rest != body {
+			t.Errorf("body is %q, want all of post.md", rest)
+		}
+	})
+	t.Run("inline", func(t *testing.T) {
+		meta, rest, err := readTestMeta(t, testPost("Inline", "01.02.2021", "The body.", "short: inline"), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if meta.Title != "Inline" || meta.Short != "inline" {
+			t.Errorf("meta of the header wasn't read: %+v", meta)
+		}
+		if rest != "The body." {
+			t.Errorf("body is %q, want the text after the header", rest)
+		}
+	})
+	t.Run("merged", func(t *testing.T) {
+		meta, _, err := readTestMeta(t, testPost("Same", "01.02.2021", "The body.", "short: inline"), "title: Same\ntags: [go]\n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if meta.Title != "Same" || meta.Short != "inline" || len(meta.Tags) != 1 {
+			t.Errorf("meta wasn't merged: %+v", meta)
+		}
+	})
+	t.Run("conflict", func(t *testing.T) {
+		_, _, err := readTestMeta(t, testPost("Inline", "01.02.2021", "The body."), "title: External\n")
+		if err == nil {
+			t.Fatal("conflicting titles were accepted")
+		}
+		for _, part := range []string{`"title"`, "External", "Inline"} {
+			if !strings.Contains(err.Error(), part) {
+				t.Errorf("error %q doesn't name %s", err, part)
+			}
+		}
+	})
+}
+
+// newTestLoader returns a loader of the posts below root with the rendering
+// of cfg
+func newTestLoader(t *testing.T, root string, cfg *config.Config) *postLoader {
+	render, err := newRenderOptions(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &postLoader{BlogURL: cfg.Blog.URL, Root: root, DateFormat: "02.01.2006", Location: time.UTC, Render: render}
+}
+
+func TestCRLFPostMatchesLF(t *testing.T) {
+	lf := testPost("Windows", "01.02.2021", "First line\nsecond line\n\n    code\n", "short: crlf", "tags:\n  - go\n  - web")
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{
+		"lf/post.md":   lf,
+		"crlf/post.md": strings.Replace(lf, "\n", "\r\n", -1),
+	})
+	loader := newTestLoader(t, root, newTestConfig(t, nil))
+	want, err := loader.newPost(filepath.Join(root, "lf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := loader.newPost(filepath.Join(root, "crlf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Meta, want.Meta) {
+		t.Errorf("meta of the CRLF post is %+v, want %+v", got.Meta, want.Meta)
+	}
+	if !bytes.Equal(got.Markdown, want.Markdown) || !bytes.Equal(got.HTML, want.HTML) {
+		t.Errorf("body of the CRLF post is %q, want %q", got.Markdown, want.Markdown)
+	}
+}
+
+func TestReadHeaderErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"missing fence", "title: No Fence\n\nThe body.", "no header found"},
+		{"unterminated fence", "---\ntitle: Open\ndate: 01.02.2021\nThe body.", "header is not terminated"},
+		{"empty header", "---\n\n---\nThe body.", "header is empty"},
+	}
+	for _, test := range tests {
+		_, err := readHeader(bufio.NewReader(strings.NewReader(test.source)))
+		if err == nil {
+			t.Errorf("%s: no error", test.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.want) || !strings.Contains(err.Error(), headerHint) {
+			t.Errorf("%s: error %q doesn't explain the problem with the hint", test.name, err)
+		}
+	}
+}
+
+func TestMissingHeaderNamesFile(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{"broken/post.md": "Just a body."})
+	_, err := newTestLoader(t, root, newTestConfig(t, nil)).newPost(filepath.Join(root, "broken"))
+	if err == nil || !strings.Contains(err.Error(), filepath.Join(root, "broken", "post.md")) {
+		t.Errorf("error %v doesn't name the post file", err)
+	}
+}
+
+func TestGetPostName(t *testing.T) {
+	root := filepath.Join("src", "blog")
+	tests := []struct {
+		path     string
+		slug     string
+		name     string
+		category string
+	}{
+		{filepath.Join(root, "hello"), "", "hello", ""},
+		{filepath.Join(root, "go", "intro"), "", "go/intro", "go"},
+		{filepath.Join(root, "go", "web", "routing"), "", "go/web/routing", "go/web"},
+		{filepath.Join(root, "go", "web", "routing"), "/routes/", "routes", "go/web"},
+	}
+	for _, test := range tests {
+		name, category := getPostName(test.path, root, test.slug)
+		if name != test.name || category != test.category {
+			t.Errorf("getPostName(%s, %q) = %s, %s, want %s, %s", test.path, test.slug, name, category, test.name, test.category)
+		}
+	}
+}
+
+func TestNestedPosts(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"go/intro/post.md":       testPost("Intro", "01.02.2021", "Intro."),
+		"go/web/routing/post.md": testPost("Routing", "02.02.2021", "Routing."),
+		"go/web/slugged/post.md": testPost("Slugged", "03.02.2021", "Slugged.", "slug: short"),
+	}).build()
+	for name, text := range map[string]string{"go/intro": "Intro.", "go/web/routing": "Routing.", "short": "Slugged."} {
+		if !strings.Contains(site.read(name+"/index.html"), text) {
+			t.Errorf("post isn't generated at %s", name)
+		}
+	}
+	loader := newTestLoader(t, site.Root, site.Config)
+	post, err := loader.newPost(filepath.Join(site.Root, "go", "web", "routing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if post.Category != "go/web" || post.Path != "/go/web/routing/" {
+		t.Errorf("nested post has category %q and path %q", post.Category, post.Path)
+	}
+}
+
+func TestSlugifiedPostNames(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.Slugs.CJK = "romanize"
+	}), map[string]string{
+		"Ünïcode 日本/post.md": testPost("Accented", "01.02.2021", "Accented."),
+		"ひらがな/post.md":       testPost("Kana", "02.02.2021", "Kana."),
+		"🎉🚀/post.md":         testPost("Emoji", "03.02.2021", "Emoji."),
+	}).build()
+	tests := []struct {
+		folder, name, text string
+	}{
+		{"Ünïcode 日本", "unicode-日本", "Accented."},
+		{"ひらがな", "hiragana", "Kana."},
+		{"🎉🚀", "146ac10a", "Emoji."},
+	}
+	for _, test := range tests {
+		if !strings.Contains(site.read(test.name+"/index.html"), test.text) {
+			t.Errorf("post %s isn't generated at %s", test.folder, test.name)
+		}
+		if old := site.read(test.folder + "/index.html"); !strings.Contains(old, "https://example.com/"+test.name+"/") {
+			t.Errorf("old URL of %s doesn't redirect to %s:\n%s", test.folder, test.name, old)
+		}
+	}
+}
+
+func TestGlobPosts(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Generator.Glob = "content/**/*.md"
+	}), map[string]string{
+		"content/notes.md":      testPost("Notes", "01.02.2021", "The notes."),
+		"content/2021/hello.md": testPost("Hello", "02.02.2021", "Hello there."),
+	})
+	sources := []string{filepath.Join(site.Root, "content", "notes.md"), filepath.Join(site.Root, "content", "2021", "hello.md")}
+	err := New(&SiteConfig{Sources: sources, SourceRoot: site.Root, Destination: site.Dest, Config: site.Config}).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, text := range map[string]string{"notes": "The notes.", "2021/hello": "Hello there."} {
+		if !strings.Contains(site.read(name+"/index.html"), text) {
+			t.Errorf("glob post isn't generated at %s", name)
+		}
+	}
+}
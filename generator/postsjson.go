@@ -0,0 +1,144 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// contents of the posts in posts.json
+const (
+	jsonContentHTML     = "html"
+	jsonContentMarkdown = "markdown"
+	jsonContentBoth     = "both"
+)
+
+// PostsJSONGenerator object
+type PostsJSONGenerator struct {
+	Config *PostsJSONConfig
+}
+
+// PostsJSONConfig holds the configuration of posts.json, Content is html,
+// markdown or both
+type PostsJSONConfig struct {
+	Posts       []*Post
+	BlogURL     string
+	Content     string
+	Destination string
+	FileMode    os.FileMode
+}
+
+// postJSON is a post in posts.json, dates are RFC 3339 and URLs absolute
+type postJSON struct {
+	Title       string   `json:"title"`
+	Slug        string   `json:"slug"`
+	URL         string   `json:"url"`
+	Date        string   `json:"date"`
+	Updated     string   `json:"updated,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Tags        []string `json:"tags"`
+	Category    string   `json:"category,omitempty"`
+	Image       string   `json:"image,omitempty"`
+	ReadingTime int      `json:"readingTime,omitempty"`
+	Words       int      `json:"words,omitempty"`
+	HTML        string   `json:"html,omitempty"`
+	Markdown    string   `json:"markdown,omitempty"`
+}
+
+// Generate writes posts.json with the metadata and content of the posts,
+// newest first
+func (g *PostsJSONGenerator) Generate() error {
+	fmt.Println("\tGenerating posts.json...")
+	posts := []postJSON{}
+	for _, post := range getJSONPosts(g.Config.Posts) {
+		p, err := g.newPostJSON(post)
+		if err != nil {
+			return err
+		}
+		posts = append(posts, p)
+	}
+	data, err := json.MarshalIndent(posts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error creating posts.json: %v", err)
+	}
+	if err := writeTextFile(filepath.Join(g.Config.Destination, "posts.json"), data, g.Config.FileMode); err != nil {
+		return err
+	}
+	fmt.Println("\tFinished generating posts.json...")
+	return nil
+}
+
+// getJSONPosts returns the posts of posts.json, drafts are left out even if
+// they are generated
+func getJSONPosts(posts []*Post) []*Post {
+	result := []*Post{}
+	for _, post := range getListedPosts(posts) {
+		if post.Meta.Status != statusDraft {
+			result = append(result, post)
+		}
+	}
+	return result
+}
+
+func (g *PostsJSONGenerator) newPostJSON(post *Post) (postJSON, error) {
+	meta := post.Meta
+	tags := meta.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	p := postJSON{
+		Title:       meta.Title,
+		Slug:        post.Name,
+		URL:         post.URL,
+		Date:        formatJSONLDDate(meta.ParsedDate),
+		Updated:     formatJSONLDDate(meta.ParsedUpdated),
+		Summary:     post.Summary,
+		Tags:        tags,
+		Category:    post.Category,
+		Image:       getPostImageURL(post, g.Config.BlogURL),
+		ReadingTime: post.ReadingTime.Minutes,
+		Words:       post.ReadingTime.Words,
+	}
+	if g.Config.Content != jsonContentMarkdown {
+		html, err := makeLinksAbsolute(post.HTML, post.URL)
+		if err != nil {
+			return p, fmt.Errorf("error creating posts.json entry of %s: %v", post.Name, err)
+		}
+		p.HTML = html
+	}
+	if g.Config.Content != jsonContentHTML {
+		p.Markdown = string(post.Markdown)
+	}
+	return p, nil
+}
+
+// makeLinksAbsolute returns html with the relative links and sources
+// resolved against base, links to fragments are left as they are
+func makeLinksAbsolute(html []byte, base string) (string, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		return "", fmt.Errorf("error while parsing html: %v", err)
+	}
+	for _, attr := range []string{"href", "src"} {
+		doc.Find("[" + attr + "]").Each(func(i int, s *goquery.Selection) {
+			link := s.AttrOr(attr, "")
+			if link == "" || strings.HasPrefix(link, "#") {
+				return
+			}
+			if u, err := url.Parse(link); err == nil && !u.IsAbs() {
+				s.SetAttr(attr, baseURL.ResolveReference(u).String())
+			}
+		})
+	}
+	return doc.Find("body").Html()
+}
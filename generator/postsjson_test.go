@@ -0,0 +1,39 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestPostsJSON(t *testing.T) {
+	for _, dev := range []bool{false, true} {
+		site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+			cfg.Blog.JSON.Enabled = true
+			cfg.Generator.Dev = dev
+		}), map[string]string{
+			"first/post.md": testPost("First Post", "01.02.2021", "See [the image](images/a.png).", "short: The first.", "tags: [go]"),
+			"draft/post.md": testPost("Draft Post", "02.02.2021", "The draft.", "status: draft"),
+		}).build()
+		var posts []postJSON
+		if err := json.Unmarshal([]byte(site.read("posts.json")), &posts); err != nil {
+			t.Fatal(err)
+		}
+		if len(posts) != 1 {
+			t.Fatalf("dev %v: posts.json has %d posts, want only the published one: %+v", dev, len(posts), posts)
+		}
+		post := posts[0]
+		if post.Title != "First Post" || post.Slug != "first" || post.URL != "https://example.com/first/" ||
+			post.Date != "2021-02-01T00:00:00Z" || post.Summary != "The first." || len(post.Tags) != 1 || post.Tags[0] != "go" {
+			t.Errorf("dev %v: got post %+v", dev, post)
+		}
+		if want := `<p>See <a href="https://example.com/first/images/a.png">the image</a>.</p>`; !strings.Contains(post.HTML, want) {
+			t.Errorf("dev %v: html %q doesn't contain %q", dev, post.HTML, want)
+		}
+		if post.Markdown != "" {
+			t.Errorf("dev %v: markdown was added with html content", dev)
+		}
+	}
+}
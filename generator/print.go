@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// printCSS hides the navigation of the pages and prints the URLs of links
+const printCSS = `header, footer, nav, .tag-nav, a.anchor {
+    display: none;
+}
+body {
+    color: #000;
+    background: #fff;
+}
+.post-content a[href^="http"]::after {
+    content: " (" attr(href) ")";
+    font-size: 90%;
+    word-break: break-all;
+}
+pre, blockquote, figure, img {
+    page-break-inside: avoid;
+}
+h1, h2, h3, h4, h5, h6 {
+    page-break-after: avoid;
+}
+`
+
+// PrintCSSGenerator object
+type PrintCSSGenerator struct {
+	Config *PrintCSSConfig
+}
+
+// PrintCSSConfig holds the configuration for the print stylesheet
+type PrintCSSConfig struct {
+	Destination string
+	DirMode     os.FileMode
+	FileMode    os.FileMode
+}
+
+// Generate writes the print stylesheet to css/print.css
+func (g *PrintCSSGenerator) Generate() error {
+	fmt.Println("\tGenerating Print CSS...")
+	dirPath := filepath.Join(g.Config.Destination, "css")
+	if err := createFolderIfNotExist(dirPath, g.Config.DirMode); err != nil {
+		return err
+	}
+	if err := writeTextFile(filepath.Join(dirPath, "print.css"), []byte(printCSS), g.Config.FileMode); err != nil {
+		return err
+	}
+	fmt.Println("\tFinished generating Print CSS...")
+	return nil
+}
+
+// PDFConfig holds the HTML-to-PDF command rendering posts, {input} and
+// {output} in its arguments are replaced by the html and the pdf file
+type PDFConfig struct {
+	Command []string
+}
+
+// newPDFConfig returns the PDF configuration of the command, nil with a
+// warning if the command is not configured or can't be found
+func newPDFConfig(command []string) *PDFConfig {
+	if len(command) == 0 {
+		fmt.Println("\tWarning: skipping PDFs, no pdf command configured")
+		return nil
+	}
+	if _, err := exec.LookPath(command[0]); err != nil {
+		fmt.Printf("\tWarning: skipping PDFs, pdf command %s not found: %v\n", command[0], err)
+		return nil
+	}
+	return &PDFConfig{Command: command}
+}
+
+// generatePDF renders the written page of the post to <slug>.pdf next to it
+func (g *PostGenerator) generatePDF(staticPath string) error {
+	post := g.Config.Post
+	input, err := filepath.Abs(filepath.Join(staticPath, "index.html"))
+	if err != nil {
+		return err
+	}
+	output, err := filepath.Abs(filepath.Join(staticPath, path.Base(post.Name)+".pdf"))
+	if err != nil {
+		return err
+	}
+	command := g.Config.PDF.Command
+	args := make([]string, 0, len(command)-1)
+	for _, arg := range command[1:] {
+		arg = strings.Replace(arg, "{input}", input, -1)
+		args = append(args, strings.Replace(arg, "{output}", output, -1))
+	}
+	if out, err := exec.Command(command[0], args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("error rendering pdf of %s: %v: %s", post.Name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
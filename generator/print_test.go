@@ -0,0 +1,41 @@
+package generator
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestPrintCSS(t *testing.T) {
+	link := `<link rel="stylesheet" href="/css/print.css" media="print">`
+	for _, enabled := range []bool{true, false} {
+		site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+			cfg.Blog.Print.Enabled = enabled
+		}), map[string]string{
+			"first/post.md": testPost("First Post", "01.02.2021", "The first post."),
+		}).build()
+		if got := strings.Contains(site.read("first/index.html"), link); got != enabled {
+			t.Errorf("print enabled %v: post links print.css %v", enabled, got)
+		}
+		if got := site.exists("css/print.css"); got != enabled {
+			t.Errorf("print enabled %v: print.css written %v", enabled, got)
+		}
+	}
+}
+
+func TestPrintPDF(t *testing.T) {
+	if _, err := exec.LookPath("cp"); err != nil {
+		t.Skip("cp not found")
+	}
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.Print.PDF = true
+		cfg.Blog.Print.Command = []string{"cp", "{input}", "{output}"}
+	}), map[string]string{
+		"first/post.md": testPost("First Post", "01.02.2021", "The first post."),
+	}).build()
+	if pdf := site.read("first/first.pdf"); pdf != site.read("first/index.html") {
+		t.Error("pdf command wasn't given the page of the post")
+	}
+}
@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// proseHidden are the elements of a post which aren't read as prose, code is
+// left out unless it counts
+const proseHidden = "script, style, nav, a.anchor"
+
+// proseBlocks are the elements which separate words, e.g. a heading and the
+// paragraph following it
+const proseBlocks = "p, li, dt, dd, h1, h2, h3, h4, h5, h6, blockquote, pre, td, th, figcaption, div, br"
+
+// proseText returns the visible prose of html with collapsed whitespace, the
+// reading time and the word count of posts both count its words
+func proseText(html []byte, code bool) string {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(html))
+	if err != nil {
+		return ""
+	}
+	doc.Find(proseHidden).Remove()
+	if !code {
+		doc.Find("pre, code").Remove()
+	}
+	doc.Find(proseBlocks).AppendHtml(" ")
+	return strings.Join(strings.Fields(doc.Text()), " ")
+}
+
+// countWords returns the number of words of text
+func countWords(text string) int {
+	return len(strings.Fields(text))
+}
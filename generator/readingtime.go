@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+// ReadingTimeOptions configure the reading time of posts. Rounding is ceil,
+// round (the default) or floor. Posts read in less than Threshold minutes
+// show Below instead of their minutes, "none" shows nothing. Code blocks
+// only count with Code.
+type ReadingTimeOptions struct {
+	Rounding  string
+	Threshold float64
+	Below     string
+	Code      bool
+}
+
+// ReadingTime is the reading time of a post, Label is shown in listings.
+// Words is the word count of the post the reading time is based on.
+type ReadingTime struct {
+	Minutes int
+	Label   string
+	Words   int
+}
+
+// setReadingTimes calculates the word count and reading time of all posts
+func setReadingTimes(posts []*Post, opts ReadingTimeOptions) {
+	for _, post := range posts {
+		words := countWords(proseText(post.HTML, opts.Code))
+		images := bytes.Count(post.HTML, []byte("<img"))
+		post.ReadingTime = getReadingTime(calculateTimeToRead(words, images), opts)
+		post.ReadingTime.Words = words
+	}
+}
+
+// getReadingTime returns the reading time of a post read in minutes
+func getReadingTime(minutes float64, opts ReadingTimeOptions) ReadingTime {
+	if opts.Threshold > 0 && minutes < opts.Threshold {
+		if opts.Below == "none" {
+			return ReadingTime{}
+		}
+		return ReadingTime{Label: opts.Below}
+	}
+	var rounded float64
+	switch opts.Rounding {
+	case "ceil":
+		rounded = math.Ceil(minutes)
+	case "floor":
+		rounded = math.Floor(minutes)
+	default:
+		rounded = math.Round(minutes)
+	}
+	if rounded < 1 {
+		rounded = 1
+	}
+	return ReadingTime{Minutes: int(rounded), Label: fmt.Sprintf("%dm", int(rounded))}
+}
+
+// calculateTimeToRead returns the minutes it takes to read words and look at
+// images
+func calculateTimeToRead(words, images int) float64 {
+	// an average human reads about 200 wpm
+	var secondsPerWord = 60.0 / 200.0
+	// add 12 seconds for each image
+	return (secondsPerWord*float64(words) + 12.0*float64(images)) / 60.0
+}
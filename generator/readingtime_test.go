@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestGetReadingTime(t *testing.T) {
+	tests := []struct {
+		minutes float64
+		opts    ReadingTimeOptions
+		want    ReadingTime
+	}{
+		{2.4, ReadingTimeOptions{}, ReadingTime{Minutes: 2, Label: "2m"}},
+		{2.5, ReadingTimeOptions{}, ReadingTime{Minutes: 3, Label: "3m"}},
+		{2.4, ReadingTimeOptions{Rounding: "round"}, ReadingTime{Minutes: 2, Label: "2m"}},
+		{2.1, ReadingTimeOptions{Rounding: "ceil"}, ReadingTime{Minutes: 3, Label: "3m"}},
+		{2.9, ReadingTimeOptions{Rounding: "floor"}, ReadingTime{Minutes: 2, Label: "2m"}},
+		{0.2, ReadingTimeOptions{Rounding: "floor"}, ReadingTime{Minutes: 1, Label: "1m"}},
+		{0.9, ReadingTimeOptions{Threshold: 1, Below: "< 1m"}, ReadingTime{Label: "< 1m"}},
+		{0.9, ReadingTimeOptions{Threshold: 1, Below: "none"}, ReadingTime{}},
+		{1, ReadingTimeOptions{Threshold: 1, Below: "< 1m"}, ReadingTime{Minutes: 1, Label: "1m"}},
+		{1.6, ReadingTimeOptions{Threshold: 2, Below: "quick read", Rounding: "ceil"}, ReadingTime{Label: "quick read"}},
+	}
+	for _, test := range tests {
+		if got := getReadingTime(test.minutes, test.opts); got != test.want {
+			t.Errorf("getReadingTime(%v, %+v) = %+v, want %+v", test.minutes, test.opts, got, test.want)
+		}
+	}
+}
+
+func TestCalculateTimeToRead(t *testing.T) {
+	if got := calculateTimeToRead(200, 0); got != 1 {
+		t.Errorf("200 words take %v minutes, want 1", got)
+	}
+	if got := calculateTimeToRead(100, 5); got != 1.5 {
+		t.Errorf("100 words and 5 images take %v minutes, want 1.5", got)
+	}
+}
+
+func TestReadingTimeAndWordCountAgree(t *testing.T) {
+	prose := strings.Repeat("word ", 400)
+	code := strings.Repeat("token ", 200)
+	html := []byte("<h2>Title<a class=\"anchor\" href=\"#title\">#</a></h2><p>" + prose + "</p><pre><code>" + code + "</code></pre>")
+	tests := []struct {
+		code    bool
+		words   int
+		minutes int
+	}{
+		{false, 401, 2},
+		{true, 601, 3},
+	}
+	for _, test := range tests {
+		post := &Post{HTML: html}
+		setReadingTimes([]*Post{post}, ReadingTimeOptions{Code: test.code})
+		if post.ReadingTime.Words != test.words || post.ReadingTime.Minutes != test.minutes {
+			t.Errorf("code %v: got %d words and %d minutes, want %d and %d", test.code, post.ReadingTime.Words, post.ReadingTime.Minutes, test.words, test.minutes)
+		}
+		site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+			cfg.Blog.ReadingTime.Code = test.code
+		}), map[string]string{
+			"post/post.md": testPost("Post", "01.02.2021", "## Title\n\n"+prose+"\n\n```\n"+code+"\n```\n"),
+		}).build()
+		if got := getTestJSONLD(t, site.read("post/index.html")).WordCount; got != test.words {
+			t.Errorf("code %v: JSON-LD word count is %d, want %d", test.code, got, test.words)
+		}
+	}
+}
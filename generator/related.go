@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// relatedByTags ranks posts by the number of shared tags
+	relatedByTags = "tags"
+	// relatedBySimilarity ranks posts by the TF-IDF similarity of their
+	// titles and summaries
+	relatedBySimilarity = "similarity"
+)
+
+// minRelatedSimilarity is the cosine similarity below which posts are not
+// considered related, the newest posts fill the remaining places instead
+const minRelatedSimilarity = 0.1
+
+// relatedStopWords are left out of the similarity of posts
+var relatedStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "how": true, "in": true, "is": true, "it": true, "of": true, "on": true,
+	"or": true, "the": true, "this": true, "to": true, "with": true, "what": true, "why": true, "you": true,
+}
+
+// createRelatedPosts sets the n related posts of all posts, the posts have to
+// be sorted by date so ties are resolved in favour of newer posts
+func createRelatedPosts(posts []*Post, strategy string, n int) {
+	if n <= 0 {
+		return
+	}
+	var vectors []map[string]float64
+	if strategy == relatedBySimilarity {
+		vectors = getTFIDFVectors(posts)
+	}
+	for i, post := range posts {
+		type candidate struct {
+			post  *Post
+			score float64
+		}
+		candidates := []candidate{}
+		for j, other := range posts {
+			if i == j {
+				continue
+			}
+			var score float64
+			if strategy == relatedBySimilarity {
+				if score = cosineSimilarity(vectors[i], vectors[j]); score < minRelatedSimilarity {
+					continue
+				}
+			} else if score = float64(countSharedTags(post, other)); score == 0 {
+				continue
+			}
+			candidates = append(candidates, candidate{other, score})
+		}
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return candidates[a].score > candidates[b].score
+		})
+		post.Related = nil
+		chosen := map[*Post]bool{post: true}
+		for _, c := range candidates {
+			if len(post.Related) == n {
+				break
+			}
+			post.Related = append(post.Related, &PostLink{Title: c.post.Meta.Title, Link: c.post.Path})
+			chosen[c.post] = true
+		}
+		if strategy != relatedBySimilarity {
+			continue
+		}
+		for _, other := range posts {
+			if len(post.Related) == n {
+				break
+			}
+			if !chosen[other] {
+				post.Related = append(post.Related, &PostLink{Title: other.Meta.Title, Link: other.Path})
+			}
+		}
+	}
+}
+
+func countSharedTags(post, other *Post) int {
+	tags := map[string]bool{}
+	for _, tag := range post.Meta.Tags {
+		tags[strings.ToLower(tag)] = true
+	}
+	count := 0
+	for _, tag := range other.Meta.Tags {
+		if tags[strings.ToLower(tag)] {
+			count++
+			// a tag listed twice counts once
+			delete(tags, strings.ToLower(tag))
+		}
+	}
+	return count
+}
+
+// getTFIDFVectors returns the TF-IDF weights of the words of the titles and
+// summaries of the posts
+func getTFIDFVectors(posts []*Post) []map[string]float64 {
+	counts := make([]map[string]int, len(posts))
+	documents := map[string]int{}
+	for i, post := range posts {
+		counts[i] = map[string]int{}
+		for _, word := range relatedWords(post.Meta.Title + " " + post.Summary) {
+			if counts[i][word] == 0 {
+				documents[word]++
+			}
+			counts[i][word]++
+		}
+	}
+	vectors := make([]map[string]float64, len(posts))
+	for i, words := range counts {
+		vectors[i] = map[string]float64{}
+		for word, count := range words {
+			vectors[i][word] = float64(count) * math.Log(float64(len(posts))/float64(documents[word]))
+		}
+	}
+	return vectors
+}
+
+func relatedWords(text string) []string {
+	words := []string{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) > 1 && !relatedStopWords[word] {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+func cosineSimilarity(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+	for word, weight := range a {
+		dot += weight * b[word]
+		normA += weight * weight
+	}
+	for _, weight := range b {
+		normB += weight * weight
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRelatedPostsBySimilarity(t *testing.T) {
+	newPost := func(title, summary string) *Post {
+		return &Post{Meta: &Meta{Title: title}, Summary: summary, Path: "/" + title + "/"}
+	}
+	// sorted by date, newest first
+	posts := []*Post{
+		newPost("Go channels explained", "Sending values between goroutines with channels."),
+		newPost("Baking sourdough bread", "Flour, water, salt and patience."),
+		newPost("Goroutines and channels in practice", "Patterns for goroutines, channels and select."),
+		newPost("Rye bread at home", "Sourdough with rye flour."),
+	}
+	titles := func(post *Post) []string {
+		result := []string{}
+		for _, link := range post.Related {
+			result = append(result, link.Title)
+		}
+		return result
+	}
+	createRelatedPosts(posts, relatedBySimilarity, 1)
+	want := map[int]string{0: "Goroutines and channels in practice", 1: "Rye bread at home", 2: "Go channels explained", 3: "Baking sourdough bread"}
+	for i, title := range want {
+		if got := titles(posts[i]); !reflect.DeepEqual(got, []string{title}) {
+			t.Errorf("%s is related to %v, want %s", posts[i].Meta.Title, got, title)
+		}
+	}
+	// places without a similar post are filled with the newest posts
+	createRelatedPosts(posts, relatedBySimilarity, 3)
+	if got, want := titles(posts[0]), []string{"Goroutines and channels in practice", "Baking sourdough bread", "Rye bread at home"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("%s is related to %v, want %v", posts[0].Meta.Title, got, want)
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	a := map[string]float64{"go": 1, "channels": 2}
+	if got := cosineSimilarity(a, a); got < 0.999 || got > 1.001 {
+		t.Errorf("similarity of a vector with itself is %f", got)
+	}
+	if got := cosineSimilarity(a, map[string]float64{"bread": 1}); got != 0 {
+		t.Errorf("similarity of unrelated vectors is %f", got)
+	}
+	if got := cosineSimilarity(a, map[string]float64{}); got != 0 {
+		t.Errorf("similarity with an empty vector is %f", got)
+	}
+}
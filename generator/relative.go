@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// relativeLinkAttrs are the attributes holding on-page links by element
+var relativeLinkAttrs = map[string]string{
+	"a":      "href",
+	"link":   "href",
+	"img":    "src",
+	"script": "src",
+	"source": "src",
+	"iframe": "src",
+	"audio":  "src",
+	"video":  "src",
+}
+
+// absoluteLinkRels are link relations which have to stay absolute
+var absoluteLinkRels = map[string]bool{
+	"canonical": true,
+	"amphtml":   true,
+	"alternate": true,
+	"prev":      true,
+	"next":      true,
+}
+
+// makeLinksRelative rewrites the internal links of the page written to
+// filePath below destination relative to the page, so the site works from
+// the file system or below a sub path. Links to folders get an index.html.
+func makeLinksRelative(page []byte, blogURL, destination, filePath string) ([]byte, error) {
+	rel, err := filepath.Rel(destination, filePath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return page, nil
+	}
+	pageDir := filepath.ToSlash(filepath.Dir(rel))
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page))
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing html %s: %v", filePath, err)
+	}
+	for element, attr := range relativeLinkAttrs {
+		doc.Find(element + "[" + attr + "]").Each(func(i int, s *goquery.Selection) {
+			if element == "link" && absoluteLinkRels[strings.ToLower(s.AttrOr("rel", ""))] {
+				return
+			}
+			if link, ok := getRelativeLink(s.AttrOr(attr, ""), blogURL, pageDir); ok {
+				s.SetAttr(attr, link)
+			}
+		})
+	}
+	result, err := doc.Html()
+	if err != nil {
+		return nil, fmt.Errorf("error while generating html %s: %v", filePath, err)
+	}
+	return []byte(result), nil
+}
+
+// getRelativeLink returns link relative to pageDir, the folder of the page
+// below the root, if it is an internal link
+func getRelativeLink(link, blogURL, pageDir string) (string, bool) {
+	if blogURL != "" && (link == blogURL || strings.HasPrefix(link, blogURL+"/")) {
+		link = "/" + strings.TrimPrefix(strings.TrimPrefix(link, blogURL), "/")
+	}
+	if !strings.HasPrefix(link, "/") || strings.HasPrefix(link, "//") {
+		return "", false
+	}
+	target, suffix := link, ""
+	if i := strings.IndexAny(target, "?#"); i >= 0 {
+		target, suffix = target[:i], target[i:]
+	}
+	// links without an extension are folders, like for normalizeLink
+	if path.Ext(strings.TrimSuffix(target, "/")) == "" {
+		target = strings.TrimSuffix(target, "/") + "/index.html"
+	}
+	from := splitPath(pageDir)
+	to := splitPath(target)
+	common := 0
+	for common < len(from) && common < len(to)-1 && from[common] == to[common] {
+		common++
+	}
+	parts := []string{}
+	for range from[common:] {
+		parts = append(parts, "..")
+	}
+	parts = append(parts, to[common:]...)
+	return strings.Join(parts, "/") + suffix, true
+}
+
+func splitPath(p string) []string {
+	parts := []string{}
+	for _, part := range strings.Split(p, "/") {
+		if part != "" && part != "." {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestRelativeURLsOnNestedPage(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.RelativeURLs = true
+		cfg.Generator.NPG = 1
+	}), map[string]string{
+		"go/intro/post.md":       testPost("Intro", "01.02.2021", "Intro."),
+		"go/web/routing/post.md": testPost("Routing", "02.02.2021", "See [the intro](https://example.com/go/intro/#start) and [Go](https://go.dev/)."),
+	}).build()
+	page := site.read("go/web/routing/index.html")
+	for _, want := range []string{
+		`href="../../intro/index.html#start"`,
+		`href="../../../css/syntax.css"`,
+		`href="https://go.dev/"`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("nested page doesn't contain %s", want)
+		}
+	}
+	if archive := site.read("archive/page/2/index.html"); !strings.Contains(archive, `<link rel="prev" href="https://example.com/archive/"`) {
+		t.Error("prev link of the nested archive page isn't absolute")
+	}
+}
+
+func TestGetRelativeLink(t *testing.T) {
+	tests := []struct {
+		link, pageDir string
+		want          string
+		ok            bool
+	}{
+		{"/", "a/b", "../../index.html", true},
+		{"/a/c/", "a/b", "../c/index.html", true},
+		{"/a/b/image.png", "a/b", "image.png", true},
+		{"https://example.com/css/vec.css?v=1", ".", "css/vec.css?v=1", true},
+		{"//cdn.example.com/x.js", "a", "", false},
+		{"https://other.com/", "a", "", false},
+		{"#top", "a", "", false},
+	}
+	for _, test := range tests {
+		got, ok := getRelativeLink(test.link, "https://example.com", test.pageDir)
+		if got != test.want || ok != test.ok {
+			t.Errorf("getRelativeLink(%s, %s) = %s, %v, want %s, %v", test.link, test.pageDir, got, ok, test.want, test.ok)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/eleztian/blog-generator/config"
+	"gopkg.in/yaml.v2"
+)
+
+// RenderPost renders a single post from its source, the markdown with its
+// "---" delimited header, like the site generation does. No files are read
+// or written: includes, meta.yml, defaults.yml and images are not resolved.
+// Options which aren't set in cfg use the defaults of config.Defaults, the
+// transforms are applied after the built-in ones.
+func RenderPost(source []byte, blogConfig *config.Config, transforms ...Transform) (*Post, error) {
+	c := *blogConfig
+	cfg := &c
+	if err := config.Defaults(cfg); err != nil {
+		return nil, err
+	}
+	location, err := time.LoadLocation(cfg.Blog.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("error loading timezone %s: %v", cfg.Blog.Timezone, err)
+	}
+	raw := normalizeNewlines(source)
+	br := bufio.NewReader(bytes.NewReader(raw))
+	header, err := readHeader(br)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing meta: %v", err)
+	}
+	dateFormat := cfg.Blog.Dateformat
+	if dateFormat == autoDateFormat {
+		date := struct{ Date string }{}
+		if err := yaml.Unmarshal(header, &date); err != nil {
+			return nil, fmt.Errorf("error parsing meta: error reading yml: %v", err)
+		}
+		if dateFormat, err = matchDateFormat(date.Date); err != nil {
+			return nil, err
+		}
+	}
+	meta, err := parseMeta(header, dateFormat, location)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing meta: %v", err)
+	}
+	markdown, _ := ioutil.ReadAll(br)
+	bodyLine := bytes.Count(raw[:len(raw)-len(markdown)], []byte("\n")) + 1
+	slug, _ := getPostName(slugify(meta.Title, cfg.Blog.Slugs.CJK), "", meta.Slug)
+	name := slugifyPostName(slug, cfg.Blog.Slugs.CJK)
+	postPath := fmt.Sprintf("/%s/", name)
+	post := &Post{Name: name, Path: postPath, URL: cfg.Blog.URL + postPath, Category: meta.Category, Markdown: markdown, BodyLine: bodyLine, Meta: meta}
+	post.License = getPostLicense(meta, cfg.Blog.License, name)
+	opts, err := newRenderOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	opts.Transforms = transforms
+	variables := newBuildVariables(cfg, time.Now().In(location))
+	if post.HTML, err = getHTML(post, interpolateVariables(markdown, variables), opts); err != nil {
+		return nil, err
+	}
+	post.Summary = getSummary(post, cfg.Blog.Excerpt.Sources)
+	post.Excerpt = truncate(post.Summary, cfg.Blog.Excerpt.Length, cfg.Blog.Excerpt.Ellipsis)
+	return post, nil
+}
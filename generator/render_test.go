@@ -0,0 +1,41 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestRenderPostDefaults(t *testing.T) {
+	source := "---\ntitle: Hello World\nshort: " + strings.Repeat("word ", 60) + "\ndate: 03.04.2021\n---\n# Heading\n\nSome *text*.\n"
+	cfg := &config.Config{}
+	cfg.Blog.URL = "https://example.com"
+	post, err := RenderPost([]byte(source), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if post.Path != "/hello-world/" {
+		t.Errorf("got path %s, want /hello-world/", post.Path)
+	}
+	if !strings.Contains(string(post.HTML), `<em>text</em>`) || !strings.Contains(string(post.HTML), `id="heading"`) {
+		t.Errorf("got html %s", post.HTML)
+	}
+	if !strings.HasPrefix(post.Summary, "word word") {
+		t.Errorf("summary %q is not taken from short", post.Summary)
+	}
+	if len([]rune(post.Excerpt)) > 200 || !strings.HasSuffix(post.Excerpt, "…") {
+		t.Errorf("excerpt %q is not truncated to 200 characters", post.Excerpt)
+	}
+	if cfg.Blog.Dateformat != "" || len(cfg.Blog.Excerpt.Sources) != 0 {
+		t.Errorf("RenderPost changed the passed config")
+	}
+}
+
+func TestRenderPostInvalidOption(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Blog.Anchors.Position = "middle"
+	if _, err := RenderPost([]byte("---\ntitle: a\ndate: 03.04.2021\n---\n"), cfg); err == nil {
+		t.Error("expected an error for an invalid anchors position")
+	}
+}
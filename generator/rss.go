@@ -5,6 +5,8 @@ import (
 	"github.com/beevik/etree"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -17,68 +19,143 @@ type RSSGenerator struct {
 type RSSConfig struct {
 	Posts           []*Post
 	Destination     string
-	DateFormat      string
 	Language        string
 	BlogURL         string
 	BlogDescription string
 	BlogTitle       string
+	ExcerptLength   int
+	Ellipsis        string
+	FileMode        os.FileMode
+	DirMode         os.FileMode
+	Location        *time.Location
+	MaxPosts        int
+	TTL             int
+	// MathML converts the TeX math of the content to MathML
+	MathML bool
+	// Title and Path of the feed's folder below the blog URL replace the
+	// blog title and the blog URL for feeds other than the main feed
+	Title string
+	Path  string
+	// Updated dates the items by their update, every update of a post is a
+	// new item
+	Updated bool
 }
 
-const rssDateFormat string = "02 Jan 2006 15:04 -0700"
+const rssDateFormat string = time.RFC1123Z
+
+// FeedLink is an autodiscovery <link> to a feed in the <head> of pages
+type FeedLink struct {
+	Title string
+	Href  string
+	Type  string
+}
+
+// newRSSFeedLink returns the link to the RSS feed in the folder path below
+// the blog URL
+func newRSSFeedLink(blogURL, path, title string) FeedLink {
+	return FeedLink{Title: title, Href: strings.TrimSuffix(blogURL, "/") + path + "index.xml", Type: "application/rss+xml"}
+}
 
 // Generate creates an RSS feed
 func (g *RSSGenerator) Generate() error {
 	fmt.Println("\tGenerating RSS...")
 	posts := g.Config.Posts
+	// posts are sorted by date, the oldest ones are dropped
+	if g.Config.MaxPosts > 0 && len(posts) > g.Config.MaxPosts {
+		posts = posts[:g.Config.MaxPosts]
+	}
 	destination := g.Config.Destination
+	if err := os.MkdirAll(destination, g.Config.DirMode); err != nil {
+		return fmt.Errorf("error creating directory at %s: %v", destination, err)
+	}
 	doc := etree.NewDocument()
 	doc.CreateProcInst("xml", `version="1.0" encoding="UTF-8"`)
 	rss := doc.CreateElement("rss")
 	rss.CreateAttr("xmlns:atom", "http://www.w3.org/2005/Atom")
+	rss.CreateAttr("xmlns:content", "http://purl.org/rss/1.0/modules/content/")
+	rss.CreateAttr("xmlns:dc", "http://purl.org/dc/elements/1.1/")
+	rss.CreateAttr("xmlns:creativeCommons", "http://backend.userland.com/creativeCommonsRssModule")
 	rss.CreateAttr("version", "2.0")
 	channel := rss.CreateElement("channel")
 
-	channel.CreateElement("title").SetText(g.Config.BlogTitle)
-	channel.CreateElement("link").SetText(g.Config.BlogURL)
+	title, link := g.Config.BlogTitle, g.Config.BlogURL
+	if g.Config.Title != "" {
+		title = g.Config.Title
+	}
+	if g.Config.Path != "" {
+		link = g.Config.BlogURL + g.Config.Path
+	}
+	channel.CreateElement("title").SetText(title)
+	channel.CreateElement("link").SetText(link)
 	channel.CreateElement("language").SetText(g.Config.Language)
 	channel.CreateElement("description").SetText(g.Config.BlogDescription)
-	channel.CreateElement("lastBuildDate").SetText(time.Now().Format(rssDateFormat))
+	channel.CreateElement("lastBuildDate").SetText(time.Now().In(g.Config.Location).Format(rssDateFormat))
+	if g.Config.TTL > 0 {
+		channel.CreateElement("ttl").SetText(strconv.Itoa(g.Config.TTL))
+	}
 
 	atomLink := channel.CreateElement("atom:link")
-	atomLink.CreateAttr("href", fmt.Sprintf("%s/index.xml", g.Config.BlogURL))
+	atomLink.CreateAttr("href", fmt.Sprintf("%s/index.xml", strings.TrimSuffix(link, "/")))
 	atomLink.CreateAttr("rel", "self")
 	atomLink.CreateAttr("type", "application/rss+xml")
 
 	for _, post := range posts {
-		if err := addItem(channel, post, fmt.Sprintf("%s/%s/", g.Config.BlogURL, post.Name[1:]), g.Config.DateFormat); err != nil {
+		if err := addItem(channel, post, g.Config); err != nil {
 			return err
 		}
 	}
 
 	filePath := filepath.Join(destination, "index.xml")
-	f, err := os.Create(filePath)
+	f, err := createFile(filePath, g.Config.FileMode)
 	if err != nil {
 		return fmt.Errorf("error creating file %s: %v", filePath, err)
 	}
-	f.Close()
-	if err := doc.WriteToFile(filePath); err != nil {
+	defer f.Close()
+	if _, err := doc.WriteTo(f); err != nil {
 		return fmt.Errorf("error writing to file %s: %v", filePath, err)
 	}
 	fmt.Println("\tFinished generating RSS...")
 	return nil
 }
 
-func addItem(element *etree.Element, post *Post, path, dateFormat string) error {
+func addItem(element *etree.Element, post *Post, cfg *RSSConfig) error {
 	meta := post.Meta
 	item := element.CreateElement("item")
 	item.CreateElement("title").SetText(meta.Title)
-	item.CreateElement("link").SetText(path)
-	item.CreateElement("guid").SetText(path)
-	pubDate, err := time.Parse(dateFormat, meta.Date)
-	if err != nil {
-		return fmt.Errorf("error parsing date %s: %v", meta.Date, err)
+	item.CreateElement("link").SetText(post.URL)
+	if meta.ParsedDate.IsZero() {
+		return fmt.Errorf("error parsing date %s of %s", meta.Date, post.Name)
+	}
+	if cfg.Updated {
+		updated := getUpdateTime(post)
+		guid := item.CreateElement("guid")
+		guid.CreateAttr("isPermaLink", "false")
+		guid.SetText(post.URL + "#updated-" + updated.Format(time.RFC3339))
+		item.CreateElement("pubDate").SetText(updated.Format(rssDateFormat))
+	} else {
+		item.CreateElement("guid").SetText(post.URL)
+		item.CreateElement("pubDate").SetText(meta.ParsedDate.Format(rssDateFormat))
+	}
+	item.CreateElement("description").SetText(truncate(post.Summary, cfg.ExcerptLength, cfg.Ellipsis))
+	content := post.HTML
+	if cfg.MathML {
+		var err error
+		if content, err = convertFeedMath(content); err != nil {
+			return fmt.Errorf("error converting math of %s: %v", post.Name, err)
+		}
+	}
+	item.CreateElement("content:encoded").SetText(string(content))
+	if license := post.License; license != nil {
+		item.CreateElement("dc:rights").SetText(license.Name)
+		if license.URL != "" {
+			item.CreateElement("creativeCommons:license").SetText(license.URL)
+		}
+	}
+	if meta.Enclosure != nil {
+		enclosure := item.CreateElement("enclosure")
+		enclosure.CreateAttr("url", getEnclosureURL(post))
+		enclosure.CreateAttr("length", strconv.FormatInt(meta.Enclosure.Length, 10))
+		enclosure.CreateAttr("type", meta.Enclosure.Type)
 	}
-	item.CreateElement("pubDate").SetText(pubDate.Format(rssDateFormat))
-	item.CreateElement("description").SetText(string(post.HTML))
 	return nil
 }
@@ -0,0 +1,221 @@
+package generator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+// testFeed is a parsed RSS feed
+type testFeed struct {
+	Channel struct {
+		Title string `xml:"title"`
+		TTL   int    `xml:"ttl"`
+		Items []struct {
+			Title       string `xml:"title"`
+			Link        string `xml:"link"`
+			GUID        string `xml:"guid"`
+			PubDate     string `xml:"pubDate"`
+			Description string `xml:"description"`
+			Content     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+			Enclosure   *struct {
+				URL    string `xml:"url,attr"`
+				Length string `xml:"length,attr"`
+				Type   string `xml:"type,attr"`
+			} `xml:"enclosure"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+func parseTestFeed(t *testing.T, data string) *testFeed {
+	t.Helper()
+	feed := &testFeed{}
+	if err := xml.Unmarshal([]byte(data), feed); err != nil {
+		t.Fatalf("error parsing feed: %v\n%s", err, data)
+	}
+	return feed
+}
+
+func TestFeedItemDescriptionAndContent(t *testing.T) {
+	short := strings.Repeat("word ", 60)
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"post/post.md": testPost("Post", "01.02.2021", "The *full* text.", "short: "+short),
+	}).build()
+	feed := parseTestFeed(t, site.read("index.xml"))
+	if len(feed.Channel.Items) != 1 {
+		t.Fatalf("got %d items, want 1", len(feed.Channel.Items))
+	}
+	item := feed.Channel.Items[0]
+	if want := truncate(short, 200, "…"); item.Description != want {
+		t.Errorf("description is %q, want the truncated summary %q", item.Description, want)
+	}
+	if !strings.Contains(item.Content, "<em>full</em>") {
+		t.Errorf("content:encoded is %q, want the html of the post", item.Content)
+	}
+}
+
+func TestFeedEnclosure(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"local/post.md":     testPost("Local Episode", "01.02.2021", "Local.", "enclosure:\n  file: episode.mp3"),
+		"local/episode.mp3": strings.Repeat("x", 1234),
+		"remote/post.md":    testPost("Remote Episode", "02.02.2021", "Remote.", "enclosure:\n  url: https://cdn.example.org/ep.ogg\n  length: 5678\n  type: audio/ogg"),
+		"text/post.md":      testPost("Text Post", "03.02.2021", "Text."),
+	}).build()
+	enclosures := map[string]string{}
+	for _, item := range parseTestFeed(t, site.read("index.xml")).Channel.Items {
+		if item.Enclosure != nil {
+			enclosures[item.Title] = item.Enclosure.URL + " " + item.Enclosure.Length + " " + item.Enclosure.Type
+		}
+	}
+	want := map[string]string{
+		"Local Episode":  "https://example.com/local/episode.mp3 1234 audio/mpeg",
+		"Remote Episode": "https://cdn.example.org/ep.ogg 5678 audio/ogg",
+	}
+	if !reflect.DeepEqual(enclosures, want) {
+		t.Errorf("enclosures are %v, want %v", enclosures, want)
+	}
+	if !site.exists("local/episode.mp3") {
+		t.Error("local enclosure wasn't copied")
+	}
+}
+
+func TestCheckEnclosureType(t *testing.T) {
+	if err := checkEnclosure(t.TempDir(), &Enclosure{URL: "https://example.org/doc.pdf", Length: 1, Type: "application/pdf"}); err == nil {
+		t.Error("enclosure of a document was accepted")
+	}
+}
+
+// feedTitles returns the titles of the items of a feed
+func feedTitles(feed *testFeed) []string {
+	titles := []string{}
+	for _, item := range feed.Channel.Items {
+		titles = append(titles, item.Title)
+	}
+	return titles
+}
+
+func TestTagFeeds(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.Feeds.Tags = true
+		cfg.Blog.Feeds.TagPosts = 2
+	}), map[string]string{
+		"a/post.md": testPost("Go A", "01.02.2021", "A.", "tags: [go]"),
+		"b/post.md": testPost("Web B", "02.02.2021", "B.", "tags: [web]"),
+		"c/post.md": testPost("Go C", "03.02.2021", "C.", "tags: [Go, web]"),
+		"d/post.md": testPost("Go D", "04.02.2021", "D.", "tags: [go]"),
+	}).build()
+	goFeed := parseTestFeed(t, site.read("tags/go/index.xml"))
+	if got, want := feedTitles(goFeed), []string{"Go D", "Go C"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("go feed has %v, want %v", got, want)
+	}
+	if got, want := feedTitles(parseTestFeed(t, site.read("tags/web/index.xml"))), []string{"Go C", "Web B"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("web feed has %v, want %v", got, want)
+	}
+	if goFeed.Channel.Items[0].Link != "https://example.com/d/" {
+		t.Errorf("tag feed links %s, want an absolute URL", goFeed.Channel.Items[0].Link)
+	}
+}
+
+func TestFeedPostsAndTTL(t *testing.T) {
+	files := map[string]string{}
+	for i := 1; i <= 5; i++ {
+		name := string(rune('a' + i))
+		files[name+"/post.md"] = testPost("Post "+name, fmt.Sprintf("%02d.02.2021", i), "Text.", "tags: [go]")
+	}
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.Feeds.Posts = 3
+		cfg.Blog.Feeds.TTL = 60
+		cfg.Blog.Feeds.Tags = true
+	}), files).build()
+	for _, name := range []string{"index.xml", "tags/go/index.xml"} {
+		feed := parseTestFeed(t, site.read(name))
+		if got, want := feedTitles(feed), []string{"Post f", "Post e", "Post d"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s has %v, want the 3 most recent posts %v", name, got, want)
+		}
+		if feed.Channel.TTL != 60 {
+			t.Errorf("%s has ttl %d, want 60", name, feed.Channel.TTL)
+		}
+	}
+
+	site = newTestSite(t, newTestConfig(t, nil), files).build()
+	feed := site.read("index.xml")
+	if strings.Contains(feed, "<ttl>") {
+		t.Error("feed has a ttl without one configured")
+	}
+	if items := len(parseTestFeed(t, feed).Channel.Items); items != 5 {
+		t.Errorf("feed has %d items, want all 5 below the default limit", items)
+	}
+}
+
+func TestFeedMathML(t *testing.T) {
+	body := `Area $\pi r^2$ costs $5 or $10.`
+	for _, mathML := range []bool{false, true} {
+		site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+			cfg.Blog.Feeds.MathML = mathML
+		}), map[string]string{
+			"post/post.md": testPost("Post", "01.02.2021", body),
+		}).build()
+		content := parseTestFeed(t, site.read("index.xml")).Channel.Items[0].Content
+		if got := strings.Contains(content, `<math xmlns="http://www.w3.org/1998/Math/MathML">`); got != mathML {
+			t.Errorf("mathml %v: feed content has MathML %v:\n%s", mathML, got, content)
+		}
+		if mathML && (!strings.Contains(content, "<mi>π</mi>") || strings.Contains(content, `$\pi`)) {
+			t.Errorf("math in the feed wasn't converted:\n%s", content)
+		}
+		if !strings.Contains(content, "costs $5 or $10.") {
+			t.Errorf("mathml %v: prices were changed:\n%s", mathML, content)
+		}
+		if page := site.read("post/index.html"); !strings.Contains(page, `$\pi r^2$`) {
+			t.Errorf("mathml %v: math on the page was changed", mathML)
+		}
+	}
+}
+
+func TestFeedAlternateLinks(t *testing.T) {
+	main := `<link href="https://example.com/index.xml" rel="alternate" type="application/rss&#43;xml" title="Test Blog" />`
+	updated := `<link href="https://example.com/updated/index.xml" rel="alternate" type="application/rss&#43;xml" title="` + getUpdatedFeedTitle("Test Blog") + `" />`
+	tag := `<link href="https://example.com/tags/go/index.xml" rel="alternate" type="application/rss&#43;xml" title="go - Test Blog" />`
+	for _, enabled := range []bool{false, true} {
+		site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+			cfg.Blog.Feeds.Updated = enabled
+			cfg.Blog.Feeds.Tags = true
+		}), map[string]string{
+			"post/post.md": testPost("Post", "01.02.2021", "Text.", "tags: [go]"),
+		}).build()
+		index := site.read("blog/index.html")
+		if strings.Count(index, main) != 1 {
+			t.Errorf("updated %v: index doesn't link the main feed once", enabled)
+		}
+		if got := strings.Contains(index, updated); got != enabled {
+			t.Errorf("updated %v: index links the updated feed %v", enabled, got)
+		}
+		if page := site.read("tags/go/index.html"); !strings.Contains(page, tag) || !strings.Contains(page, main) {
+			t.Errorf("updated %v: tag page doesn't link the tag and main feeds", enabled)
+		}
+	}
+}
+
+func TestUpdatedFeed(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.Feeds.Updated = true
+	}), map[string]string{
+		"a/post.md": testPost("A", "01.02.2021", "A.", "updated: 10.02.2021"),
+		"b/post.md": testPost("B", "02.02.2021", "B.", "updated: 05.02.2021"),
+		"c/post.md": testPost("C", "03.02.2021", "C."),
+		"d/post.md": testPost("D", "04.02.2021", "D.", "updated: 04.02.2021"),
+	}).build()
+	feed := parseTestFeed(t, site.read("updated/index.xml"))
+	if got, want := feedTitles(feed), []string{"A", "B"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("updated feed lists %v, want %v", got, want)
+	}
+	if guid := feed.Channel.Items[0].GUID; guid != "https://example.com/a/#updated-2021-02-10T00:00:00Z" {
+		t.Errorf("updated item has guid %s", guid)
+	}
+	if got, want := feedTitles(parseTestFeed(t, site.read("index.xml"))), []string{"D", "C", "B", "A"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("main feed lists %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,50 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/eleztian/blog-generator/config"
+	"gopkg.in/yaml.v2"
+)
+
+// CreatePost creates the folder of a new post below root, named after the
+// slug of its title, with a post.md holding the header of the post dated now
+// and an empty images folder. Existing folders are not overwritten.
+func CreatePost(root, title string, cfg *config.Config, now time.Time) (string, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return "", fmt.Errorf("please provide the title of the post")
+	}
+	location, err := time.LoadLocation(cfg.Blog.Timezone)
+	if err != nil {
+		return "", fmt.Errorf("error loading timezone %s: %v", cfg.Blog.Timezone, err)
+	}
+	dateFormat := cfg.Blog.Dateformat
+	if dateFormat == autoDateFormat || dateFormat == "" {
+		dateFormat = dateFormatCandidates[0]
+	}
+	quoted, err := yaml.Marshal(title)
+	if err != nil {
+		return "", fmt.Errorf("error creating header: %v", err)
+	}
+	path := filepath.Join(root, slugify(title, cfg.Blog.Slugs.CJK))
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("post %s already exists", path)
+	}
+	imagesDir := cfg.Generator.Images.Dir
+	if imagesDir == "" {
+		imagesDir = defaultImagesDir
+	}
+	if err := os.MkdirAll(filepath.Join(path, imagesDir), cfg.Generator.DirMode); err != nil {
+		return "", fmt.Errorf("error creating directory at %s: %v", path, err)
+	}
+	header := fmt.Sprintf("---\ntitle: %s\nshort: ''\ndate: %s\ntags: []\n---\n", strings.TrimSpace(string(quoted)), now.In(location).Format(dateFormat))
+	if err := writeTextFile(filepath.Join(path, "post.md"), []byte(header), cfg.Generator.FileMode); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,39 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreatePost(t *testing.T) {
+	root := t.TempDir()
+	cfg := newTestConfig(t, nil)
+	now := time.Date(2021, 2, 3, 23, 30, 0, 0, time.UTC)
+	path, err := CreatePost(root, " Hello: World ", cfg, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(root, "hello-world"); path != want {
+		t.Errorf("post was created at %s, want %s", path, want)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(path, "post.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "---\ntitle: 'Hello: World'\nshort: ''\ndate: 03.02.2021\ntags: []\n---\n"; string(data) != want {
+		t.Errorf("post.md is %q, want %q", data, want)
+	}
+	if info, err := os.Stat(filepath.Join(path, defaultImagesDir)); err != nil || !info.IsDir() {
+		t.Errorf("images folder wasn't created: %v", err)
+	}
+	if _, err := CreatePost(root, "Hello: World", cfg, now); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("second scaffold returned %v, want an already exists error", err)
+	}
+	if again, _ := ioutil.ReadFile(filepath.Join(path, "post.md")); string(again) != string(data) {
+		t.Error("second scaffold overwrote post.md")
+	}
+}
@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultSecurityExpires is the number of days security.txt expires after
+// the build if no positive number is configured
+const defaultSecurityExpires = 365
+
+// SecurityGenerator object
+type SecurityGenerator struct {
+	Config *SecurityConfig
+}
+
+// SecurityConfig holds the configuration of security.txt
+type SecurityConfig struct {
+	Contact     []string
+	Policy      string
+	Encryption  string
+	Languages   string
+	ExpiresDays int
+	BlogURL     string
+	Destination string
+	DirMode     os.FileMode
+	FileMode    os.FileMode
+}
+
+// Generate writes /.well-known/security.txt as described in RFC 9116,
+// nothing is written without a contact
+func (g *SecurityGenerator) Generate() error {
+	if len(g.Config.Contact) == 0 {
+		return nil
+	}
+	fmt.Println("\tGenerating security.txt...")
+	path := filepath.Join(g.Config.Destination, ".well-known")
+	if err := createFolderIfNotExist(path, g.Config.DirMode); err != nil {
+		return err
+	}
+	days := g.Config.ExpiresDays
+	if days <= 0 {
+		days = defaultSecurityExpires
+	}
+	expires := time.Now().UTC().AddDate(0, 0, days)
+	return writeTextFile(filepath.Join(path, "security.txt"), buildSecurityTxt(g.Config, expires), g.Config.FileMode)
+}
+
+func buildSecurityTxt(cfg *SecurityConfig, expires time.Time) []byte {
+	var b bytes.Buffer
+	for _, contact := range cfg.Contact {
+		if strings.Contains(contact, "@") && !strings.Contains(contact, ":") {
+			contact = "mailto:" + contact
+		}
+		fmt.Fprintf(&b, "Contact: %s\n", contact)
+	}
+	fmt.Fprintf(&b, "Expires: %s\n", expires.Format(time.RFC3339))
+	if cfg.Encryption != "" {
+		fmt.Fprintf(&b, "Encryption: %s\n", cfg.Encryption)
+	}
+	if cfg.Policy != "" {
+		fmt.Fprintf(&b, "Policy: %s\n", cfg.Policy)
+	}
+	if cfg.Languages != "" {
+		fmt.Fprintf(&b, "Preferred-Languages: %s\n", cfg.Languages)
+	}
+	fmt.Fprintf(&b, "Canonical: %s/.well-known/security.txt\n", strings.TrimSuffix(cfg.BlogURL, "/"))
+	return b.Bytes()
+}
@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSecurityTxt(t *testing.T) {
+	dir := t.TempDir()
+	for _, days := range []int{0, -5, 30} {
+		g := SecurityGenerator{&SecurityConfig{
+			Contact:     []string{"security@example.com", "https://example.com/contact"},
+			ExpiresDays: days,
+			BlogURL:     "https://example.com/",
+			Destination: dir,
+			DirMode:     0755,
+			FileMode:    0644,
+		}}
+		if err := g.Generate(); err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, ".well-known", "security.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		txt := string(data)
+		if !strings.Contains(txt, "Contact: mailto:security@example.com\nContact: https://example.com/contact\n") {
+			t.Errorf("contacts are missing:\n%s", txt)
+		}
+		if !strings.Contains(txt, "Canonical: https://example.com/.well-known/security.txt\n") {
+			t.Errorf("canonical URL is wrong:\n%s", txt)
+		}
+		var expires time.Time
+		for _, line := range strings.Split(txt, "\n") {
+			if strings.HasPrefix(line, "Expires: ") {
+				if expires, err = time.Parse(time.RFC3339, strings.TrimPrefix(line, "Expires: ")); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		if !expires.After(time.Now()) {
+			t.Errorf("expires %v of %d days isn't in the future", expires, days)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// sharedImagesDir is the folder of the blog repository holding images shared
+// by posts, they are referenced by posts below sharedImagesPath
+const sharedImagesDir = "_assets/images"
+
+// sharedImagesPath is the URL path the shared images are generated to
+const sharedImagesPath = "/assets/"
+
+// getSharedImagesDir returns the shared images folder below root or an empty
+// string if there is none
+func getSharedImagesDir(root string) (string, error) {
+	if root == "" {
+		return "", nil
+	}
+	dir := filepath.Join(root, filepath.FromSlash(sharedImagesDir))
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error reading folder %s: %v", dir, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a folder", dir)
+	}
+	return dir, nil
+}
+
+// getImageFile returns the source file of an image referenced by a post,
+// either in the post's images folder or in the shared images folder. Other
+// images, e.g. on other hosts, return an empty string.
+func getImageFile(post *Post, src string) string {
+	var dir, rel string
+	switch {
+	case strings.HasPrefix(src, getImagesPath(post)):
+		dir, rel = post.ImagesDir, strings.TrimPrefix(src, getImagesPath(post))
+	case strings.HasPrefix(src, sharedImagesPath):
+		dir, rel = post.SharedImagesDir, strings.TrimPrefix(src, sharedImagesPath)
+	}
+	if dir == "" || rel == "" {
+		return ""
+	}
+	clean := filepath.Clean(filepath.FromSlash(rel))
+	if strings.HasPrefix(clean, "..") {
+		return ""
+	}
+	return filepath.Join(dir, clean)
+}
+
+// SharedImagesGenerator object
+type SharedImagesGenerator struct {
+	Config *SharedImagesConfig
+}
+
+// SharedImagesConfig holds the configuration for the shared images
+type SharedImagesConfig struct {
+	Source      string
+	Destination string
+	Images      *ImageProcessor
+	DirMode     os.FileMode
+}
+
+// Generate copies the shared images once to the assets folder, processing
+// them like the images of posts
+func (g *SharedImagesGenerator) Generate() error {
+	fmt.Println("\tGenerating Shared Images...")
+	source := g.Config.Source
+	destination := filepath.Join(g.Config.Destination, filepath.FromSlash(strings.Trim(sharedImagesPath, "/")))
+	err := filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(destination, rel)
+		if info.IsDir() {
+			return createFolderIfNotExist(dst, g.Config.DirMode)
+		}
+		return g.Config.Images.Process(path, dst)
+	})
+	if err != nil {
+		return fmt.Errorf("error copying shared images %s: %v", source, err)
+	}
+	fmt.Println("\tFinished generating Shared Images...")
+	return nil
+}
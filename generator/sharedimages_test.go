@@ -0,0 +1,32 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSharedImageCopiedOnce(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"_assets/images/logo.png": testPNG(t, 4, 4),
+		"first/post.md":           testPost("First Post", "01.02.2021", "![Logo](/assets/logo.png)"),
+		"second/post.md":          testPost("Second Post", "02.02.2021", "![Logo](/assets/logo.png)"),
+	}).build()
+	var copies []string
+	filepath.Walk(site.Dest, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.Name() == "logo.png" {
+			rel, _ := filepath.Rel(site.Dest, path)
+			copies = append(copies, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if len(copies) != 1 || copies[0] != "assets/logo.png" {
+		t.Errorf("shared image was copied to %v, want only assets/logo.png", copies)
+	}
+	for _, page := range []string{"first/index.html", "second/index.html"} {
+		if !strings.Contains(site.read(page), `src="/assets/logo.png"`) {
+			t.Errorf("%s doesn't reference the shared image", page)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package generator
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+// TestMain runs the tests from the root of the repository, where the
+// templates and statics are found like in a real build
+func TestMain(m *testing.M) {
+	if err := os.Chdir(".."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// newTestConfig returns the defaulted configuration of a test blog, edit is
+// called before the defaults are applied
+func newTestConfig(t testing.TB, edit func(cfg *config.Config)) *config.Config {
+	cfg := &config.Config{}
+	cfg.Generator.Repo = "https://example.com/blog.git"
+	cfg.Blog.URL = "https://example.com"
+	cfg.Blog.Title = "Test Blog"
+	cfg.Blog.Description = "A blog of the tests"
+	cfg.Blog.Author = "Tester"
+	if edit != nil {
+		edit(cfg)
+	}
+	if err := config.Defaults(cfg); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+// writeTestFiles writes files by their path relative to root
+func writeTestFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// testPNG returns a png image of the given size
+func testPNG(t *testing.T, width, height int) string {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), uint8(x + y), 255})
+		}
+	}
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+// captureStdout returns what fn prints, the generator reports warnings on
+// stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	output := make(chan string)
+	go func() {
+		data, _ := ioutil.ReadAll(r)
+		output <- string(data)
+	}()
+	defer func() {
+		os.Stdout = stdout
+	}()
+	fn()
+	w.Close()
+	return <-output
+}
+
+// testSite is a blog built by a test
+type testSite struct {
+	t      *testing.T
+	Root   string
+	Dest   string
+	Config *config.Config
+}
+
+// newTestSite writes the files of a blog, by their path relative to the
+// source root, every folder with a post.md is a post
+func newTestSite(t *testing.T, cfg *config.Config, files map[string]string) *testSite {
+	dir := t.TempDir()
+	site := &testSite{t: t, Root: filepath.Join(dir, "src"), Dest: filepath.Join(dir, "www"), Config: cfg}
+	cfg.Generator.Tmp = site.Root
+	cfg.Generator.Dest = site.Dest
+	cfg.Generator.Preview = site.Dest + "-preview"
+	writeTestFiles(t, site.Root, files)
+	return site
+}
+
+// sources returns the post folders of the site
+func (s *testSite) sources() []string {
+	var sources []string
+	filepath.Walk(s.Root, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && info.Name() == "post.md" {
+			sources = append(sources, filepath.Dir(path))
+		}
+		return nil
+	})
+	sort.Strings(sources)
+	return sources
+}
+
+// generate builds the site and returns the error of the generation
+func (s *testSite) generate() error {
+	return New(&SiteConfig{Sources: s.sources(), SourceRoot: s.Root, Destination: s.Dest, Config: s.Config}).Generate()
+}
+
+// build builds the site and fails the test on errors
+func (s *testSite) build() *testSite {
+	s.t.Helper()
+	if err := s.generate(); err != nil {
+		s.t.Fatal(err)
+	}
+	return s
+}
+
+// read returns the content of a generated file by its path below the
+// destination, the test fails if it doesn't exist
+func (s *testSite) read(name string) string {
+	s.t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join(s.Dest, filepath.FromSlash(name)))
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return string(data)
+}
+
+// exists returns whether a file exists below the destination
+func (s *testSite) exists(name string) bool {
+	_, err := os.Stat(filepath.Join(s.Dest, filepath.FromSlash(name)))
+	return err == nil
+}
+
+// testPost returns the source of a post with the given header lines
+func testPost(title, date, body string, header ...string) string {
+	lines := append([]string{"---", "title: " + title, "date: " + date}, header...)
+	return strings.Join(append(lines, "---", body), "\n")
+}
+
+func TestBuildTestSite(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"first/post.md":  testPost("First Post", "01.02.2021", "The first post.", "short: first", "tags: [go]"),
+		"second/post.md": testPost("Second Post", "02.02.2021", "The second post.", "short: second", "tags: [go, web]"),
+	}).build()
+	if !strings.Contains(site.read("first/index.html"), "The first post.") {
+		t.Error("first post wasn't generated")
+	}
+	index := site.read("blog/index.html")
+	if strings.Index(index, "Second Post") > strings.Index(index, "First Post") {
+		t.Error("posts on the index aren't sorted by date")
+	}
+	if !site.exists("tags/web/index.html") {
+		t.Error("tag page wasn't generated")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/beevik/etree"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // SitemapGenerator object
@@ -19,6 +20,9 @@ type SitemapConfig struct {
 	Destination string
 	BlogURL     string
 	Statics     []string
+	CleanURLs   bool
+	FileMode    os.FileMode
+	Paths       SitePaths
 }
 
 // Generate creates the sitemap
@@ -33,47 +37,57 @@ func (g *SitemapGenerator) Generate() error {
 	urlSet.CreateAttr("xmlns", "http://www.sitemaps.org/schemas/sitemap/0.9")
 	urlSet.CreateAttr("xmlns:image", "http://www.google.com/schemas/sitemap-image/1.1")
 
-	url := urlSet.CreateElement("url")
-	loc := url.CreateElement("loc")
-	loc.SetText(g.Config.BlogURL)
+	addLocation := func(element *etree.Element, location string, images []string) {
+		if g.Config.CleanURLs {
+			location = normalizeLink(location)
+		}
+		addURL(element, location, images)
+	}
+	addLocation(urlSet, g.Config.BlogURL, nil)
 
+	blogURL := g.Config.BlogURL
 	for _, staticURL := range g.Config.Statics {
-		addURL(urlSet, staticURL, g.Config.BlogURL, nil)
+		// the root page is already part of the sitemap
+		if staticURL = strings.Trim(staticURL, "/"); staticURL != "" {
+			addLocation(urlSet, fmt.Sprintf("%s/%s/", blogURL, staticURL), nil)
+		}
 	}
-	addURL(urlSet, "archive", g.Config.BlogURL, nil)
-	addURL(urlSet, "tags", g.Config.BlogURL, nil)
+	addLocation(urlSet, blogURL+g.Config.Paths.ArchiveLink(), nil)
+	addLocation(urlSet, blogURL+g.Config.Paths.TagsLink(), nil)
 
 	for tag := range tagPostsMap {
-		addURL(urlSet, tag, g.Config.BlogURL, nil)
+		addLocation(urlSet, blogURL+g.Config.Paths.tagLink(tag), nil)
 	}
 
 	for _, post := range posts {
-		addURL(urlSet, post.Name[1:], g.Config.BlogURL, post.Images)
+		images := []string{}
+		for _, image := range post.Images {
+			images = append(images, post.URL+getImagesPath(post)+image)
+		}
+		addLocation(urlSet, post.URL, images)
 	}
 
 	filePath := filepath.Join(destination, "sitemap.xml")
-	f, err := os.Create(filePath)
+	f, err := createFile(filePath, g.Config.FileMode)
 	if err != nil {
 		return fmt.Errorf("error creating file %s: %v", filePath, err)
 	}
-	f.Close()
-	if err := doc.WriteToFile(filePath); err != nil {
+	defer f.Close()
+	if _, err := doc.WriteTo(f); err != nil {
 		return fmt.Errorf("error writing to file %s: %v", filePath, err)
 	}
 	fmt.Println("\tFinished generating Sitemap...")
 	return nil
 }
 
-func addURL(element *etree.Element, location, blogURL string, images []string) {
+func addURL(element *etree.Element, location string, images []string) {
 	url := element.CreateElement("url")
 	loc := url.CreateElement("loc")
-	loc.SetText(fmt.Sprintf("%s/%s/", blogURL, location))
+	loc.SetText(location)
 
-	if len(images) > 0 {
-		for _, image := range images {
-			img := url.CreateElement("image:image")
-			imgLoc := img.CreateElement("image:loc")
-			imgLoc.SetText(fmt.Sprintf("%s/%s/images/%s", blogURL, location, image))
-		}
+	for _, image := range images {
+		img := url.CreateElement("image:image")
+		imgLoc := img.CreateElement("image:loc")
+		imgLoc.SetText(image)
 	}
 }
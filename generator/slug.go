@@ -0,0 +1,177 @@
+package generator
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// slugKeepCJK keeps CJK characters in slugs
+	slugKeepCJK = "keep"
+	// slugRomanizeCJK romanizes kana and hangul, han characters are kept
+	// as there is no reading to derive from them
+	slugRomanizeCJK = "romanize"
+)
+
+// slugify converts a title, tag or heading text to a lowercase slug of
+// letters, digits and dashes. Accented latin letters are transliterated, CJK
+// characters are kept or romanized depending on cjk and symbols like emoji are
+// dropped. Texts without any letters or digits get a slug from their hash.
+func slugify(text, cjk string) string {
+	romanize := cjk == slugRomanizeCJK
+	var b strings.Builder
+	dash := false
+	runes := []rune(strings.ToLower(strings.TrimSpace(text)))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		s := ""
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			s = transliterateLatin(r)
+		case romanize && isHangul(r):
+			s = romanizeHangul(r)
+		case romanize && isKana(r):
+			var n int
+			s, n = romanizeKana(runes[i:])
+			i += n - 1
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			s = string(r)
+		}
+		switch {
+		case s != "":
+			b.WriteString(s)
+			dash = false
+		case !dash && b.Len() > 0 && !unicode.Is(unicode.Mn, r):
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		return fmt.Sprintf("%x", sha1.Sum([]byte(text)))[:8]
+	}
+	return slug
+}
+
+// latinTransliterations are the latin letters which don't decompose into a
+// base letter and combining marks
+var latinTransliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'þ': "th", 'ł': "l", 'ı': "i", 'ħ': "h",
+}
+
+// transliterateLatin returns the ascii letters of a latin letter, accents are
+// removed
+func transliterateLatin(r rune) string {
+	if s, ok := latinTransliterations[r]; ok {
+		return s
+	}
+	var b strings.Builder
+	for _, d := range norm.NFD.String(string(r)) {
+		if d < unicode.MaxASCII && (unicode.IsLetter(d) || unicode.IsDigit(d)) {
+			b.WriteRune(d)
+		}
+	}
+	return b.String()
+}
+
+// kanaRomaji maps hiragana to their Hepburn romanization, katakana are mapped
+// to hiragana first
+var kanaRomaji = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n", 'ゔ': "vu",
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+	'ゃ': "ya", 'ゅ': "yu", 'ょ': "yo", 'ゎ': "wa",
+}
+
+// smallKana combine with the preceding kana, e.g. きゃ is kya and ふぁ is fa
+var smallKana = map[rune]bool{'ぁ': true, 'ぃ': true, 'ぅ': true, 'ぇ': true, 'ぉ': true, 'ゃ': true, 'ゅ': true, 'ょ': true}
+
+func isKana(r rune) bool {
+	h := toHiragana(r)
+	return kanaRomaji[h] != "" || h == 'っ' || h == 'ー'
+}
+
+// toHiragana maps katakana to the corresponding hiragana
+func toHiragana(r rune) rune {
+	if r >= 'ァ' && r <= 'ヶ' {
+		return r - 'ァ' + 'ぁ'
+	}
+	return r
+}
+
+// romanizeKana romanizes the kana at the start of runes and returns the
+// number of runes used. The sokuon doubles the following consonant and the
+// long vowel mark is dropped.
+func romanizeKana(runes []rune) (string, int) {
+	h := toHiragana(runes[0])
+	switch h {
+	case 'ー':
+		return "", 1
+	case 'っ':
+		if len(runes) > 1 && isKana(runes[1]) {
+			next, n := romanizeKana(runes[1:])
+			if strings.HasPrefix(next, "ch") {
+				return "t" + next, n + 1
+			}
+			if next != "" && strings.IndexByte("aeiou", next[0]) < 0 {
+				return next[:1] + next, n + 1
+			}
+			return next, n + 1
+		}
+		return "", 1
+	}
+	base := kanaRomaji[h]
+	if len(runes) < 2 || smallKana[h] || !smallKana[toHiragana(runes[1])] {
+		return base, 1
+	}
+	small := kanaRomaji[toHiragana(runes[1])]
+	stem := base[:len(base)-1]
+	switch {
+	case small[0] == 'y' && strings.HasSuffix(base, "i") && stem != "":
+		// shi + ya is sha, ki + ya is kya
+		if strings.HasSuffix(stem, "sh") || strings.HasSuffix(stem, "ch") || stem == "j" {
+			return stem + small[1:], 2
+		}
+		return stem + small, 2
+	case small[0] == 'y':
+		return base + small, 2
+	case base == "u":
+		// wi, we and wo
+		return "w" + small, 2
+	}
+	return stem + small, 2
+}
+
+var (
+	hangulInitials = []string{"g", "kk", "n", "d", "tt", "r", "m", "b", "pp", "s", "ss", "", "j", "jj", "ch", "k", "t", "p", "h"}
+	hangulVowels   = []string{"a", "ae", "ya", "yae", "eo", "e", "yeo", "ye", "o", "wa", "wae", "oe", "yo", "u", "wo", "we", "wi", "yu", "eu", "ui", "i"}
+	hangulFinals   = []string{"", "k", "k", "k", "n", "n", "n", "t", "l", "k", "m", "p", "l", "l", "p", "l", "m", "p", "p", "t", "t", "ng", "t", "t", "k", "t", "p", "t"}
+)
+
+func isHangul(r rune) bool {
+	return r >= 0xAC00 && r <= 0xD7A3
+}
+
+// romanizeHangul romanizes a hangul syllable following the Revised
+// Romanization of Korean, without the sound changes between syllables
+func romanizeHangul(r rune) string {
+	s := int(r - 0xAC00)
+	return hangulInitials[s/(21*28)] + hangulVowels[s%(21*28)/28] + hangulFinals[s%28]
+}
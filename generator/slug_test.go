@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		text string
+		cjk  string
+		want string
+	}{
+		{"Hello, World!", slugKeepCJK, "hello-world"},
+		{"Crème Brûlée", slugKeepCJK, "creme-brulee"},
+		{"Straße", slugKeepCJK, "strasse"},
+		{"こんにちは", slugKeepCJK, "こんにちは"},
+		{"こんにちは", slugRomanizeCJK, "konnichiha"},
+		{"한국어", slugRomanizeCJK, "hangukeo"},
+		{"キャッシュ", slugRomanizeCJK, "kyasshu"},
+	}
+	for _, test := range tests {
+		if got := slugify(test.text, test.cjk); got != test.want {
+			t.Errorf("slugify(%q, %q) = %q, want %q", test.text, test.cjk, got, test.want)
+		}
+	}
+	if got := slugify("🎉", slugKeepCJK); len(got) != 8 {
+		t.Errorf("slug of a text without letters is %q, want a hash of 8 characters", got)
+	}
+}
+
+func TestSlugCJKModesDontInterfere(t *testing.T) {
+	anchorsOf := func(cjk string) string {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader("<h2>こんにちは</h2>"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := newHeadingAnchors(AnchorOptions{}, cjk)(&Post{}, doc); err != nil {
+			t.Fatal(err)
+		}
+		return doc.Find("h2").AttrOr("id", "")
+	}
+	keep := SitePaths{CJK: slugKeepCJK}
+	romanize := SitePaths{CJK: slugRomanizeCJK}
+	for i := 0; i < 2; i++ {
+		if got := anchorsOf(slugRomanizeCJK); got != "konnichiha" {
+			t.Errorf("romanized heading id is %q", got)
+		}
+		if got := anchorsOf(slugKeepCJK); got != "こんにちは" {
+			t.Errorf("kept heading id is %q", got)
+		}
+		if got := romanize.tagLink("ひらがな"); got != "/tags/hiragana/" {
+			t.Errorf("romanized tag link is %q", got)
+		}
+		if got := keep.tagLink("ひらがな"); got != "/tags/ひらがな/" {
+			t.Errorf("kept tag link is %q", got)
+		}
+	}
+}
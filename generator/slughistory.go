@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// slugHistoryFile is the file next to post.md the paths of a post were
+// recorded in before the history moved to a single file, it is read once to
+// seed the history of posts which don't have one yet
+const slugHistoryFile = ".slughistory"
+
+// slugHistory are the names the posts had, by the path of their markdown file
+// relative to the source root, the last name is the current one. The source
+// root is cloned fresh for every build, so the history is kept in a file
+// outside of it.
+type slugHistory struct {
+	file    string
+	names   map[string][]string
+	changed bool
+}
+
+// readSlugHistory reads the slug history from file, a missing file is an
+// empty history
+func readSlugHistory(file string) (*slugHistory, error) {
+	history := &slugHistory{file: file, names: map[string][]string{}}
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading slug history %s: %v", file, err)
+	}
+	if err := yaml.Unmarshal(data, &history.names); err != nil {
+		return nil, fmt.Errorf("error parsing slug history %s: %v", file, err)
+	}
+	return history, nil
+}
+
+// getLegacySlugHistoryFile returns the old slug history file of a post, posts
+// of a glob have it next to their markdown file, e.g. my-post.slughistory
+func getLegacySlugHistoryFile(path, filePath string) string {
+	if filepath.Base(filePath) == "post.md" {
+		return filepath.Join(path, slugHistoryFile)
+	}
+	return path + slugHistoryFile
+}
+
+// readLegacySlugHistory returns the names recorded in an old slug history
+// file of a post
+func readLegacySlugHistory(file string) []string {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	names := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.Trim(strings.TrimSpace(line), "/"); line != "" {
+			names = append(names, line)
+		}
+	}
+	return names
+}
+
+// update records name for the post of source if it isn't its last recorded
+// one and returns the previous names of the post, legacy is the old slug
+// history file of the post. raw is the name of the post before it was
+// slugified, posts without any history start with it.
+func (h *slugHistory) update(source, legacy, raw, name string) []string {
+	names, ok := h.names[source]
+	if !ok {
+		names = readLegacySlugHistory(legacy)
+	}
+	if len(names) == 0 && raw != name {
+		names = []string{raw}
+	}
+	if len(names) == 0 || names[len(names)-1] != name {
+		if len(names) > 0 {
+			fmt.Printf("\tRecording the old slug %s of %s in %s\n", names[len(names)-1], name, h.file)
+		}
+		names = append(names, name)
+		h.changed = true
+	}
+	h.names[source] = names
+	seen := map[string]bool{name: true}
+	old := []string{}
+	for _, n := range names {
+		if !seen[n] {
+			seen[n] = true
+			old = append(old, n)
+		}
+	}
+	return old
+}
+
+// save writes the history back to its file if it changed
+func (h *slugHistory) save(mode os.FileMode) error {
+	if !h.changed {
+		return nil
+	}
+	sources := make([]string, 0, len(h.names))
+	for source := range h.names {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	sorted := yaml.MapSlice{}
+	for _, source := range sources {
+		sorted = append(sorted, yaml.MapItem{Key: source, Value: h.names[source]})
+	}
+	data, err := yaml.Marshal(sorted)
+	if err != nil {
+		return fmt.Errorf("error writing slug history %s: %v", h.file, err)
+	}
+	if err := writeTextFile(h.file, data, mode); err != nil {
+		return err
+	}
+	h.changed = false
+	return nil
+}
@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestSlugHistorySurvivesRebuilds(t *testing.T) {
+	historyFile := filepath.Join(t.TempDir(), "slughistory.yml")
+	cfg := newTestConfig(t, func(cfg *config.Config) {
+		cfg.Generator.SlugHistory = true
+		cfg.Generator.SlugHistoryFile = historyFile
+	})
+	site := newTestSite(t, cfg, map[string]string{
+		"post/post.md": testPost("Post", "01.02.2021", "Text.", "slug: first-name"),
+	}).build()
+	if !site.exists("first-name/index.html") {
+		t.Fatal("post wasn't generated at its slug")
+	}
+	// the source root is cloned fresh for every build
+	if err := os.RemoveAll(site.Root); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFiles(t, site.Root, map[string]string{
+		"post/post.md": testPost("Post", "01.02.2021", "Text.", "slug: second-name"),
+	})
+	site.build()
+	if !site.exists("second-name/index.html") {
+		t.Fatal("post wasn't generated at its new slug")
+	}
+	stub := site.read("first-name/index.html")
+	if !strings.Contains(stub, "https://example.com/second-name/") {
+		t.Errorf("old slug doesn't redirect to the post:\n%s", stub)
+	}
+	history, err := readSlugHistory(historyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(history.names["post/post.md"], " "); got != "first-name second-name" {
+		t.Errorf("recorded slugs are %s", got)
+	}
+}
+
+func TestSlugHistoryReadsLegacyFiles(t *testing.T) {
+	dir := t.TempDir()
+	legacy := filepath.Join(dir, slugHistoryFile)
+	writeTestFiles(t, dir, map[string]string{slugHistoryFile: "/old/\nnewer\n"})
+	history := &slugHistory{file: filepath.Join(dir, "history.yml"), names: map[string][]string{}}
+	old := history.update("post/post.md", legacy, "current", "current")
+	if strings.Join(old, " ") != "old newer" {
+		t.Errorf("old slugs are %v", old)
+	}
+	if !history.changed {
+		t.Error("history didn't change")
+	}
+	if old := history.update("post/post.md", legacy, "current", "current"); strings.Join(old, " ") != "old newer" {
+		t.Errorf("old slugs of the second update are %v", old)
+	}
+}
+
+func TestSlugHistoryStartsWithUnsluggedName(t *testing.T) {
+	history := &slugHistory{file: filepath.Join(t.TempDir(), "history.yml"), names: map[string][]string{}}
+	if old := history.update("Ünïcode/post.md", "", "Ünïcode", "unicode"); strings.Join(old, " ") != "Ünïcode" {
+		t.Errorf("old slugs are %v", old)
+	}
+	if got := strings.Join(history.names["Ünïcode/post.md"], " "); got != "Ünïcode unicode" {
+		t.Errorf("recorded slugs are %s", got)
+	}
+}
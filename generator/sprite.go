@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"sync"
+)
+
+// svgSprite inlines the symbols of an SVG sprite, the sprite is parsed once
+// and shared by all pages
+type svgSprite struct {
+	path    string
+	once    sync.Once
+	symbols map[string]template.HTML
+	err     error
+	// warned are the names which were reported, every page using an unknown
+	// symbol would report it again otherwise
+	mu     sync.Mutex
+	warned map[string]bool
+}
+
+type spriteSymbol struct {
+	ID      string `xml:"id,attr"`
+	ViewBox string `xml:"viewBox,attr"`
+	Inner   string `xml:",innerxml"`
+}
+
+type spriteFile struct {
+	Symbols     []spriteSymbol `xml:"symbol"`
+	DefsSymbols []spriteSymbol `xml:"defs>symbol"`
+}
+
+func newSVGSprite(path string) *svgSprite {
+	return &svgSprite{path: path}
+}
+
+func (s *svgSprite) load() {
+	if s.path == "" {
+		s.err = fmt.Errorf("no sprite configured")
+		return
+	}
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		s.err = fmt.Errorf("error reading sprite %s: %v", s.path, err)
+		return
+	}
+	sprite := spriteFile{}
+	if err := xml.Unmarshal(data, &sprite); err != nil {
+		s.err = fmt.Errorf("error parsing sprite %s: %v", s.path, err)
+		return
+	}
+	s.symbols = map[string]template.HTML{}
+	for _, symbol := range append(sprite.Symbols, sprite.DefsSymbols...) {
+		viewBox := ""
+		if symbol.ViewBox != "" {
+			viewBox = fmt.Sprintf(` viewBox="%s"`, template.HTMLEscapeString(symbol.ViewBox))
+		}
+		s.symbols[symbol.ID] = template.HTML(fmt.Sprintf(`<svg class="icon icon-%s"%s aria-hidden="true">%s</svg>`,
+			template.HTMLEscapeString(symbol.ID), viewBox, symbol.Inner))
+	}
+}
+
+// Symbol returns the named symbol as inline <svg>, unknown symbols are
+// reported once and render nothing
+func (s *svgSprite) Symbol(name string) template.HTML {
+	s.once.Do(s.load)
+	if s.err != nil {
+		if s.firstWarning(name) {
+			fmt.Printf("\tWarning: can't inline svg %s: %v\n", name, s.err)
+		}
+		return ""
+	}
+	symbol, ok := s.symbols[name]
+	if !ok {
+		if s.firstWarning(name) {
+			fmt.Printf("\tWarning: svg symbol %s not found in %s\n", name, s.path)
+		}
+		return ""
+	}
+	return symbol
+}
+
+// firstWarning reports if name wasn't reported yet and records it
+func (s *svgSprite) firstWarning(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.warned[name] {
+		return false
+	}
+	if s.warned == nil {
+		s.warned = map[string]bool{}
+	}
+	s.warned[name] = true
+	return true
+}
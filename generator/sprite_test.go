@@ -0,0 +1,41 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSVGSpriteSymbols(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"sprite.svg": `<svg xmlns="http://www.w3.org/2000/svg"><defs><symbol id="github" viewBox="0 0 16 16"><path d="M0 0h16v16z"/></symbol></defs><symbol id="rss"><circle r="2"/></symbol></svg>`})
+	sprite := newSVGSprite(filepath.Join(dir, "sprite.svg"))
+	if got := string(sprite.Symbol("github")); got != `<svg class="icon icon-github" viewBox="0 0 16 16" aria-hidden="true"><path d="M0 0h16v16z"/></svg>` {
+		t.Errorf("got github symbol %s", got)
+	}
+	if got := string(sprite.Symbol("rss")); !strings.HasPrefix(got, `<svg class="icon icon-rss" aria-hidden="true">`) {
+		t.Errorf("got rss symbol %s", got)
+	}
+	output := captureStdout(t, func() {
+		for i := 0; i < 3; i++ {
+			if got := sprite.Symbol("twitter"); got != "" {
+				t.Errorf("unknown symbol rendered %s", got)
+			}
+			sprite.Symbol("mastodon")
+		}
+	})
+	if strings.Count(output, "svg symbol twitter not found") != 1 || strings.Count(output, "svg symbol mastodon not found") != 1 {
+		t.Errorf("unknown symbols aren't reported once:\n%s", output)
+	}
+}
+
+func TestSVGSpriteMissing(t *testing.T) {
+	sprite := newSVGSprite("")
+	output := captureStdout(t, func() {
+		sprite.Symbol("github")
+		sprite.Symbol("github")
+	})
+	if strings.Count(output, "can't inline svg github") != 1 {
+		t.Errorf("missing sprite isn't reported once:\n%s", output)
+	}
+}
@@ -29,20 +29,21 @@ func (g *StaticsGenerator) Generate() error {
 	fileToDestination := g.Config.FileToDestination
 	templateToFile := g.Config.TemplateToFile
 	t := g.Config.Template
+	writer := g.Config.Writer
 	for k, v := range fileToDestination {
-		if err := createFolderIfNotExist(getFolder(v)); err != nil {
+		if err := createFolderIfNotExist(getFolder(v), writer.DirMode); err != nil {
 			return err
 		}
-		if err := copyFile(k, v); err != nil {
+		if err := copyFile(k, v, writer.FileMode); err != nil {
 			return err
 		}
 	}
 	for k, v := range templateToFile {
-		if err := createFolderIfNotExist(getFolder(v)); err != nil {
+		if err := createFolderIfNotExist(getFolder(v), writer.DirMode); err != nil {
 			return err
 		}
 		content, err := ioutil.ReadFile(k)
-		if err := g.Config.Writer.WriteIndexHTML(getFolder(v), getTitle(k), getTitle(k), template.HTML(content),t); err != nil {
+		if err := g.Config.Writer.WriteIndexHTML(getFolder(v), getTitle(k), getTitle(k), template.HTML(content), t); err != nil {
 			return err
 		}
 		if err != nil {
@@ -52,7 +53,7 @@ func (g *StaticsGenerator) Generate() error {
 		if err != nil {
 			fmt.Println("sssssssssssssss", err)
 		}
-		t2, err := template.New("p").Parse(string(content))
+		t2, err := template.New("p").Funcs(g.Config.Writer.Funcs).Parse(string(content))
 		if err != nil {
 			return err
 		}
@@ -64,10 +65,10 @@ func (g *StaticsGenerator) Generate() error {
 	return nil
 }
 
-func createFolderIfNotExist(path string) error {
+func createFolderIfNotExist(path string, mode os.FileMode) error {
 	if _, err := os.Stat(path); err != nil {
 		if os.IsNotExist(err) {
-			if err = os.MkdirAll(path, os.ModePerm); err != nil {
+			if err = os.MkdirAll(path, mode); err != nil {
 				return fmt.Errorf("error creating directory %s: %v", path, err)
 			}
 		} else {
@@ -77,13 +78,13 @@ func createFolderIfNotExist(path string) error {
 	return nil
 }
 
-func copyFile(src, dst string) (err error) {
+func copyFile(src, dst string, mode os.FileMode) (err error) {
 	in, err := os.Open(src)
 	if err != nil {
 		return fmt.Errorf("error reading file %s: %v", src, err)
 	}
 	defer in.Close()
-	out, err := os.Create(dst)
+	out, err := createFile(dst, mode)
 	if err != nil {
 		return fmt.Errorf("error creating file %s: %v", dst, err)
 	}
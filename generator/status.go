@@ -0,0 +1,59 @@
+package generator
+
+import "fmt"
+
+// publish states of a post, posts without a status are published
+const (
+	statusDraft     = "draft"
+	statusReview    = "review"
+	statusPublished = "published"
+)
+
+// checkStatus validates the publish status of a post
+func checkStatus(status string) error {
+	switch status {
+	case "", statusDraft, statusReview, statusPublished:
+		return nil
+	}
+	return fmt.Errorf("unknown status %q, use %s, %s or %s", status, statusDraft, statusReview, statusPublished)
+}
+
+// filterDrafts removes draft posts, unless includeDrafts is set
+func filterDrafts(posts []*Post, includeDrafts bool) []*Post {
+	result := []*Post{}
+	for _, post := range posts {
+		if post.Meta.Status == statusDraft && !includeDrafts {
+			fmt.Printf("Skipping draft %s\n", post.Name)
+			continue
+		}
+		result = append(result, post)
+	}
+	return result
+}
+
+// isReview reports if the post is only generated into the preview destination
+func isReview(post *Post) bool {
+	return post.Meta.Status == statusReview
+}
+
+// getPublishedPosts returns the posts generated to the live destination
+func getPublishedPosts(posts []*Post) []*Post {
+	result := []*Post{}
+	for _, post := range posts {
+		if !isReview(post) {
+			result = append(result, post)
+		}
+	}
+	return result
+}
+
+// getReviewPosts returns the posts in review
+func getReviewPosts(posts []*Post) []*Post {
+	result := []*Post{}
+	for _, post := range posts {
+		if isReview(post) {
+			result = append(result, post)
+		}
+	}
+	return result
+}
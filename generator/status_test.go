@@ -0,0 +1,52 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestStatusRouting(t *testing.T) {
+	for _, includeDrafts := range []bool{false, true} {
+		cfg := newTestConfig(t, func(cfg *config.Config) {
+			cfg.Generator.IncludeDrafts = includeDrafts
+			cfg.Blog.Statics.Files = append(cfg.Blog.Statics.Files, struct {
+				Src  string
+				Dest string
+			}{filepath.Join("static", "css", "vec.css"), "static/css/vec.css"})
+		})
+		site := newTestSite(t, cfg, map[string]string{
+			"published/post.md": testPost("Published Post", "01.02.2021", "Published.", "status: published"),
+			"default/post.md":   testPost("Default Post", "02.02.2021", "Default."),
+			"draft/post.md":     testPost("Draft Post", "03.02.2021", "Draft.", "status: draft"),
+			"review/post.md":    testPost("Review Post", "04.02.2021", "Review.", "status: review"),
+		}).build()
+		preview := func(name string) bool {
+			_, err := os.Stat(filepath.Join(cfg.Generator.Preview, filepath.FromSlash(name)))
+			return err == nil
+		}
+		index := site.read("blog/index.html")
+		for _, name := range []string{"published", "default"} {
+			if !site.exists(name+"/index.html") || preview(name+"/index.html") {
+				t.Errorf("%s post isn't only generated into the live site", name)
+			}
+		}
+		if site.exists("draft/index.html") != includeDrafts {
+			t.Errorf("draft generated is %v with includedrafts %v", !includeDrafts, includeDrafts)
+		}
+		if site.exists("review/index.html") || strings.Contains(index, "Review Post") {
+			t.Error("post in review ended up in the live site")
+		}
+		if !preview("review/index.html") {
+			t.Error("post in review wasn't generated into the preview")
+		}
+		for _, name := range []string{"static/css/vec.css", "css/syntax.css"} {
+			if !preview(name) {
+				t.Errorf("%s wasn't copied into the preview", name)
+			}
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// SyntaxTheme maps the classes of the syntax highlighter to css declarations
+type SyntaxTheme map[string]string
+
+// syntaxClasses are the classes used by syntaxhighlight.DefaultHTMLConfig and
+// the lines of diffs
+var syntaxClasses = []string{"pln", "str", "kwd", "com", "typ", "lit", "pun", "tag", "htm", "atn", "atv", "dec", "diff-file", "diff-add", "diff-del", "diff-hunk"}
+
+// syntaxThemes are the built-in themes, the "code" key styles the code block
+var syntaxThemes = map[string]SyntaxTheme{
+	"light": {
+		"code":      "background: #f8f8f8; color: #333333;",
+		"pln":       "color: #333333;",
+		"str":       "color: #df5000;",
+		"kwd":       "color: #a71d5d;",
+		"com":       "color: #969896; font-style: italic;",
+		"typ":       "color: #0086b3;",
+		"lit":       "color: #0086b3;",
+		"pun":       "color: #333333;",
+		"tag":       "color: #63a35c;",
+		"htm":       "color: #63a35c;",
+		"atn":       "color: #795da3;",
+		"atv":       "color: #df5000;",
+		"dec":       "color: #0086b3;",
+		"diff-file": "color: #333333; font-weight: bold;",
+		"diff-add":  "background: #e6ffed; color: #22863a;",
+		"diff-del":  "background: #ffeef0; color: #b31d28;",
+		"diff-hunk": "color: #6f42c1;",
+	},
+	"dark": {
+		"code":      "background: #282c34; color: #abb2bf;",
+		"pln":       "color: #abb2bf;",
+		"str":       "color: #98c379;",
+		"kwd":       "color: #c678dd;",
+		"com":       "color: #5c6370; font-style: italic;",
+		"typ":       "color: #e5c07b;",
+		"lit":       "color: #d19a66;",
+		"pun":       "color: #abb2bf;",
+		"tag":       "color: #e06c75;",
+		"htm":       "color: #e06c75;",
+		"atn":       "color: #d19a66;",
+		"atv":       "color: #98c379;",
+		"dec":       "color: #d19a66;",
+		"diff-file": "color: #abb2bf; font-weight: bold;",
+		"diff-add":  "background: #1f3b2a; color: #98c379;",
+		"diff-del":  "background: #3f2226; color: #e06c75;",
+		"diff-hunk": "color: #c678dd;",
+	},
+}
+
+// syntaxSelector is the element containing highlighted code
+const syntaxSelector = `code[class*="language-"]`
+
+// darkSelector enables the dark theme in the "selector" dark mode
+const darkSelector = `[data-theme=dark]`
+
+// SyntaxCSSGenerator object
+type SyntaxCSSGenerator struct {
+	Config *SyntaxCSSConfig
+}
+
+// SyntaxCSSConfig holds the configuration for the syntax stylesheet
+type SyntaxCSSConfig struct {
+	Theme       string
+	DarkTheme   string
+	DarkMode    string
+	Themes      map[string]SyntaxTheme
+	Destination string
+	DirMode     os.FileMode
+	FileMode    os.FileMode
+}
+
+// Generate writes the stylesheet of the configured theme to css/syntax.css.
+// If a dark theme is configured its rules are applied either for
+// prefers-color-scheme: dark ("media") or below [data-theme=dark] ("selector").
+func (g *SyntaxCSSGenerator) Generate() error {
+	fmt.Println("\tGenerating Syntax CSS...")
+	css, err := g.getCSS()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(g.Config.Destination, "css")
+	if err := createFolderIfNotExist(path, g.Config.DirMode); err != nil {
+		return err
+	}
+	filePath := filepath.Join(path, "syntax.css")
+	f, err := createFile(filePath, g.Config.FileMode)
+	if err != nil {
+		return fmt.Errorf("error creating file %s: %v", filePath, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(css); err != nil {
+		return fmt.Errorf("error writing file %s: %v", filePath, err)
+	}
+	fmt.Println("\tFinished generating Syntax CSS...")
+	return nil
+}
+
+// getCSS returns the stylesheet of the configured themes
+func (g *SyntaxCSSGenerator) getCSS() ([]byte, error) {
+	theme, err := g.getTheme(g.Config.Theme)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.Buffer{}
+	writeSyntaxRules(&buf, theme, "", "")
+	if g.Config.DarkTheme != "" {
+		dark, err := g.getTheme(g.Config.DarkTheme)
+		if err != nil {
+			return nil, err
+		}
+		if g.Config.DarkMode == "selector" {
+			writeSyntaxRules(&buf, dark, "", darkSelector+" ")
+		} else {
+			buf.WriteString("@media (prefers-color-scheme: dark) {\n")
+			writeSyntaxRules(&buf, dark, "    ", "")
+			buf.WriteString("}\n")
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// getTheme returns a theme of the config or a built-in one
+func (g *SyntaxCSSGenerator) getTheme(name string) (SyntaxTheme, error) {
+	if theme, ok := g.Config.Themes[name]; ok {
+		return theme, nil
+	}
+	if theme, ok := syntaxThemes[name]; ok {
+		return theme, nil
+	}
+	return nil, fmt.Errorf("unknown syntax theme %s, available themes: %v", name, g.getThemeNames())
+}
+
+func (g *SyntaxCSSGenerator) getThemeNames() []string {
+	names := []string{}
+	for name := range syntaxThemes {
+		names = append(names, name)
+	}
+	for name := range g.Config.Themes {
+		if _, ok := syntaxThemes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func writeSyntaxRules(buf *bytes.Buffer, theme SyntaxTheme, indent, prefix string) {
+	if decl, ok := theme["code"]; ok {
+		fmt.Fprintf(buf, "%s%s%s { %s }\n", indent, prefix, syntaxSelector, decl)
+	}
+	for _, class := range syntaxClasses {
+		if decl, ok := theme[class]; ok {
+			fmt.Fprintf(buf, "%s%s%s .%s { %s }\n", indent, prefix, syntaxSelector, class, decl)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// generateSyntaxCSS writes the syntax stylesheet of cfg and returns it
+func generateSyntaxCSS(t *testing.T, cfg SyntaxCSSConfig) (string, error) {
+	t.Helper()
+	cfg.Destination = t.TempDir()
+	cfg.DirMode, cfg.FileMode = 0755, 0644
+	if err := (&SyntaxCSSGenerator{&cfg}).Generate(); err != nil {
+		return "", err
+	}
+	css, err := ioutil.ReadFile(filepath.Join(cfg.Destination, "css", "syntax.css"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(css), nil
+}
+
+func TestSyntaxThemeCSS(t *testing.T) {
+	tests := []struct {
+		theme string
+		rules []string
+	}{
+		{"light", []string{
+			`code[class*="language-"] { background: #f8f8f8; color: #333333; }`,
+			`code[class*="language-"] .kwd { color: #a71d5d; }`,
+			`code[class*="language-"] .com { color: #969896; font-style: italic; }`,
+		}},
+		{"dark", []string{
+			`code[class*="language-"] .kwd { color: #c678dd; }`,
+			`code[class*="language-"] .str { color: #98c379; }`,
+		}},
+		{"mine", []string{
+			`code[class*="language-"] .kwd { color: red; }`,
+		}},
+	}
+	themes := map[string]SyntaxTheme{"mine": {"kwd": "color: red;"}}
+	for _, test := range tests {
+		css, err := generateSyntaxCSS(t, SyntaxCSSConfig{Theme: test.theme, Themes: themes})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, rule := range test.rules {
+			if !strings.Contains(css, rule) {
+				t.Errorf("css of theme %s is missing %s", test.theme, rule)
+			}
+		}
+	}
+	if _, err := generateSyntaxCSS(t, SyntaxCSSConfig{Theme: "missing"}); err == nil || !strings.Contains(err.Error(), "[dark light]") {
+		t.Errorf("unknown theme gives error %v", err)
+	}
+}
+
+func TestHighlightedCodeUsesThemeClasses(t *testing.T) {
+	post, err := RenderPost([]byte(testPost("Code", "01.02.2021", "```go\nfunc main() {}\n```")), newTestConfig(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(post.HTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	known := map[string]bool{}
+	for _, class := range syntaxClasses {
+		known[class] = true
+	}
+	spans := doc.Find(syntaxSelector + " span")
+	if spans.Length() == 0 {
+		t.Fatal("code wasn't highlighted")
+	}
+	spans.Each(func(i int, span *goquery.Selection) {
+		if class := span.AttrOr("class", ""); !known[class] {
+			t.Errorf("highlighted code uses the class %q, which the themes don't style", class)
+		}
+	})
+}
+
+func TestSyntaxDarkModeCSS(t *testing.T) {
+	light := `code[class*="language-"] .kwd { color: #a71d5d; }`
+	media, err := generateSyntaxCSS(t, SyntaxCSSConfig{Theme: "light", DarkTheme: "dark", DarkMode: "media"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := "@media (prefers-color-scheme: dark) {\n"
+	start := strings.Index(media, block)
+	if start < 0 || !strings.HasSuffix(media, "}\n") {
+		t.Fatalf("css has no prefers-color-scheme block:\n%s", media)
+	}
+	if !strings.Contains(media[:start], light) {
+		t.Error("default rules aren't outside of the dark block")
+	}
+	if !strings.Contains(media[start:], `    code[class*="language-"] .kwd { color: #c678dd; }`) {
+		t.Error("dark block doesn't override the keyword color")
+	}
+
+	selector, err := generateSyntaxCSS(t, SyntaxCSSConfig{Theme: "light", DarkTheme: "dark", DarkMode: "selector"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(selector, "@media") || !strings.Contains(selector, light) {
+		t.Error("selector mode doesn't keep just the default rules unscoped")
+	}
+	if !strings.Contains(selector, `[data-theme=dark] code[class*="language-"] .kwd { color: #c678dd; }`) {
+		t.Error("selector mode doesn't scope the dark rules")
+	}
+
+	custom, err := generateSyntaxCSS(t, SyntaxCSSConfig{Theme: "light", DarkTheme: "night", Themes: map[string]SyntaxTheme{"night": {"kwd": "color: navy;"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(custom, `    code[class*="language-"] .kwd { color: navy; }`) {
+		t.Error("configured dark palette isn't used")
+	}
+}
@@ -0,0 +1,43 @@
+package generator
+
+import (
+	"fmt"
+	"html/template"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// defaultTableClass is the class of the wrappers of responsive tables if none
+// is configured
+const defaultTableClass = "table-responsive"
+
+// getTableClass returns the class of the table wrappers, empty if tables
+// aren't responsive
+func getTableClass(responsive bool, class string) string {
+	if !responsive {
+		return ""
+	}
+	if class == "" {
+		return defaultTableClass
+	}
+	return class
+}
+
+// newTableWrappers returns the transform wrapping the tables of posts in a div
+// with class, so they can scroll horizontally on narrow screens. Tables whose
+// parent already has the class are left alone, an empty class disables it.
+func newTableWrappers(class string) Transform {
+	return func(post *Post, doc *goquery.Document) error {
+		if class == "" {
+			return nil
+		}
+		wrapper := fmt.Sprintf(`<div class="%s"></div>`, template.HTMLEscapeString(class))
+		doc.Find("table").Each(func(i int, s *goquery.Selection) {
+			if s.Parent().HasClass(class) {
+				return
+			}
+			s.WrapHtml(wrapper)
+		})
+		return nil
+	}
+}
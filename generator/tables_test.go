@@ -0,0 +1,39 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTableWrappedOnce(t *testing.T) {
+	transform := newTableWrappers("scroll")
+	html := `<table><tr><td>1</td></tr></table><div class="scroll"><table><tr><td>2</td></tr></table></div>`
+	once := applyTestTransform(t, transform, html)
+	want := `<div class="scroll"><table><tbody><tr><td>1</td></tr></tbody></table></div><div class="scroll"><table><tbody><tr><td>2</td></tr></tbody></table></div>`
+	if once != want {
+		t.Errorf("got\n%s\nwant\n%s", once, want)
+	}
+	if twice := applyTestTransform(t, transform, once); twice != once {
+		t.Errorf("wrapping twice changed the tables:\n%s", twice)
+	}
+	if got := applyTestTransform(t, newTableWrappers(""), html); strings.Count(got, `class="scroll"`) != 1 {
+		t.Errorf("tables were wrapped without a class:\n%s", got)
+	}
+}
+
+func TestGetTableClass(t *testing.T) {
+	tests := []struct {
+		responsive bool
+		class      string
+		want       string
+	}{
+		{false, "scroll", ""},
+		{true, "", defaultTableClass},
+		{true, "scroll", "scroll"},
+	}
+	for _, test := range tests {
+		if got := getTableClass(test.responsive, test.class); got != test.want {
+			t.Errorf("getTableClass(%v, %q) = %q, want %q", test.responsive, test.class, got, test.want)
+		}
+	}
+}
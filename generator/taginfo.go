@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// tagInfoFile holds the display names, descriptions and images of tags in the
+// root of the blog repository
+const tagInfoFile = "tags.yml"
+
+// TagInfo is the configuration of a tag page, Name is shown instead of the
+// tag and Image is a path below the blog root or an absolute URL
+type TagInfo struct {
+	Name        string
+	Description string
+	Image       string
+}
+
+// readTagInfo reads the tags.yml in root by lowercase tag, if any, like the
+// tags of posts are grouped
+func readTagInfo(root string) (map[string]*TagInfo, error) {
+	if root == "" {
+		return nil, nil
+	}
+	filePath := filepath.Join(root, tagInfoFile)
+	raw, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error while reading file %s: %v", filePath, err)
+	}
+	configured := map[string]*TagInfo{}
+	if err := yaml.Unmarshal(raw, &configured); err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", tagInfoFile, err)
+	}
+	info := make(map[string]*TagInfo, len(configured))
+	for tag, tagInfo := range configured {
+		key := strings.ToLower(tag)
+		if _, ok := info[key]; ok {
+			return nil, fmt.Errorf("error reading %s: tag %s is configured more than once", tagInfoFile, key)
+		}
+		info[key] = tagInfo
+	}
+	return info, nil
+}
+
+// reportUnknownTagInfo warns about the configured tags no listed post has,
+// which are mostly typos
+func reportUnknownTagInfo(info map[string]*TagInfo, tagPostsMap map[string][]*Post) {
+	for _, tag := range getUnknownTagInfo(info, tagPostsMap) {
+		fmt.Printf("\tWarning: tag %s of %s matches no tag of a post\n", tag, tagInfoFile)
+	}
+}
+
+// getUnknownTagInfo returns the sorted configured tags no post of
+// tagPostsMap has
+func getUnknownTagInfo(info map[string]*TagInfo, tagPostsMap map[string][]*Post) []string {
+	tags := []string{}
+	for tag := range info {
+		if _, ok := tagPostsMap[tag]; !ok {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// getTagInfo returns the configuration of tag, tags which aren't configured
+// or have no name are shown as they are
+func getTagInfo(tag string, info map[string]*TagInfo) TagInfo {
+	result := TagInfo{Name: tag}
+	if configured := info[tag]; configured != nil {
+		result = *configured
+		if result.Name == "" {
+			result.Name = tag
+		}
+	}
+	return result
+}
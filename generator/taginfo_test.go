@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadTagInfoLowercasesTags(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{tagInfoFile: "Go:\n    name: 'Go'\n    description: 'About Go.'\nWeb:\n    image: '/web.png'\n"})
+	info, err := readTagInfo(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := getTagInfo("go", info); got.Name != "Go" || got.Description != "About Go." {
+		t.Errorf("got %+v for go", got)
+	}
+	if got := getTagInfo("web", info); got.Name != "web" || got.Image != "/web.png" {
+		t.Errorf("got %+v for web", got)
+	}
+}
+
+func TestReadTagInfoDuplicates(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(t, root, map[string]string{tagInfoFile: "Go:\n    name: 'Go'\ngo:\n    name: 'golang'\n"})
+	if _, err := readTagInfo(root); err == nil {
+		t.Error("expected an error for a tag configured twice")
+	}
+}
+
+func TestTagInfoOnTagPage(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		tagInfoFile:    "Go:\n    name: 'The Go Language'\n    description: 'Posts about Go.'\n",
+		"post/post.md": testPost("Post", "01.02.2021", "Text.", "short: a post", "tags: [go]"),
+	}).build()
+	page := site.read("tags/go/index.html")
+	if !strings.Contains(page, "The Go Language") || !strings.Contains(page, "Posts about Go.") {
+		t.Errorf("tag page doesn't use the tag info:\n%s", page)
+	}
+}
+
+func TestGetUnknownTagInfo(t *testing.T) {
+	info := map[string]*TagInfo{"go": {}, "golnag": {}, "rust": {}}
+	tagPostsMap := map[string][]*Post{"go": {{}}, "web": {{}}}
+	if got := strings.Join(getUnknownTagInfo(info, tagPostsMap), " "); got != "golnag rust" {
+		t.Errorf("got unknown tags %s, want golnag rust", got)
+	}
+}
@@ -16,6 +16,20 @@ type Tag struct {
 	Count int
 }
 
+// PostLink is a link to a post
+type PostLink struct {
+	Title string
+	Link  string
+}
+
+// TagNavigation links to the neighbours of a post within one of its tags, Prev
+// is the older and Next the newer post
+type TagNavigation struct {
+	Tag  *Tag
+	Prev *PostLink
+	Next *PostLink
+}
+
 // TagsGenerator object
 type TagsGenerator struct {
 	Config *TagsConfig
@@ -23,11 +37,17 @@ type TagsGenerator struct {
 
 // TagsConfig holds the tag's config
 type TagsConfig struct {
-	NPG         int
-	TagPostsMap map[string][]*Post
-	Template    *template.Template
-	Destination string
-	Writer      *IndexWriter
+	NPG           int
+	TagPostsMap   map[string][]*Post
+	Template      *template.Template
+	Destination   string
+	Writer        *IndexWriter
+	ExcerptLength int
+	Ellipsis      string
+	Feed          *RSSConfig
+	FeedPosts     int
+	// Info are the names, descriptions and images of the tags from tags.yml
+	Info map[string]*TagInfo
 }
 
 // Generate creates the tags page
@@ -36,17 +56,24 @@ func (g *TagsGenerator) Generate() error {
 	tagPostsMap := g.Config.TagPostsMap
 	t := g.Config.Template
 	destination := g.Config.Destination
-	tagsPath := filepath.Join(destination, "tags")
-	if err := clearAndCreateDestination(tagsPath); err != nil {
+	paths := g.Config.Writer.Paths
+	tagsPath := filepath.Join(destination, paths.tagsDir())
+	if err := clearAndCreateDestination(tagsPath, g.Config.Writer.DirMode); err != nil {
 		return err
 	}
 	if err := generateTagIndex(tagPostsMap, t, tagsPath, g.Config.Writer); err != nil {
 		return err
 	}
 	// 为每一个tag生成一个页面
-	for tag, tagPosts := range tagPostsMap {
-		tagPagePath := filepath.Join(tagsPath, tag)
-		if err := generateTagPage(tag, tagPosts, t, tagPagePath, g.Config.NPG, g.Config.Writer); err != nil {
+	tags := make([]string, 0, len(tagPostsMap))
+	for tag := range tagPostsMap {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		tagPosts := tagPostsMap[tag]
+		tagPagePath := filepath.Join(tagsPath, paths.tagSlug(tag))
+		if err := generateTagPage(tag, tagPosts, t, tagPagePath, g.Config); err != nil {
 			return err
 		}
 	}
@@ -56,16 +83,11 @@ func (g *TagsGenerator) Generate() error {
 
 func generateTagIndex(tagPostsMap map[string][]*Post, t *template.Template, destination string, writer *IndexWriter) error {
 	tagsTemplatePath := filepath.Join("static", "tags.html")
-	tmpl, err := getTemplate(tagsTemplatePath)
+	tmpl, err := getTemplate(tagsTemplatePath, writer.Funcs)
 	if err != nil {
 		return err
 	}
-	tags := []*Tag{}
-	for tag, posts := range tagPostsMap {
-		tags = append(tags, &Tag{Name: tag, Link: getTagLink(tag), Count: len(posts)})
-	}
-	// 排序
-	sort.Sort(ByCountDesc(tags))
+	tags := createTagList(tagPostsMap, writer.Paths)
 	// 生成index.html
 	buf := bytes.Buffer{}
 	if err := tmpl.Execute(&buf, tags); err != nil {
@@ -77,34 +99,105 @@ func generateTagIndex(tagPostsMap map[string][]*Post, t *template.Template, dest
 	return nil
 }
 
-func generateTagPage(tag string, posts []*Post, t *template.Template, destination string, png int, writer *IndexWriter) error {
-	if err := clearAndCreateDestination(destination); err != nil {
+func generateTagPage(tag string, posts []*Post, t *template.Template, destination string, cfg *TagsConfig) error {
+	if err := clearAndCreateDestination(destination, cfg.Writer.DirMode); err != nil {
 		return err
 	}
+	info := getTagInfo(tag, cfg.Info)
 	lg := ListingGenerator{&ListingConfig{
-		NPG:         png,
-		Posts:       posts,
-		Template:    t,
-		Destination: destination,
-		PageTitle:   tag,
-		Writer:      writer,
+		NPG:           cfg.NPG,
+		Posts:         posts,
+		Template:      t,
+		Destination:   destination,
+		PageTitle:     info.Name,
+		Description:   info.Description,
+		Image:         getDefaultImageURL(info.Image, cfg.Writer.BlogURL),
+		Writer:        cfg.Writer,
+		ExcerptLength: cfg.ExcerptLength,
+		Ellipsis:      cfg.Ellipsis,
 	}}
+	if cfg.Feed != nil {
+		feed := newRSSFeedLink(cfg.Feed.BlogURL, cfg.Writer.Paths.tagLink(tag), getTagFeedTitle(tag, cfg.Feed.BlogTitle))
+		lg.Config.Feed = &feed
+	}
 	if err := lg.Generate(); err != nil {
 		return err
 	}
+	if cfg.Feed != nil {
+		if err := generateTagFeed(tag, posts, destination, cfg); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func createTags(tags []string) []*Tag {
-	var result []*Tag
+// generateTagFeed writes the RSS feed of the most recent posts of a tag
+func generateTagFeed(tag string, posts []*Post, destination string, cfg *TagsConfig) error {
+	if cfg.FeedPosts > 0 && len(posts) > cfg.FeedPosts {
+		posts = posts[:cfg.FeedPosts]
+	}
+	feed := *cfg.Feed
+	feed.Posts = posts
+	feed.Destination = destination
+	feed.Title = getTagFeedTitle(tag, cfg.Feed.BlogTitle)
+	feed.Path = cfg.Writer.Paths.tagLink(tag)
+	rg := RSSGenerator{&feed}
+	return rg.Generate()
+}
+
+func getTagFeedTitle(tag, blogTitle string) string {
+	return fmt.Sprintf("%s - %s", tag, blogTitle)
+}
+
+// createTagList returns all tags with their post count, sorted by count and name
+func createTagList(tagPostsMap map[string][]*Post, paths SitePaths) []*Tag {
+	tags := []*Tag{}
+	for tag, posts := range tagPostsMap {
+		tags = append(tags, &Tag{Name: tag, Link: paths.tagLink(tag), Count: len(posts)})
+	}
+	sort.Sort(ByCountDesc(tags))
+	return tags
+}
+
+func createTagCounts(tags []*Tag) map[string]int {
+	result := make(map[string]int, len(tags))
 	for _, tag := range tags {
-		result = append(result, &Tag{Name: tag, Link: getTagLink(tag)})
+		result[tag.Name] = tag.Count
 	}
 	return result
 }
 
-func getTagLink(tag string) string {
-	return fmt.Sprintf("/tags/%s/", strings.ToLower(tag))
+// createTagNavigation sets the navigation within each tag of all posts, the
+// posts of tagPostsMap have to be sorted by date
+func createTagNavigation(posts []*Post, tagPostsMap map[string][]*Post, paths SitePaths) {
+	for _, post := range posts {
+		post.TagNav = nil
+		for _, tag := range post.Meta.Tags {
+			tagPosts := tagPostsMap[strings.ToLower(tag)]
+			for i, p := range tagPosts {
+				if p != post {
+					continue
+				}
+				nav := &TagNavigation{Tag: &Tag{Name: tag, Link: paths.tagLink(tag), Count: len(tagPosts)}}
+				if i > 0 {
+					nav.Next = &PostLink{Title: tagPosts[i-1].Meta.Title, Link: tagPosts[i-1].Path}
+				}
+				if i+1 < len(tagPosts) {
+					nav.Prev = &PostLink{Title: tagPosts[i+1].Meta.Title, Link: tagPosts[i+1].Path}
+				}
+				post.TagNav = append(post.TagNav, nav)
+				break
+			}
+		}
+	}
+}
+
+func createTags(tags []string, paths SitePaths) []*Tag {
+	var result []*Tag
+	for _, tag := range tags {
+		result = append(result, &Tag{Name: tag, Link: paths.tagLink(tag)})
+	}
+	return result
 }
 
 // ByCountDesc sorts the tags
@@ -119,5 +212,8 @@ func (t ByCountDesc) Swap(i, j int) {
 }
 
 func (t ByCountDesc) Less(i, j int) bool {
+	if t[i].Count == t[j].Count {
+		return t[i].Name < t[j].Name
+	}
 	return t[i].Count > t[j].Count
 }
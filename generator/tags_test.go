@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCreateTagListCounts(t *testing.T) {
+	posts := []*Post{
+		{Meta: &Meta{Tags: []string{"go", "web"}}},
+		{Meta: &Meta{Tags: []string{"Go", "css"}}},
+		{Meta: &Meta{Tags: []string{"web", "ai"}}},
+		{Meta: &Meta{Tags: []string{"go"}}},
+	}
+	tagPostsMap := createTagPostsMap(posts)
+	want := []string{"go", "web", "ai", "css"}
+	for i := 0; i < 10; i++ {
+		tags := createTagList(tagPostsMap, SitePaths{})
+		names := []string{}
+		for _, tag := range tags {
+			names = append(names, tag.Name)
+		}
+		if !reflect.DeepEqual(names, want) {
+			t.Fatalf("tags are sorted %v, want %v", names, want)
+		}
+		counts := createTagCounts(tags)
+		if !reflect.DeepEqual(counts, map[string]int{"go": 3, "web": 2, "ai": 1, "css": 1}) {
+			t.Fatalf("tag counts are %v", counts)
+		}
+	}
+}
+
+func TestCreateTagNavigation(t *testing.T) {
+	// newest first, like the loaded posts
+	newPost := func(name string, tags ...string) *Post {
+		return &Post{Path: "/" + name + "/", Meta: &Meta{Title: name, Tags: tags}}
+	}
+	d := newPost("d", "go")
+	c := newPost("c", "go", "web")
+	b := newPost("b", "web")
+	a := newPost("a", "go", "web")
+	posts := []*Post{d, c, b, a}
+	createTagNavigation(posts, createTagPostsMap(posts), SitePaths{})
+
+	link := func(l *PostLink) string {
+		if l == nil {
+			return ""
+		}
+		return l.Link
+	}
+	tests := []struct {
+		post       *Post
+		tag        string
+		prev, next string
+	}{
+		{d, "go", "/c/", ""},
+		{c, "go", "/a/", "/d/"},
+		{c, "web", "/b/", ""},
+		{b, "web", "/a/", "/c/"},
+		{a, "go", "", "/c/"},
+		{a, "web", "", "/b/"},
+	}
+	for _, test := range tests {
+		var nav *TagNavigation
+		for _, n := range test.post.TagNav {
+			if n.Tag.Name == test.tag {
+				nav = n
+			}
+		}
+		if nav == nil {
+			t.Errorf("%s has no navigation in %s", test.post.Meta.Title, test.tag)
+			continue
+		}
+		if link(nav.Prev) != test.prev || link(nav.Next) != test.next {
+			t.Errorf("%s in %s has prev %q and next %q, want %q and %q", test.post.Meta.Title, test.tag, link(nav.Prev), link(nav.Next), test.prev, test.next)
+		}
+	}
+	if len(d.TagNav) != 1 || len(c.TagNav) != 2 {
+		t.Error("posts don't have a navigation per tag")
+	}
+}
+
+func TestCollidingTagSlugs(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"first/post.md":  testPost("First Post", "01.02.2021", "The first post.", "tags: [C]"),
+		"second/post.md": testPost("Second Post", "02.02.2021", "The second post.", "tags: [C#]"),
+	}).build()
+	c, sharp := site.read("tags/c/index.html"), site.read("tags/c-1/index.html")
+	if !strings.Contains(c, "First Post") || strings.Contains(c, "Second Post") {
+		t.Errorf("tags/c/ doesn't list only the C post:\n%s", c)
+	}
+	if !strings.Contains(sharp, "Second Post") || strings.Contains(sharp, "First Post") {
+		t.Errorf("tags/c-1/ doesn't list only the C# post:\n%s", sharp)
+	}
+	index := site.read("tags/index.html")
+	if !strings.Contains(index, `href="/tags/c/"`) || !strings.Contains(index, `href="/tags/c-1/"`) {
+		t.Errorf("tag index doesn't link both tag pages:\n%s", index)
+	}
+	if post := site.read("second/index.html"); !strings.Contains(post, `/tags/c-1/`) || strings.Contains(post, `/tags/c/"`) {
+		t.Errorf("the C# post doesn't link its own tag page")
+	}
+}
@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestOffsetlessDateInTimezone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	meta, err := parseMeta([]byte("title: Summer\ndate: 2021-07-01 09:30\n"), "2006-01-02 15:04", berlin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2021, 7, 1, 7, 30, 0, 0, time.UTC); !meta.ParsedDate.Equal(want) {
+		t.Errorf("parsed date is %v, want %v", meta.ParsedDate, want)
+	}
+
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.Timezone = "Europe/Berlin"
+		cfg.Blog.Dateformat = "2006-01-02 15:04"
+	}), map[string]string{
+		"summer/post.md": testPost("Summer", "2021-07-01 09:30", "Summer."),
+		"winter/post.md": testPost("Winter", "2021-01-15 18:00", "Winter."),
+	}).build()
+	dates := map[string]string{}
+	for _, item := range parseTestFeed(t, site.read("index.xml")).Channel.Items {
+		dates[item.Title] = item.PubDate
+	}
+	if want := "Thu, 01 Jul 2021 09:30:00 +0200"; dates["Summer"] != want {
+		t.Errorf("pubDate in summer is %q, want %q", dates["Summer"], want)
+	}
+	if want := "Fri, 15 Jan 2021 18:00:00 +0100"; dates["Winter"] != want {
+		t.Errorf("pubDate in winter is %q, want %q", dates["Winter"], want)
+	}
+}
@@ -0,0 +1,58 @@
+package generator
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// TOCOptions configure the table of contents of posts, it lists the headings
+// from MinLevel to MaxLevel. Other headings still get their anchor ids.
+type TOCOptions struct {
+	Enabled  bool
+	MinLevel int
+	MaxLevel int
+}
+
+// newTOCCollector returns the transform setting the table of contents of a
+// post from its headings, it has to run after the heading anchors
+func newTOCCollector(opts TOCOptions) Transform {
+	return func(post *Post, doc *goquery.Document) error {
+		post.TOC = ""
+		if !opts.Enabled {
+			return nil
+		}
+		var b strings.Builder
+		// levels of the open lists, the nesting follows the heading levels
+		var open []int
+		doc.Find("h1, h2, h3, h4, h5, h6").Each(func(i int, s *goquery.Selection) {
+			level := int(goquery.NodeName(s)[1] - '0')
+			id := s.AttrOr("id", "")
+			if level < opts.MinLevel || level > opts.MaxLevel || id == "" {
+				return
+			}
+			heading := s.Clone()
+			heading.Find("a.anchor").Remove()
+			title := strings.Join(strings.Fields(heading.Text()), " ")
+			switch {
+			case len(open) == 0 || level > open[len(open)-1]:
+				b.WriteString("<ul>")
+				open = append(open, level)
+			default:
+				for len(open) > 1 && level < open[len(open)-1] && level <= open[len(open)-2] {
+					b.WriteString("</li></ul>")
+					open = open[:len(open)-1]
+				}
+				b.WriteString("</li>")
+			}
+			fmt.Fprintf(&b, `<li><a href="#%s">%s</a>`, template.HTMLEscapeString(id), template.HTMLEscapeString(title))
+		})
+		for range open {
+			b.WriteString("</li></ul>")
+		}
+		post.TOC = template.HTML(b.String())
+		return nil
+	}
+}
@@ -0,0 +1,26 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestTOCLevels(t *testing.T) {
+	source := "---\ntitle: TOC\ndate: 03.04.2021\n---\n## Setup\n\n### Install\n\n#### Details\n\n## Usage\n"
+	cfg := &config.Config{}
+	cfg.Blog.URL = "https://example.com"
+	cfg.Blog.TOC.Enabled = true
+	post, err := RenderPost([]byte(source), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<ul><li><a href="#setup">Setup</a><ul><li><a href="#install">Install</a></li></ul></li><li><a href="#usage">Usage</a></li></ul>`
+	if string(post.TOC) != want {
+		t.Errorf("got toc\n%s\nwant\n%s", post.TOC, want)
+	}
+	if !strings.Contains(string(post.HTML), `<h4 id="details">`) {
+		t.Errorf("h4 left out of the toc has no id:\n%s", post.HTML)
+	}
+}
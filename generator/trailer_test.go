@@ -0,0 +1,25 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTrailer(t *testing.T) {
+	cfg := newTestConfig(t, nil)
+	site := newTestSite(t, cfg, map[string]string{
+		"trailer.html":   `<p>Thanks for reading {{ .Meta.Title }}.</p>`,
+		"first/post.md":  testPost("First Post", "01.02.2021", "The first post."),
+		"second/post.md": testPost("Second Post", "02.02.2021", "The second post.", "notrailer: true"),
+	})
+	cfg.Blog.Trailer = filepath.Join(site.Root, "trailer.html")
+	site.build()
+	want := `<div class="post-trailer"><p>Thanks for reading First Post.</p></div>`
+	if !strings.Contains(site.read("first/index.html"), want) {
+		t.Error("post doesn't show the trailer")
+	}
+	if strings.Contains(site.read("second/index.html"), "post-trailer") {
+		t.Error("post which opted out shows the trailer")
+	}
+}
@@ -0,0 +1,311 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/eleztian/blog-generator/config"
+	"github.com/sourcegraph/syntaxhighlight"
+)
+
+// Transform modifies the parsed html of a post, it is applied to every post
+// after its markdown is rendered
+type Transform func(post *Post, doc *goquery.Document) error
+
+// RenderOptions configure the markdown renderer and the built-in transforms.
+// Extensions and HTMLFlags are the blackfriday flags of the renderer.
+type RenderOptions struct {
+	Extensions int
+	HTMLFlags  int
+	Anchors    AnchorOptions
+	TOC        TOCOptions
+	External   ExternalLinkOptions
+	Autolink   AutolinkOptions
+	// TableClass is the class of the div tables are wrapped in, empty if
+	// they aren't wrapped
+	TableClass string
+	// SlugCJK is how the heading ids handle CJK characters
+	SlugCJK string
+	// Transforms are applied after the built-in ones
+	Transforms []Transform
+	// Timings measure the rendering and highlighting
+	Timings *PhaseTimings
+}
+
+// AnchorOptions configure the anchor links of headings. Symbol is the text
+// of the link ("#" if empty), Position is "before" or "after" (the default)
+// the heading text and with Hover the link is only visible on hover.
+type AnchorOptions struct {
+	Symbol   string
+	Position string
+	Hover    bool
+}
+
+// newRenderOptions returns the render options of the blog config
+func newRenderOptions(cfg *config.Config) (RenderOptions, error) {
+	extensions, htmlFlags, err := getMarkdownFlags(cfg.Blog.Markdown)
+	if err != nil {
+		return RenderOptions{}, err
+	}
+	return RenderOptions{
+		Extensions: extensions,
+		HTMLFlags:  htmlFlags,
+		Anchors: AnchorOptions{
+			Symbol:   cfg.Blog.Anchors.Symbol,
+			Position: cfg.Blog.Anchors.Position,
+			Hover:    cfg.Blog.Anchors.Hover,
+		},
+		TOC: TOCOptions{
+			Enabled:  cfg.Blog.TOC.Enabled,
+			MinLevel: cfg.Blog.TOC.MinLevel,
+			MaxLevel: cfg.Blog.TOC.MaxLevel,
+		},
+		External: ExternalLinkOptions{
+			Host:      getURLHost(cfg.Blog.URL),
+			NewTab:    cfg.Blog.ExternalLinks.NewTab,
+			Indicator: cfg.Blog.ExternalLinks.Indicator,
+		},
+		Autolink: AutolinkOptions{
+			URLs:     cfg.Blog.Autolink.URLs,
+			Mentions: cfg.Blog.Autolink.Mentions,
+		},
+		TableClass: getTableClass(cfg.Blog.Tables.Responsive, cfg.Blog.Tables.Class),
+		SlugCJK:    cfg.Blog.Slugs.CJK,
+	}, nil
+}
+
+// hintedTransform is a built-in transform with the substrings of rendered
+// markdown it acts on, html containing none of them is not changed by it
+type hintedTransform struct {
+	transform Transform
+	hints     [][]byte
+}
+
+// headingHints are the start tags of headings
+var headingHints = [][]byte{[]byte("<h1"), []byte("<h2"), []byte("<h3"), []byte("<h4"), []byte("<h5"), []byte("<h6")}
+
+// getBuiltinTransforms returns the built-in transforms of opts in the order
+// they run, transforms which are disabled in opts have no hints
+func getBuiltinTransforms(opts RenderOptions) []hintedTransform {
+	highlight := highlightCode
+	if opts.Timings != nil {
+		highlight = func(post *Post, doc *goquery.Document) error {
+			defer opts.Timings.since(phaseHighlighting, time.Now())
+			return highlightCode(post, doc)
+		}
+	}
+	var tocHints, autolinkHints, externalHints [][]byte
+	if opts.TOC.Enabled {
+		tocHints = headingHints
+	}
+	if opts.Autolink.URLs {
+		autolinkHints = append(autolinkHints, []byte("://"))
+	}
+	if opts.Autolink.Mentions != "" {
+		autolinkHints = append(autolinkHints, []byte("@"))
+	}
+	if opts.External.NewTab || opts.External.Indicator != "" {
+		externalHints = [][]byte{[]byte(`href="http`)}
+	}
+	return []hintedTransform{
+		{highlight, [][]byte{[]byte("language-"), []byte("<pre")}},
+		{newHeadingAnchors(opts.Anchors, opts.SlugCJK), headingHints},
+		{newTOCCollector(opts.TOC), tocHints},
+		{newAutolinks(opts.Autolink), autolinkHints},
+		{newExternalLinks(opts.External), externalHints},
+		{newTableWrappers(opts.TableClass), [][]byte{[]byte("<table")}},
+		{captionImages, [][]byte{[]byte("<img")}},
+	}
+}
+
+// getTransforms returns the built-in transforms which may change html
+// followed by the transforms of opts
+func getTransforms(html []byte, opts RenderOptions) []Transform {
+	var transforms []Transform
+	for _, t := range getBuiltinTransforms(opts) {
+		for _, hint := range t.hints {
+			if bytes.Contains(html, hint) {
+				transforms = append(transforms, t.transform)
+				break
+			}
+		}
+	}
+	return append(transforms, opts.Transforms...)
+}
+
+// needsTransforms reports if the transform chain of opts may change html,
+// which is always the case with transforms besides the built-in ones
+func needsTransforms(html []byte, opts RenderOptions) bool {
+	return len(getTransforms(html, opts)) > 0
+}
+
+// applyTransforms runs transforms on the html of a post
+func applyTransforms(post *Post, doc *goquery.Document, transforms []Transform) error {
+	for _, t := range transforms {
+		if err := t(post, doc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// highlightedClass marks code blocks which are already highlighted, they are
+// skipped so highlighting html twice doesn't change it
+const highlightedClass = "highlighted"
+
+// codeLang matches the names of languages of code blocks
+var codeLang = regexp.MustCompile(`^[\w+#.-]+$`)
+
+// highlightCode replaces code blocks with a language by highlighted versions,
+// code blocks without one get the defaultCodeLang of the post
+func highlightCode(post *Post, doc *goquery.Document) error {
+	if post.Meta != nil && post.Meta.DefaultCodeLang != "" {
+		labelCodeBlocks(doc, post.Meta.DefaultCodeLang)
+	}
+	var err error
+	doc.Find("code[class*=\"language-\"]").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if s.HasClass(highlightedClass) {
+			return true
+		}
+		s.AddClass(highlightedClass)
+		if s.HasClass("language-diff") {
+			s.SetHtml(highlightDiff(s.Text()))
+			return true
+		}
+		var formatted []byte
+		formatted, err = syntaxhighlight.AsHTML([]byte(s.Text()))
+		if err != nil {
+			err = fmt.Errorf("error during syntax highlighting : %v", err)
+			return false
+		}
+		s.SetHtml(string(formatted))
+		return true
+	})
+	return err
+}
+
+// labelCodeBlocks sets the language of the code blocks without one to lang,
+// inline code is left alone
+func labelCodeBlocks(doc *goquery.Document, lang string) {
+	doc.Find("pre > code").Each(func(i int, s *goquery.Selection) {
+		for _, class := range strings.Fields(s.AttrOr("class", "")) {
+			if strings.HasPrefix(class, "language-") {
+				return
+			}
+		}
+		s.AddClass("language-" + lang)
+	})
+}
+
+// highlightDiff wraps the lines of a diff in spans with the classes diff-file,
+// diff-add, diff-del and diff-hunk depending on their first characters, other
+// lines are left as they are
+func highlightDiff(code string) string {
+	var b strings.Builder
+	lines := strings.SplitAfter(code, "\n")
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		class := ""
+		switch {
+		case strings.HasPrefix(line, "+++ "), strings.HasPrefix(line, "--- "):
+			class = "diff-file"
+		case strings.HasPrefix(line, "@@"):
+			class = "diff-hunk"
+		case strings.HasPrefix(line, "+"):
+			class = "diff-add"
+		case strings.HasPrefix(line, "-"):
+			class = "diff-del"
+		}
+		escaped := template.HTMLEscapeString(line)
+		if class == "" {
+			b.WriteString(escaped)
+			continue
+		}
+		fmt.Fprintf(&b, `<span class="%s">%s</span>`, class, escaped)
+	}
+	return b.String()
+}
+
+// newHeadingAnchors returns the transform giving every heading an id and a
+// link to it, before or after its text
+func newHeadingAnchors(opts AnchorOptions, cjk string) Transform {
+	symbol := opts.Symbol
+	if symbol == "" {
+		symbol = "#"
+	}
+	class := "anchor"
+	if opts.Hover {
+		class += " anchor-hover"
+	}
+	return func(post *Post, doc *goquery.Document) error {
+		used := map[string]bool{}
+		doc.Find("[id]").Each(func(i int, s *goquery.Selection) {
+			used[s.AttrOr("id", "")] = true
+		})
+		doc.Find("h1, h2, h3, h4, h5, h6").Each(func(i int, s *goquery.Selection) {
+			id, ok := s.Attr("id")
+			if !ok || id == "" {
+				id = uniqueID(slugify(s.Text(), cjk), used)
+				s.SetAttr("id", id)
+			}
+			anchor := fmt.Sprintf(`<a class="%s" href="#%s" aria-hidden="true">%s</a>`, class, id, template.HTMLEscapeString(symbol))
+			if opts.Position == "before" {
+				s.PrependHtml(anchor)
+			} else {
+				s.AppendHtml(anchor)
+			}
+		})
+		return nil
+	}
+}
+
+// uniqueID returns id, or id with the first free numeric suffix
+func uniqueID(id string, used map[string]bool) string {
+	result := id
+	for i := 1; used[result]; i++ {
+		result = fmt.Sprintf("%s-%d", id, i)
+	}
+	used[result] = true
+	return result
+}
+
+// captionImages merges a paragraph containing only an image and a directly
+// following paragraph containing only emphasized text into a <figure>
+func captionImages(post *Post, doc *goquery.Document) error {
+	doc.Find("p").Each(func(i int, s *goquery.Selection) {
+		if !isOnly(s, "img") {
+			return
+		}
+		next := s.Next()
+		if !next.Is("p") || !isOnly(next, "em") {
+			return
+		}
+		img, err := goquery.OuterHtml(s.Children().First())
+		if err != nil {
+			return
+		}
+		caption, err := next.Children().First().Html()
+		if err != nil {
+			return
+		}
+		next.Remove()
+		s.ReplaceWithHtml(fmt.Sprintf("<figure>%s<figcaption>%s</figcaption></figure>", img, caption))
+	})
+	return nil
+}
+
+// isOnly reports if the only content of s is a single element matching selector
+func isOnly(s *goquery.Selection, selector string) bool {
+	children := s.Children()
+	if children.Length() != 1 || !children.Is(selector) {
+		return false
+	}
+	return strings.TrimSpace(s.Text()) == strings.TrimSpace(children.Text())
+}
@@ -0,0 +1,222 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestNeedsTransforms(t *testing.T) {
+	prose := []byte("<p>Mail me @ home, the site is example.com://x</p>")
+	tests := []struct {
+		name string
+		html []byte
+		opts RenderOptions
+		want bool
+	}{
+		{"plain prose", []byte("<p>Just some text.</p>"), RenderOptions{}, false},
+		{"autolinks disabled", prose, RenderOptions{}, false},
+		{"url autolinks", prose, RenderOptions{Autolink: AutolinkOptions{URLs: true}}, true},
+		{"mention autolinks", []byte("<p>Thanks @someone</p>"), RenderOptions{Autolink: AutolinkOptions{Mentions: "https://x.com/{handle}"}}, true},
+		{"mentions without handles", []byte("<p>Thanks @someone</p>"), RenderOptions{Autolink: AutolinkOptions{URLs: true}}, false},
+		{"heading", []byte("<h2>Title</h2>"), RenderOptions{}, true},
+		{"code", []byte("<pre><code>x</code></pre>"), RenderOptions{}, true},
+	}
+	for _, test := range tests {
+		if got := needsTransforms(test.html, test.opts); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestTransformsRunOnTheirHints(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		opts RenderOptions
+		want int
+	}{
+		{"heading", "<h2>Title</h2><p>Text.</p>", RenderOptions{}, 1},
+		{"heading with toc", "<h2>Title</h2>", RenderOptions{TOC: TOCOptions{Enabled: true}}, 2},
+		{"code", `<pre><code class="language-go">x</code></pre>`, RenderOptions{}, 1},
+		{"external link", `<p><a href="https://other.org/">x</a></p>`, RenderOptions{}, 0},
+		{"marked external link", `<p><a href="https://other.org/">x</a></p>`, RenderOptions{External: ExternalLinkOptions{NewTab: true}}, 1},
+		{"heading, image and table", "<h2>Title</h2><p><img src=\"a.png\"></p><table></table>", RenderOptions{}, 3},
+	}
+	for _, test := range tests {
+		if got := len(getTransforms([]byte(test.html), test.opts)); got != test.want {
+			t.Errorf("%s: %d transforms run, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestHeadingAnchors(t *testing.T) {
+	tests := []struct {
+		opts AnchorOptions
+		want string
+	}{
+		{AnchorOptions{}, `<h2 id="intro">Intro<a aria-hidden="true" class="anchor" href="#intro">#</a></h2><h2 id="intro-1">Intro<a aria-hidden="true" class="anchor" href="#intro-1">#</a></h2><h3 id="set">Set<a aria-hidden="true" class="anchor" href="#set">#</a></h3>`},
+		{AnchorOptions{Symbol: "§", Position: "before", Hover: true}, `<h2 id="intro"><a aria-hidden="true" class="anchor anchor-hover" href="#intro">§</a>Intro</h2><h2 id="intro-1"><a aria-hidden="true" class="anchor anchor-hover" href="#intro-1">§</a>Intro</h2><h3 id="set"><a aria-hidden="true" class="anchor anchor-hover" href="#set">§</a>Set</h3>`},
+	}
+	for _, test := range tests {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<h2>Intro</h2><h2>Intro</h2><h3 id="set">Set</h3>`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := newHeadingAnchors(test.opts, slugKeepCJK)(&Post{}, doc); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := doc.Find("body").Html(); got != test.want {
+			t.Errorf("got %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestRegisterTransformPerGenerator(t *testing.T) {
+	files := map[string]string{"first/post.md": testPost("First Post", "01.02.2021", "The first post.")}
+	site := newTestSite(t, newTestConfig(t, nil), files)
+	g := New(&SiteConfig{Sources: site.sources(), SourceRoot: site.Root, Destination: site.Dest, Config: site.Config})
+	g.RegisterTransform(func(post *Post, doc *goquery.Document) error {
+		doc.Find("p").AddClass("prose")
+		return nil
+	})
+	if err := g.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(site.read("first/index.html"), `<p class="prose">The first post.</p>`) {
+		t.Error("registered transform wasn't applied")
+	}
+	other := newTestSite(t, newTestConfig(t, nil), files).build()
+	if strings.Contains(other.read("first/index.html"), "prose") {
+		t.Error("transform of another generator was applied")
+	}
+}
+
+// applyTestTransform applies transform to html and returns the resulting body
+func applyTestTransform(t *testing.T, transform Transform, html string) string {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := transform(&Post{}, doc); err != nil {
+		t.Fatal(err)
+	}
+	body, err := doc.Find("body").Html()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestCaptionImages(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			"merged",
+			`<p><img src="cat.png" alt="Cat"/></p><p><em>A <a href="/cat/">cat</a></em></p>`,
+			`<figure><img src="cat.png" alt="Cat"/><figcaption>A <a href="/cat/">cat</a></figcaption></figure>`,
+		},
+		{
+			"mixed caption",
+			`<p><img src="cat.png"/></p><p><em>A cat</em> sleeping</p>`,
+			`<p><img src="cat.png"/></p><p><em>A cat</em> sleeping</p>`,
+		},
+		{
+			"image with text",
+			`<p>Look: <img src="cat.png"/></p><p><em>A cat</em></p>`,
+			`<p>Look: <img src="cat.png"/></p><p><em>A cat</em></p>`,
+		},
+		{
+			"not adjacent",
+			`<p><img src="cat.png"/></p><hr/><p><em>A cat</em></p>`,
+			`<p><img src="cat.png"/></p><hr/><p><em>A cat</em></p>`,
+		},
+	}
+	for _, test := range tests {
+		if got := applyTestTransform(t, captionImages, test.html); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCaptionImagesInPost(t *testing.T) {
+	post, err := RenderPost([]byte(testPost("Cat", "01.02.2021", "![Cat](cat.png)\n\n*A sleeping cat*\n")), newTestConfig(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(post.HTML), `<figcaption>A sleeping cat</figcaption></figure>`) {
+		t.Errorf("image of the post wasn't captioned: %s", post.HTML)
+	}
+}
+
+func TestHighlightDiff(t *testing.T) {
+	code := "--- a/main.go\n+++ b/main.go\n@@ -1,2 +1,2 @@\n context <b>\n-old line\n+new line\n"
+	want := `<span class="diff-file">--- a/main.go` + "\n</span>" +
+		`<span class="diff-file">+++ b/main.go` + "\n</span>" +
+		`<span class="diff-hunk">@@ -1,2 +1,2 @@` + "\n</span> context &lt;b&gt;\n" +
+		`<span class="diff-del">-old line` + "\n</span>" +
+		`<span class="diff-add">+new line` + "\n</span>"
+	if got := highlightDiff(code); got != want {
+		t.Errorf("highlightDiff = %q, want %q", got, want)
+	}
+}
+
+func TestDiffFence(t *testing.T) {
+	post, err := RenderPost([]byte(testPost("Diff", "01.02.2021", "```diff\n--- a/main.go\n+++ b/main.go\n-a := 1\n+a := 2\n```\n")), newTestConfig(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(post.HTML)
+	for _, want := range []string{`<span class="diff-file">--- a/main.go`, `<span class="diff-file">+++ b/main.go`, `<span class="diff-del">-a := 1`, `<span class="diff-add">+a := 2`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("diff fence is missing %s: %s", want, html)
+		}
+	}
+	if strings.Contains(html, `class="pln"`) {
+		t.Error("diff was highlighted as a programming language")
+	}
+}
+
+func TestHighlightTwiceIsIdempotent(t *testing.T) {
+	html := `<pre><code class="language-go">func main() { fmt.Println("&lt;hi&gt;") }</code></pre>` +
+		`<pre><code class="language-diff">-old
++new</code></pre><p><code>inline</code></p>`
+	once := applyTestTransform(t, highlightCode, html)
+	if once == html || strings.Count(once, highlightedClass) != 2 {
+		t.Fatalf("code blocks weren't highlighted:\n%s", once)
+	}
+	if twice := applyTestTransform(t, highlightCode, once); twice != once {
+		t.Errorf("highlighting twice changed the html:\n%s\nto\n%s", once, twice)
+	}
+}
+
+func TestDefaultCodeLang(t *testing.T) {
+	body := "```\nfunc main() {}\n```\n\n```python\nprint(1)\n```\n\nSome `inline` code."
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"default/post.md": testPost("Default", "01.02.2021", body, "defaultCodeLang: go"),
+		"plain/post.md":   testPost("Plain", "02.02.2021", body),
+	}).build()
+	classes := func(page string) []string {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(site.read(page)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := []string{}
+		doc.Find(".post-content code").Each(func(i int, s *goquery.Selection) {
+			result = append(result, s.AttrOr("class", ""))
+		})
+		return result
+	}
+	if got, want := classes("default/index.html"), []string{"language-go highlighted", "language-python highlighted", ""}; !reflect.DeepEqual(got, want) {
+		t.Errorf("code classes with a default language are %q, want %q", got, want)
+	}
+	if got, want := classes("plain/index.html"), []string{"", "language-python highlighted", ""}; !reflect.DeepEqual(got, want) {
+		t.Errorf("code classes without a default language are %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// updatedPath is the folder of the feed of updated posts below the blog URL
+const updatedPath = "/updated/"
+
+// getUpdatedPosts returns the posts updated after their publication, most
+// recently updated first
+func getUpdatedPosts(posts []*Post) []*Post {
+	updated := []*Post{}
+	for _, post := range posts {
+		if isUpdated(post) {
+			updated = append(updated, post)
+		}
+	}
+	sort.SliceStable(updated, func(i, j int) bool {
+		return getUpdateTime(updated[i]).After(getUpdateTime(updated[j]))
+	})
+	return updated
+}
+
+// isUpdated reports whether the updated date of a post differs from its
+// publish date
+func isUpdated(post *Post) bool {
+	meta := post.Meta
+	return !meta.ParsedUpdated.IsZero() && !meta.ParsedUpdated.Equal(meta.ParsedDate)
+}
+
+// getUpdateTime returns when a post was last updated, its publish date if it
+// never was
+func getUpdateTime(post *Post) time.Time {
+	if post.Meta.ParsedUpdated.IsZero() {
+		return post.Meta.ParsedDate
+	}
+	return post.Meta.ParsedUpdated
+}
+
+// getFeedLinks returns the links to the feeds of every page
+func getFeedLinks(blogURL, blogTitle string, updated bool) []FeedLink {
+	feeds := []FeedLink{newRSSFeedLink(blogURL, "/", blogTitle)}
+	if updated {
+		feeds = append(feeds, newRSSFeedLink(blogURL, updatedPath, getUpdatedFeedTitle(blogTitle)))
+	}
+	return feeds
+}
+
+// newUpdatedFeed returns the RSS feed of the updated posts of feed
+func newUpdatedFeed(feed *RSSConfig, destination string) *RSSGenerator {
+	updated := *feed
+	updated.Posts = getUpdatedPosts(feed.Posts)
+	updated.Destination = filepath.Join(destination, filepath.FromSlash(updatedPath))
+	updated.Title = getUpdatedFeedTitle(feed.BlogTitle)
+	updated.Path = updatedPath
+	updated.Updated = true
+	return &RSSGenerator{&updated}
+}
+
+func getUpdatedFeedTitle(blogTitle string) string {
+	return fmt.Sprintf("Updated - %s", blogTitle)
+}
@@ -0,0 +1,144 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// normalizeLink makes an internal link canonical, directory-style links end
+// with a slash and their index.html is dropped, file-style links don't end
+// with a slash
+func normalizeLink(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	p := strings.TrimSuffix(u.Path, "index.html")
+	switch {
+	case p == "":
+		p = "/"
+	case path.Ext(strings.TrimSuffix(p, "/")) != "":
+		p = strings.TrimSuffix(p, "/")
+	case !strings.HasSuffix(p, "/"):
+		p += "/"
+	}
+	u.Path = p
+	return u.String()
+}
+
+// isInternalLink reports if link points into the blog, either absolute below
+// blogURL or relative to its root
+func isInternalLink(link, blogURL string) bool {
+	if blogURL != "" && (link == blogURL || strings.HasPrefix(link, blogURL+"/")) {
+		return true
+	}
+	return strings.HasPrefix(link, "/") && !strings.HasPrefix(link, "//")
+}
+
+// normalizeLinks makes the internal links of doc canonical
+func normalizeLinks(doc *goquery.Document, blogURL string) {
+	doc.Find("a[href], link[href]").Each(func(i int, s *goquery.Selection) {
+		if link := s.AttrOr("href", ""); isInternalLink(link, blogURL) {
+			s.SetAttr("href", normalizeLink(link))
+		}
+	})
+}
+
+// normalizePageLinks makes the internal links of the page written to
+// filePath canonical
+func normalizePageLinks(page []byte, blogURL, filePath string) ([]byte, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page))
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing html %s: %v", filePath, err)
+	}
+	normalizeLinks(doc, blogURL)
+	result, err := doc.Html()
+	if err != nil {
+		return nil, fmt.Errorf("error while generating html %s: %v", filePath, err)
+	}
+	return []byte(result), nil
+}
+
+// normalizePostLinks makes the internal links of the html of a post
+// canonical, so they are in the feeds as well
+func normalizePostLinks(post *Post, blogURL string) error {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(post.HTML))
+	if err != nil {
+		return fmt.Errorf("error while parsing html of %s: %v", post.Name, err)
+	}
+	normalizeLinks(doc, blogURL)
+	result, err := doc.Find("body").Html()
+	if err != nil {
+		return fmt.Errorf("error while generating html of %s: %v", post.Name, err)
+	}
+	post.HTML = []byte(result)
+	return nil
+}
+
+// getPageLink returns the canonical URL of the page written to the folder path
+// below destination
+func getPageLink(blogURL, destination, path string) string {
+	rel, err := filepath.Rel(destination, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return blogURL + "/"
+	}
+	return normalizeLink(fmt.Sprintf("%s/%s/", blogURL, filepath.ToSlash(rel)))
+}
+
+// writeRedirectStubs writes a <folder>.html redirecting to <folder>/ next to
+// every generated folder with an index.html, for hosts serving /folder from
+// folder.html
+func writeRedirectStubs(destination, blogURL string, mode os.FileMode) error {
+	return filepath.Walk(destination, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Name() != "index.html" {
+			return nil
+		}
+		dir := filepath.Dir(p)
+		if dir == filepath.Clean(destination) {
+			return nil
+		}
+		stub := dir + ".html"
+		if _, err := os.Stat(stub); err == nil {
+			return nil
+		}
+		return writeRedirectStub(stub, getPageLink(blogURL, destination, dir), mode)
+	})
+}
+
+// writeRedirectStub writes an html page at filePath redirecting to target
+func writeRedirectStub(filePath, target string, mode os.FileMode) error {
+	escaped := strings.NewReplacer(`&`, "&amp;", `"`, "&quot;", `<`, "&lt;", `>`, "&gt;").Replace(target)
+	script, err := json.Marshal(target)
+	if err != nil {
+		return fmt.Errorf("error writing redirect %s: %v", filePath, err)
+	}
+	html := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Redirecting…</title>
+<link rel="canonical" href="%[1]s">
+<meta name="robots" content="noindex">
+<meta http-equiv="refresh" content="0; url=%[1]s">
+<script>location.replace(%[2]s)</script>
+</head>
+<body><a href="%[1]s">%[1]s</a></body>
+</html>
+`, escaped, script)
+	if err := ioutil.WriteFile(filePath, []byte(html), mode); err != nil {
+		return fmt.Errorf("error writing redirect %s: %v", filePath, err)
+	}
+	return nil
+}
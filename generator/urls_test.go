@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestPostURLMatchesOutput(t *testing.T) {
+	for _, blogURL := range []string{"https://example.com", "https://example.org/sub"} {
+		for _, clean := range []bool{false, true} {
+			cfg := newTestConfig(t, func(cfg *config.Config) {
+				cfg.Blog.URL = blogURL
+				cfg.Blog.CleanURLs = clean
+			})
+			site := newTestSite(t, cfg, map[string]string{
+				"plain/post.md":      testPost("Plain", "01.02.2021", "Plain."),
+				"go/nested/post.md":  testPost("Nested", "02.02.2021", "Nested."),
+				"go/slugged/post.md": testPost("Slugged", "03.02.2021", "Slugged.", "slug: other/name"),
+			}).build()
+			loader := newTestLoader(t, site.Root, cfg)
+			links := map[string]string{}
+			for _, item := range parseTestFeed(t, site.read("index.xml")).Channel.Items {
+				links[item.Title] = item.Link
+			}
+			sitemap := site.read("sitemap.xml")
+			index := site.read("blog/index.html")
+			for _, source := range site.sources() {
+				post, err := loader.newPost(source)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if post.URL != blogURL+post.Path {
+					t.Errorf("URL %s doesn't end with the path %s", post.URL, post.Path)
+				}
+				if !site.exists(filepath.FromSlash(post.Path) + "index.html") {
+					t.Errorf("%s isn't generated at its path %s", post.Meta.Title, post.Path)
+				}
+				if links[post.Meta.Title] != post.URL {
+					t.Errorf("feed links %s to %s, want %s", post.Meta.Title, links[post.Meta.Title], post.URL)
+				}
+				if !strings.Contains(sitemap, "<loc>"+post.URL+"</loc>") {
+					t.Errorf("sitemap doesn't contain %s", post.URL)
+				}
+				if !strings.Contains(index, `href="`+post.Path+`"`) && !strings.Contains(index, `href="`+post.URL+`"`) {
+					t.Errorf("listing doesn't link %s", post.Path)
+				}
+			}
+		}
+	}
+}
+
+func TestCleanURLs(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+		cfg.Blog.CleanURLs = true
+		cfg.Blog.RedirectStubs = true
+	}), map[string]string{
+		"first/post.md":  testPost("First", "01.02.2021", "See [the second](/second) and [its index](/second/index.html).", "tags: [go]"),
+		"second/post.md": testPost("Second", "02.02.2021", "Second.", "tags: [go]"),
+	}).build()
+	link := regexp.MustCompile(`(?:href|src)="([^"#]+)|<(?:loc|link)>([^<]+)<`)
+	err := filepath.Walk(site.Dest, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || (filepath.Ext(path) != ".html" && filepath.Ext(path) != ".xml") {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, match := range link.FindAllStringSubmatch(string(data), -1) {
+			href := match[1] + match[2]
+			u, err := url.Parse(href)
+			if err != nil || (u.Host != "" && u.Host != "example.com") || !strings.HasPrefix(u.Path, "/") {
+				continue
+			}
+			if normalized := normalizeLink(href); href != normalized {
+				t.Errorf("%s links %s, want %s", path, href, normalized)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range parseTestFeed(t, site.read("index.xml")).Channel.Items {
+		if item.Title == "First" && (!strings.Contains(item.Content, `href="/second/"`) || strings.Contains(item.Content, "index.html")) {
+			t.Errorf("feed content links aren't canonical: %s", item.Content)
+		}
+	}
+	for _, name := range []string{"first", "second", "tags/go", "blog"} {
+		stub := site.read(name + ".html")
+		target := "https://example.com/" + name + "/"
+		if !strings.Contains(stub, `<link rel="canonical" href="`+target+`">`) || !strings.Contains(stub, `url=`+target+`"`) {
+			t.Errorf("redirect stub %s.html doesn't point to %s", name, target)
+		}
+	}
+	if site.exists("index.html.html") || site.exists(".html") {
+		t.Error("redirect stub written for the root")
+	}
+}
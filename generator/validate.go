@@ -0,0 +1,118 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// voidElements never have an end tag
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// optionalEndElements may omit their end tag
+var optionalEndElements = map[string]bool{
+	"html": true, "head": true, "body": true, "p": true, "li": true,
+	"dt": true, "dd": true, "rt": true, "rp": true, "optgroup": true,
+	"option": true, "colgroup": true, "caption": true, "thead": true,
+	"tbody": true, "tfoot": true, "tr": true, "td": true, "th": true,
+}
+
+// reportInvalidHTML validates every generated html file in destination, in
+// strict mode invalid files fail the generation
+func reportInvalidHTML(destination string, strict bool) error {
+	count := 0
+	err := filepath.Walk(destination, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".html" {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		rel, err := filepath.Rel(destination, path)
+		if err != nil {
+			return err
+		}
+		for _, problem := range validateHTML(f) {
+			fmt.Printf("html: %s: %s\n", filepath.ToSlash(rel), problem)
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error validating html of %s: %v", destination, err)
+	}
+	if strict && count > 0 {
+		return fmt.Errorf("found %d html problems", count)
+	}
+	return nil
+}
+
+// validateHTML returns the problems of an html document: unknown end tags,
+// unclosed elements and content which can't be tokenized
+func validateHTML(r io.Reader) []string {
+	problems := []string{}
+	open := []string{}
+	z := html.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if z.Err() != io.EOF {
+				return append(problems, fmt.Sprintf("error parsing html: %v", z.Err()))
+			}
+			for i := len(open) - 1; i >= 0; i-- {
+				if !optionalEndElements[open[i]] {
+					problems = append(problems, fmt.Sprintf("unclosed <%s>", open[i]))
+				}
+			}
+			return problems
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			if tag := string(name); !voidElements[tag] {
+				open = append(open, tag)
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+			if voidElements[tag] {
+				continue
+			}
+			i := len(open) - 1
+			for i >= 0 && open[i] != tag {
+				i--
+			}
+			if i < 0 {
+				problems = append(problems, fmt.Sprintf("unexpected </%s>", tag))
+				continue
+			}
+			unclosed := []string{}
+			for _, inner := range open[i+1:] {
+				if !optionalEndElements[inner] {
+					unclosed = append(unclosed, "<"+inner+">")
+				}
+			}
+			if len(unclosed) > 0 {
+				problems = append(problems, fmt.Sprintf("unclosed %s before </%s>", strings.Join(unclosed, ", "), tag))
+			}
+			open = open[:i]
+		}
+	}
+}
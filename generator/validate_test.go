@@ -0,0 +1,23 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateHTML(t *testing.T) {
+	tests := []struct {
+		html string
+		want []string
+	}{
+		{`<!DOCTYPE html><html><head><meta charset="utf-8"><title>T</title></head><body><p>One<p>Two<ul><li>A<li>B</ul><img src="a.png"><br></body></html>`, []string{}},
+		{`<div><span>text</div></em>`, []string{"unclosed <span> before </div>", "unexpected </em>"}},
+		{`<section><article>text`, []string{"unclosed <article>", "unclosed <section>"}},
+	}
+	for _, test := range tests {
+		if got := validateHTML(strings.NewReader(test.html)); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("validateHTML(%q) = %q, want %q", test.html, got, test.want)
+		}
+	}
+}
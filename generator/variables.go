@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+var variablePattern = regexp.MustCompile(`\{\{\s*([a-z]+\.[a-z]+)\s*\}\}`)
+
+// newBuildVariables returns the variables which can be used in posts, e.g.
+// {{site.name}} or {{now.year}}
+func newBuildVariables(cfg *config.Config, now time.Time) map[string]string {
+	return map[string]string{
+		"site.name":        cfg.Blog.Title,
+		"site.url":         cfg.Blog.URL,
+		"site.author":      cfg.Blog.Author,
+		"site.description": cfg.Blog.Description,
+		"site.language":    cfg.Blog.Language,
+		"now.year":         strconv.Itoa(now.Year()),
+	}
+}
+
+// interpolateVariables replaces the known variables in markdown, code blocks
+// and code spans as well as unknown variables are left untouched
+func interpolateVariables(markdown []byte, variables map[string]string) []byte {
+	if len(variables) == 0 || !variablePattern.Match(markdown) {
+		return markdown
+	}
+	return []byte(mapOutsideCode(string(markdown), func(text string) string {
+		return variablePattern.ReplaceAllStringFunc(text, func(token string) string {
+			name := variablePattern.FindStringSubmatch(token)[1]
+			if value, ok := variables[name]; ok {
+				return value
+			}
+			return token
+		})
+	}))
+}
+
+// mapOutsideCode applies fn to the parts of markdown outside of code blocks
+// and code spans
+func mapOutsideCode(markdown string, fn func(text string) string) string {
+	lines := strings.Split(markdown, "\n")
+	inFence := false
+	for i, line := range lines {
+		if isCodeFence(strings.TrimSpace(line)) {
+			inFence = !inFence
+			continue
+		}
+		if inFence || strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		// every odd part is inside of a code span
+		parts := strings.Split(line, "`")
+		for j := 0; j < len(parts); j += 2 {
+			parts[j] = fn(parts[j])
+		}
+		lines[i] = strings.Join(parts, "`")
+	}
+	return strings.Join(lines, "\n")
+}
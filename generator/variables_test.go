@@ -0,0 +1,37 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInterpolateVariables(t *testing.T) {
+	variables := newBuildVariables(newTestConfig(t, nil), time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{"prose", "Welcome to {{site.name}}, (c) {{ now.year }}.", "Welcome to Test Blog, (c) 2021."},
+		{"code span", "Write `{{site.name}}` for {{site.name}}.", "Write `{{site.name}}` for Test Blog."},
+		{"fenced code", "```\n{{site.name}}\n```\n{{site.author}}", "```\n{{site.name}}\n```\nTester"},
+		{"indented code", "    {{site.name}}\n\n{{site.author}}", "    {{site.name}}\n\nTester"},
+		{"unknown", "{{site.unknown}} and {{ page.title }} stay", "{{site.unknown}} and {{ page.title }} stay"},
+	}
+	for _, test := range tests {
+		if got := string(interpolateVariables([]byte(test.markdown), variables)); got != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestVariablesInPost(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"post/post.md": testPost("Post", "01.02.2021", "Posted on {{site.name}}.\n\n```\n{{site.name}}\n```"),
+	}).build()
+	page := site.read("post/index.html")
+	if !strings.Contains(page, "Posted on Test Blog.") || !strings.Contains(page, "{{site.name}}") {
+		t.Error("variables aren't expanded just in prose")
+	}
+}
@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnlistedPost(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"listed/post.md":   testPost("Listed Post", "01.02.2021", "Listed."),
+		"unlisted/post.md": testPost("Thank You", "02.02.2021", "Hidden.", "unlisted: true"),
+	}).build()
+	if !strings.Contains(site.read("unlisted/index.html"), "Hidden.") {
+		t.Error("unlisted post wasn't generated")
+	}
+	for _, name := range []string{"blog/index.html", "archive/index.html", "sitemap.xml"} {
+		content := site.read(name)
+		if !strings.Contains(content, "/listed/") {
+			t.Errorf("%s is missing the listed post", name)
+		}
+		if strings.Contains(content, "Thank You") || strings.Contains(content, "/unlisted/") {
+			t.Errorf("%s contains the unlisted post", name)
+		}
+	}
+	for _, item := range parseTestFeed(t, site.read("index.xml")).Channel.Items {
+		if item.Title == "Thank You" {
+			t.Error("feed contains the unlisted post")
+		}
+	}
+}
+
+func TestNoIndexPost(t *testing.T) {
+	site := newTestSite(t, newTestConfig(t, nil), map[string]string{
+		"indexed/post.md": testPost("Indexed Post", "01.02.2021", "Indexed."),
+		"utility/post.md": testPost("Utility Post", "02.02.2021", "Utility.", "noindex: true"),
+	}).build()
+	robots := `<meta name="robots" content="noindex">`
+	if !strings.Contains(site.read("utility/index.html"), robots) {
+		t.Error("noindex post has no robots meta tag")
+	}
+	if strings.Contains(site.read("indexed/index.html"), robots) {
+		t.Error("indexed post has a robots meta tag")
+	}
+	sitemap := site.read("sitemap.xml")
+	if strings.Contains(sitemap, "/utility/") || !strings.Contains(sitemap, "/indexed/") {
+		t.Error("sitemap doesn't leave out just the noindex post")
+	}
+	if !strings.Contains(site.read("blog/index.html"), "Utility Post") {
+		t.Error("noindex post isn't listed")
+	}
+}
@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// webmentionActions are the verbs shown for the properties of received
+// webmentions, other properties are mentions
+var webmentionActions = map[string]string{
+	"in-reply-to": "replied",
+	"like-of":     "liked",
+	"repost-of":   "reposted",
+	"bookmark-of": "bookmarked",
+	"mention-of":  "mentioned",
+}
+
+// Webmention is a webmention received by a post
+type Webmention struct {
+	Author      string
+	AuthorURL   string
+	AuthorPhoto string
+	URL         string
+	Published   string
+	Action      string
+	Content     string
+}
+
+// jf2Feed is the jf2 feed of received webmentions as returned by
+// webmention.io
+type jf2Feed struct {
+	Children []struct {
+		Author struct {
+			Name  string `json:"name"`
+			URL   string `json:"url"`
+			Photo string `json:"photo"`
+		} `json:"author"`
+		URL       string `json:"url"`
+		Published string `json:"published"`
+		Target    string `json:"wm-target"`
+		Property  string `json:"wm-property"`
+		Content   struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	} `json:"children"`
+}
+
+// readWebmentions reads the jf2 feed at path and returns the webmentions by
+// their target URL without trailing slash
+func readWebmentions(path string) (map[string][]*Webmention, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading webmentions %s: %v", path, err)
+	}
+	var feed jf2Feed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, fmt.Errorf("error parsing webmentions %s: %v", path, err)
+	}
+	result := map[string][]*Webmention{}
+	for _, child := range feed.Children {
+		action, ok := webmentionActions[child.Property]
+		if !ok {
+			action = "mentioned"
+		}
+		target := strings.TrimSuffix(child.Target, "/")
+		result[target] = append(result[target], &Webmention{
+			Author:      child.Author.Name,
+			AuthorURL:   child.Author.URL,
+			AuthorPhoto: child.Author.Photo,
+			URL:         child.URL,
+			Published:   child.Published,
+			Action:      action,
+			Content:     child.Content.Text,
+		})
+	}
+	return result, nil
+}
+
+// getPostWebmentions returns the webmentions targeting a post
+func getPostWebmentions(post *Post, webmentions map[string][]*Webmention) []*Webmention {
+	return webmentions[strings.TrimSuffix(post.URL, "/")]
+}
@@ -0,0 +1,35 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eleztian/blog-generator/config"
+)
+
+func TestWebmentionLink(t *testing.T) {
+	endpoint := "https://webmention.io/example.com/webmention"
+	pingback := "https://webmention.io/example.com/xmlrpc"
+	for _, enabled := range []bool{false, true} {
+		site := newTestSite(t, newTestConfig(t, func(cfg *config.Config) {
+			if enabled {
+				cfg.Blog.Webmention.Endpoint = endpoint
+				cfg.Blog.Webmention.Pingback = pingback
+			}
+		}), map[string]string{
+			"first/post.md": testPost("First Post", "01.02.2021", "The first post."),
+		}).build()
+		for _, page := range []string{"first/index.html", "blog/index.html"} {
+			html := site.read(page)
+			if got := strings.Contains(html, `<link rel="webmention" href="`+endpoint+`">`); got != enabled {
+				t.Errorf("endpoint %v: %s links the webmention endpoint %v", enabled, page, got)
+			}
+			if got := strings.Contains(html, `<link rel="pingback" href="`+pingback+`">`); got != enabled {
+				t.Errorf("endpoint %v: %s links the pingback endpoint %v", enabled, page, got)
+			}
+			if !enabled && strings.Contains(html, `rel="webmention"`) {
+				t.Errorf("%s has a webmention link without an endpoint", page)
+			}
+		}
+	}
+}
@@ -2,11 +2,18 @@ package main
 
 import (
 	"github.com/eleztian/blog-generator/cli"
+	"github.com/eleztian/blog-generator/generator"
 	"net/http"
+	"os"
 )
 
 func main() {
-	cli.Run()
+	if len(os.Args) > 1 && os.Args[1] == "new" {
+		cli.New(os.Args[2:])
+		return
+	}
+	site := cli.Run()
+	http.Handle(generator.DraftsPath, generator.NewDraftsHandler(site))
 	http.Handle("/", http.FileServer(http.Dir("www/")))
 	http.ListenAndServe(":9090", nil)
 }